@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/backtest"
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/stock"
+	"github.com/juanF18/EquiSignal-Backend/internal/application"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/db"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+// runBacktest mide el recomendador contra cotizaciones históricas (un CSV
+// date,open,high,low,close por ticker):
+//
+//	app backtest -prices ./prices -start 2025-01-01 -holding 30 -top 10
+func runBacktest(args []string) {
+	fs := flag.NewFlagSet("backtest", flag.ExitOnError)
+	pricesDir := fs.String("prices", "", "directorio con un CSV por ticker (AAPL.csv)")
+	start := fs.String("start", "", "primera fecha de rebalanceo (RFC3339 o 2006-01-02)")
+	end := fs.String("end", "", "última fecha con cotizaciones a usar (por defecto la última disponible)")
+	holding := fs.Int("holding", 30, "días de tenencia entre rebalanceos")
+	top := fs.Int("top", 10, "recomendaciones que se compran en cada rebalanceo")
+	mode := fs.String("mode", "", "modo del recomendador: event o ticker")
+	profile := fs.String("profile", "", "perfil de scoring (por defecto el default de SCORING_PROFILES_FILE)")
+	reportPath := fs.String("report", "", "ruta donde escribir el reporte JSON (por defecto stdout)")
+	fs.Parse(args)
+
+	if *pricesDir == "" || *start == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	req := application.BacktestRequest{HoldingDays: *holding, TopN: *top, Profile: *profile}
+	var err error
+	if req.Start, err = parseReplayDate(*start); err != nil {
+		log.Fatal("❌ -start: ", err)
+	}
+	if req.End, err = parseReplayDate(*end); err != nil {
+		log.Fatal("❌ -end: ", err)
+	}
+	if req.Mode, err = stock.ParseMode(*mode); err != nil {
+		log.Fatal("❌ -mode: ", err)
+	}
+
+	prices, err := backtest.LoadPricesDir(*pricesDir)
+	if err != nil {
+		log.Fatal("❌ Error leyendo cotizaciones: ", err)
+	}
+
+	cfg := bootstrap()
+	ensureSchema(cfg.DBAutoMigrate)
+
+	profiles, err := application.NewProfileStore(cfg.ScoringProfilesFile)
+	if err != nil {
+		log.Fatal("❌ Error cargando perfiles de scoring: ", err)
+	}
+	stockService := application.NewStockService(nil, repository.NewStockRepository(db.DB), nil, nil)
+	stockService.SetProfiles(profiles)
+	stockService.SetBrokerages(loadBrokerages())
+
+	report, err := stockService.Backtest(prices, req)
+	if err != nil {
+		log.Fatal("❌ Error en backtest: ", err)
+	}
+	writeJSONReport(report, *reportPath)
+	log.Printf("📈 Backtest de %d períodos: hit rate %.1f%%, retorno promedio %+.2f%% (baseline %+.2f%%), drawdown máximo %.1f%% (baseline %.1f%%)",
+		len(report.Periods), report.Strategy.HitRate*100,
+		report.Strategy.AvgForwardReturn*100, report.Baseline.AvgForwardReturn*100,
+		report.Strategy.MaxDrawdown*100, report.Baseline.MaxDrawdown*100)
+}
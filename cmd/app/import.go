@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/application"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/importer"
+)
+
+// runImport importa historial de ratings desde un archivo CSV o JSON-lines:
+//
+//	app import -file historial.csv -map "ticker=Symbol,company=Company Name" -report errores.json
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "archivo a importar (obligatorio)")
+	format := fs.String("format", "", "csv o jsonl (por defecto según la extensión)")
+	mapping := fs.String("map", "", "mapeo campo=columna separado por comas")
+	provider := fs.String("provider", application.DefaultImportProvider, "proveedor que se registra en cada evento")
+	reportPath := fs.String("report", "", "ruta donde escribir el reporte JSON (por defecto stdout)")
+	fs.Parse(args)
+
+	if *file == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	parsedFormat, err := importer.ParseFormat(*format, *file)
+	if err != nil {
+		log.Fatal("❌ ", err)
+	}
+	parsedMapping, err := importer.ParseColumnMapping(*mapping)
+	if err != nil {
+		log.Fatal("❌ ", err)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal("❌ ", err)
+	}
+	defer f.Close()
+
+	cfg := bootstrap()
+	ensureSchema(cfg.DBAutoMigrate)
+
+	ratings := loadRatings()
+	stockRepo := ingestionStockRepo(loadBrokerages(), ratings)
+	report, err := application.NewImportService(stockRepo, ratings).Import(context.Background(), f, application.ImportOptions{
+		Format:   parsedFormat,
+		Mapping:  parsedMapping,
+		Provider: *provider,
+		Source:   filepath.Base(*file),
+	})
+	if report != nil {
+		writeJSONReport(report, *reportPath)
+		log.Printf("📥 Importación: %d filas, %d insertadas, %d actualizadas, %d sin cambios, %d con error",
+			report.TotalRows, report.Inserted, report.Updated, report.Unchanged, report.Failed)
+	}
+	if err != nil {
+		log.Fatal("❌ Error importando: ", err)
+	}
+}
+
+// writeJSONReport escribe el reporte en un archivo o en stdout
+func writeJSONReport(report interface{}, path string) {
+	out := os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatal("❌ ", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Fatal("❌ ", err)
+	}
+}
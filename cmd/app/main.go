@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -11,12 +15,39 @@ import (
 	"github.com/juanF18/EquiSignal-Backend/internal/application"
 	"github.com/juanF18/EquiSignal-Backend/internal/config"
 	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/db"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
 	"github.com/juanF18/EquiSignal-Backend/internal/interface/external"
 	"github.com/juanF18/EquiSignal-Backend/internal/interface/handlers"
 	"github.com/juanF18/EquiSignal-Backend/internal/interface/http"
 )
 
+// Uso: app [serve|import|replay|backtest|migrate] [flags]. Sin subcomando arranca el servidor.
 func main() {
+	command := "serve"
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		runServer(args)
+	case "import":
+		runImport(args)
+	case "replay":
+		runReplay(args)
+	case "backtest":
+		runBacktest(args)
+	case "migrate":
+		runMigrate(args)
+	default:
+		log.Fatalf("❌ Subcomando desconocido %q (disponibles: serve, import, replay, backtest, migrate)", command)
+	}
+}
+
+// bootstrap carga la configuración y abre la conexión a la base de datos.
+// El esquema se revisa aparte con ensureSchema.
+func bootstrap() *config.Config {
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠️ No se encontró .env, usando variables del sistema")
 	}
@@ -25,6 +56,63 @@ func main() {
 
 	db.ConnectCockroachDB(cfg)
 
+	return cfg
+}
+
+// ensureSchema aplica las migraciones pendientes o, con autoMigrate=false,
+// se niega a continuar si falta alguna
+func ensureSchema(autoMigrate bool) {
+	if err := db.EnsureSchema(db.DB, autoMigrate); err != nil {
+		log.Fatal("❌ Esquema de base de datos: ", err)
+	}
+}
+
+// loadBrokerages carga la tabla de brokerages con la que se resuelven los
+// nombres crudos al ingerir eventos
+func loadBrokerages() *application.BrokerageService {
+	brokerages := application.NewBrokerageService(repository.NewBrokerageRepository(db.DB))
+	if err := brokerages.Load(); err != nil {
+		log.Fatal("❌ Error cargando brokerages: ", err)
+	}
+	return brokerages
+}
+
+// loadRatings carga el vocabulario de ratings con el que se validan y
+// traducen a la escala canónica los eventos al ingerirlos
+func loadRatings() *application.RatingService {
+	ratings := application.NewRatingService(repository.NewRatingRepository(db.DB))
+	if err := ratings.Load(); err != nil {
+		log.Fatal("❌ Error cargando el vocabulario de ratings: ", err)
+	}
+	return ratings
+}
+
+// ingestionStockRepo envuelve el repositorio de eventos para que cada upsert
+// guarde el brokerage y los ratings canónicos
+func ingestionStockRepo(brokerages *application.BrokerageService, ratings *application.RatingService) repository.StockRepository {
+	stocks := repository.NewStockRepository(db.DB)
+	return repository.WithRatingResolution(repository.WithBrokerageResolution(stocks, brokerages), ratings)
+}
+
+func runServer(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	migrate := fs.String("migrate", "", "auto (aplica migraciones pendientes) o refuse (no arranca si hay pendientes); por defecto según DB_AUTO_MIGRATE")
+	fs.Parse(args)
+
+	cfg := bootstrap()
+
+	autoMigrate := cfg.DBAutoMigrate
+	switch *migrate {
+	case "":
+	case "auto":
+		autoMigrate = true
+	case "refuse":
+		autoMigrate = false
+	default:
+		log.Fatalf("❌ -migrate=%q inválido (auto o refuse)", *migrate)
+	}
+	ensureSchema(autoMigrate)
+
 	r := gin.Default()
 
 	r.Use(cors.New(cors.Config{
@@ -40,12 +128,85 @@ func main() {
 	db.DB.Raw("SELECT NOW()").Scan(&now)
 	fmt.Println("⏰ DB Time:", now)
 
-	// API externa
-	externalAPI := external.NewExternalAPI(cfg)
-	stockService := application.NewStockService(externalAPI)
+	// Proveedores de ratings (API externa y adaptadores opcionales)
+	var archiver external.PayloadArchiver
+	if cfg.ArchiveRawPayloads {
+		archiver = application.NewPayloadArchive(repository.NewRawPayloadRepository(db.DB))
+	}
+	providers, err := external.BuildRegistry(cfg, archiver)
+	if err != nil {
+		log.Fatal("❌ Error configurando proveedores: ", err)
+	}
+	// Brokerages canónicos: cada upsert guarda el brokerage resuelto
+	brokerageService := loadBrokerages()
+	if updated, err := brokerageService.ResolveStocks(); err != nil {
+		log.Println("⚠️ Error resolviendo brokerages de los eventos:", err)
+	} else if updated > 0 {
+		log.Printf("🔗 %d eventos asignados a su brokerage canónico", updated)
+	}
+	// Vocabulario de ratings: cada upsert guarda los ratings canónicos
+	ratingService := loadRatings()
+	if updated, err := ratingService.ResolveStocks(); err != nil {
+		log.Println("⚠️ Error resolviendo ratings de los eventos:", err)
+	} else if updated > 0 {
+		log.Printf("🔗 %d ratings de eventos asignados a la escala canónica", updated)
+	}
+
+	stockRepo := ingestionStockRepo(brokerageService, ratingService)
+	syncStateRepo := repository.NewSyncStateRepository(db.DB)
+	quarantineRepo := repository.NewQuarantineRepository(db.DB)
+	stockService := application.NewStockService(providers, stockRepo, syncStateRepo, quarantineRepo)
+
+	// Perfiles de scoring del recomendador, recargados si cambia el archivo
+	profiles, err := application.NewProfileStore(cfg.ScoringProfilesFile)
+	if err != nil {
+		log.Fatal("❌ Error cargando perfiles de scoring: ", err)
+	}
+	profiles.Watch(context.Background(), cfg.ScoringProfilesReload)
+	stockService.SetProfiles(profiles)
+	stockService.SetBrokerages(brokerageService)
+	stockService.SetRatings(ratingService)
+	log.Printf("🎯 Perfiles de scoring: %s", strings.Join(profiles.Names(), ", "))
+
+	// Leaderboard de brokerages medido contra cotizaciones históricas; se
+	// calcula en segundo plano para no demorar el arranque
+	var leaderboardService *application.LeaderboardService
+	if cfg.LeaderboardPricesDir != "" {
+		leaderboardService = application.NewLeaderboardService(stockRepo, brokerageService, cfg.LeaderboardPricesDir)
+		leaderboardService.SetCurrency(cfg.LeaderboardPricesCurrency)
+		stockService.SetLeaderboard(leaderboardService)
+		go func() {
+			if _, err := leaderboardService.Refresh(); err != nil {
+				log.Println("⚠️ Error calculando el leaderboard de brokerages:", err)
+			}
+		}()
+	}
+
 	stockHandler := handlers.NewStockHandler(stockService)
 
-	http.SetupRoutes(r, stockHandler)
+	// Jobs de sincronización en segundo plano
+	syncJobService := application.NewSyncJobService(stockService, repository.NewSyncJobRepository(db.DB))
+	if err := syncJobService.RecoverInterrupted(); err != nil {
+		log.Println("⚠️ Error recuperando jobs interrumpidos:", err)
+	}
+	syncJobHandler := handlers.NewSyncJobHandler(syncJobService)
+
+	// Sincronizaciones periódicas dentro del proceso
+	sched := setupScheduler(cfg, syncJobService)
+	sched.Start(context.Background())
+	leaderboardSched := setupLeaderboardScheduler(cfg, leaderboardService)
+	leaderboardSched.Start(context.Background())
+
+	http.SetupRoutes(r, http.Handlers{
+		Stock:       stockHandler,
+		SyncJob:     syncJobHandler,
+		Schedule:    handlers.NewScheduleHandler(sched, leaderboardSched),
+		Import:      handlers.NewImportHandler(application.NewImportService(stockRepo, ratingService)),
+		Quarantine:  handlers.NewQuarantineHandler(application.NewQuarantineService(quarantineRepo, stockRepo, ratingService)),
+		Brokerage:   handlers.NewBrokerageHandler(brokerageService),
+		Rating:      handlers.NewRatingHandler(ratingService),
+		Leaderboard: handlers.NewLeaderboardHandler(leaderboardService),
+	})
 
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
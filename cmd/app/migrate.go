@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/db"
+)
+
+// runMigrate maneja el esquema con las migraciones embebidas:
+//
+//	app migrate status
+//	app migrate up
+//	app migrate down -steps 1
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "uso: app migrate up|down|status [-steps N]")
+		os.Exit(2)
+	}
+	action, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	steps := fs.Int("steps", 1, "cuántas migraciones revertir (solo down)")
+	fs.Parse(args)
+
+	bootstrap()
+	migrator, err := db.NewMigrator(db.DB)
+	if err != nil {
+		log.Fatal("❌ ", err)
+	}
+
+	switch action {
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatal("❌ ", err)
+		}
+		for _, s := range statuses {
+			state := "pendiente"
+			if s.Applied {
+				state = "aplicada " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, state)
+		}
+	case "up":
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			fmt.Println("⬆️", m)
+		}
+		if err != nil {
+			log.Fatal("❌ ", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("✅ El esquema ya está al día")
+		}
+	case "down":
+		if *steps <= 0 {
+			log.Fatal("❌ -steps debe ser mayor a 0")
+		}
+		reverted, err := migrator.Down(*steps)
+		for _, m := range reverted {
+			fmt.Println("⬇️", m)
+		}
+		if err != nil {
+			log.Fatal("❌ ", err)
+		}
+	default:
+		log.Fatalf("❌ Acción desconocida %q (up, down, status)", action)
+	}
+}
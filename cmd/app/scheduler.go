@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/application"
+	"github.com/juanF18/EquiSignal-Backend/internal/config"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/scheduler"
+)
+
+// setupScheduler registra las sincronizaciones periódicas definidas en la config
+func setupScheduler(cfg *config.Config, jobs *application.SyncJobService) *scheduler.Scheduler {
+	sched := scheduler.New(cfg.SyncScheduleJitter)
+
+	schedules := []struct {
+		name string
+		expr string
+		mode application.SyncMode
+	}{
+		{"sync-full", cfg.SyncScheduleFull, application.SyncModeFull},
+		{"sync-incremental", cfg.SyncScheduleIncremental, application.SyncModeIncremental},
+	}
+
+	for _, sc := range schedules {
+		if sc.expr == "" {
+			continue
+		}
+		if err := sched.Add(sc.name, sc.expr, syncTask(jobs, sc.mode)); err != nil {
+			log.Fatal("❌ Programación inválida: ", err)
+		}
+	}
+
+	return sched
+}
+
+// setupLeaderboardScheduler programa el recálculo del leaderboard de
+// brokerages. Va en un scheduler aparte porque el de sincronizaciones omite
+// cualquier disparo mientras otra tarea corre, y un recálculo lento haría
+// saltar sincronizaciones.
+func setupLeaderboardScheduler(cfg *config.Config, leaderboard *application.LeaderboardService) *scheduler.Scheduler {
+	sched := scheduler.New(cfg.SyncScheduleJitter)
+	if leaderboard != nil && cfg.LeaderboardSchedule != "" {
+		if err := sched.Add("brokerage-leaderboard", cfg.LeaderboardSchedule, leaderboardTask(leaderboard)); err != nil {
+			log.Fatal("❌ Programación inválida: ", err)
+		}
+	}
+	return sched
+}
+
+// leaderboardTask recalcula el ranking de brokerages con las cotizaciones y
+// los eventos del momento
+func leaderboardTask(leaderboard *application.LeaderboardService) scheduler.Task {
+	return func(ctx context.Context) error {
+		_, err := leaderboard.Refresh()
+		return err
+	}
+}
+
+// syncTask lanza un job de sincronización y espera a que termine
+func syncTask(jobs *application.SyncJobService, mode application.SyncMode) scheduler.Task {
+	return func(ctx context.Context) error {
+		job, err := jobs.RunAndWait(ctx, "", mode)
+		if errors.Is(err, application.ErrSyncInProgress) {
+			return fmt.Errorf("%w: %v", scheduler.ErrSkipped, err)
+		}
+		if err != nil {
+			return err
+		}
+		if job.Status != models.SyncJobSucceeded {
+			return fmt.Errorf("job %s terminó con estado %s: %s", job.ID, job.Status, job.Error)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,311 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/stock"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+// maxPriceGap es cuánto se acepta entre una fecha y la siguiente cotización
+// (fines de semana y feriados)
+const maxPriceGap = 7 * 24 * time.Hour
+
+// ErrInvalidConfig se devuelve cuando la configuración del backtest no es válida
+var ErrInvalidConfig = errors.New("configuración de backtest inválida")
+
+// Config configura un backtest del recomendador
+type Config struct {
+	// Start es la primera fecha de rebalanceo
+	Start time.Time
+	// End es la última fecha con cotizaciones a usar; cero usa la última disponible
+	End time.Time
+	// HoldingDays es el período de tenencia y también el intervalo entre rebalanceos
+	HoldingDays int
+	// TopN es cuántas recomendaciones se compran en cada rebalanceo
+	TopN             int
+	Mode             stock.Mode
+	Profile          *stock.Profile
+	BrokerageWeights map[string]float64
+}
+
+// Position es una compra al cierre del rebalanceo y su venta al cierre del
+// fin del período de tenencia
+type Position struct {
+	Ticker     string    `json:"ticker"`
+	Score      int       `json:"score,omitempty"`
+	EntryDate  time.Time `json:"entry_date"`
+	EntryPrice float64   `json:"entry_price"`
+	ExitDate   time.Time `json:"exit_date"`
+	ExitPrice  float64   `json:"exit_price"`
+	Return     float64   `json:"return"`
+}
+
+// Period es el resultado de un rebalanceo
+type Period struct {
+	Date     time.Time  `json:"date"`
+	ExitDate time.Time  `json:"exit_date"`
+	Picks    []Position `json:"picks"`
+	// Skipped son los tickers recomendados sin cotización en el período
+	Skipped []string `json:"skipped"`
+	// Return es el retorno promedio de Picks (0 si no hubo compras)
+	Return float64 `json:"return"`
+	// BaselineReturn es el retorno equiponderado de todos los tickers con
+	// ratings hasta Date y cotización en el período
+	BaselineReturn float64 `json:"baseline_return"`
+	BaselineSize   int     `json:"baseline_size"`
+}
+
+// Metrics resume una estrategia a lo largo de todos los períodos
+type Metrics struct {
+	Positions int `json:"positions"`
+	// HitRate es la fracción de posiciones con retorno positivo
+	HitRate float64 `json:"hit_rate"`
+	// AvgForwardReturn es el retorno promedio por posición
+	AvgForwardReturn float64 `json:"avg_forward_return"`
+	// CumulativeReturn compone los retornos de cada período
+	CumulativeReturn float64 `json:"cumulative_return"`
+	// MaxDrawdown es la mayor caída desde un máximo, con marcas diarias
+	MaxDrawdown float64 `json:"max_drawdown"`
+}
+
+// Report es el resultado de un backtest
+type Report struct {
+	Start       time.Time  `json:"start"`
+	End         time.Time  `json:"end"`
+	HoldingDays int        `json:"holding_days"`
+	TopN        int        `json:"top_n"`
+	Mode        stock.Mode `json:"mode"`
+	Profile     string     `json:"profile"`
+	Periods     []Period   `json:"periods"`
+	Strategy    Metrics    `json:"strategy"`
+	Baseline    Metrics    `json:"baseline"`
+	// ExcessReturn es CumulativeReturn de la estrategia menos el del baseline
+	ExcessReturn float64 `json:"excess_return"`
+}
+
+// Run re-ejecuta el recomendador en cada fecha de rebalanceo usando solo los
+// eventos publicados hasta esa fecha y midiendo la frescura contra ella, y
+// compara el top-N contra un portafolio equiponderado del mismo universo.
+func Run(events []models.Stock, prices Prices, cfg Config) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	end := cfg.End
+	if end.IsZero() {
+		end = prices.Last()
+	}
+	holding := time.Duration(cfg.HoldingDays) * 24 * time.Hour
+	if cfg.Start.Add(holding).After(end) {
+		return nil, fmt.Errorf("%w: no cabe un período de %d días entre %s y %s",
+			ErrInvalidConfig, cfg.HoldingDays, cfg.Start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	sorted := make([]models.Stock, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	profile := stock.DefaultProfileName
+	if cfg.Profile != nil {
+		profile = cfg.Profile.Name
+	}
+	mode := cfg.Mode
+	if mode == "" {
+		mode = stock.ModeEvent
+	}
+	report := &Report{
+		Start: cfg.Start, End: end, HoldingDays: cfg.HoldingDays, TopN: cfg.TopN,
+		Mode: mode, Profile: profile, Periods: []Period{},
+	}
+	strategy, baseline := newTracker(), newTracker()
+
+	for date := cfg.Start; !date.Add(holding).After(end); date = date.Add(holding) {
+		exit := date.Add(holding)
+		period := Period{Date: date, ExitDate: exit, Picks: []Position{}, Skipped: []string{}}
+
+		// Solo lo publicado hasta la fecha de rebalanceo
+		n := sort.Search(len(sorted), func(i int) bool { return sorted[i].Time.After(date) })
+		history := sorted[:n]
+
+		recs := stock.Recommend(history, stock.Options{
+			Limit:            cfg.TopN,
+			Mode:             mode,
+			Profile:          cfg.Profile,
+			BrokerageWeights: cfg.BrokerageWeights,
+			Clock:            stock.FixedClock(date),
+		})
+		for _, rec := range recs {
+			pos, err := openPosition(prices, rec.Ticker, date, exit)
+			if err != nil {
+				period.Skipped = append(period.Skipped, rec.Ticker)
+				continue
+			}
+			pos.Score = rec.Score
+			period.Picks = append(period.Picks, pos)
+		}
+
+		var universe []Position
+		for _, ticker := range tickers(history) {
+			if pos, err := openPosition(prices, ticker, date, exit); err == nil {
+				universe = append(universe, pos)
+			}
+		}
+
+		period.Return = strategy.hold(period.Picks, prices)
+		period.BaselineReturn = baseline.hold(universe, prices)
+		period.BaselineSize = len(universe)
+		report.Periods = append(report.Periods, period)
+	}
+
+	report.Strategy = strategy.metrics()
+	report.Baseline = baseline.metrics()
+	report.ExcessReturn = report.Strategy.CumulativeReturn - report.Baseline.CumulativeReturn
+	return report, nil
+}
+
+func (c Config) validate() error {
+	switch {
+	case c.Start.IsZero():
+		return fmt.Errorf("%w: falta la fecha de inicio", ErrInvalidConfig)
+	case c.HoldingDays <= 0:
+		return fmt.Errorf("%w: el período de tenencia debe ser mayor a 0 días", ErrInvalidConfig)
+	case c.TopN <= 0:
+		return fmt.Errorf("%w: top debe ser mayor a 0", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// openPosition compra al primer cierre desde date y vende al primer cierre desde exit
+func openPosition(prices Prices, ticker string, date, exit time.Time) (Position, error) {
+	series, ok := prices[ticker]
+	if !ok {
+		return Position{}, fmt.Errorf("%w para %s", ErrNoPrice, ticker)
+	}
+	entry, err := series.OnOrAfter(date, maxPriceGap)
+	if err != nil {
+		return Position{}, err
+	}
+	out, err := series.OnOrAfter(exit, maxPriceGap)
+	if err != nil {
+		return Position{}, err
+	}
+	if !out.Date.After(entry.Date) {
+		return Position{}, fmt.Errorf("%w: %s no cotiza entre %s y %s", ErrNoPrice, ticker, date.Format("2006-01-02"), exit.Format("2006-01-02"))
+	}
+	return Position{
+		Ticker:     ticker,
+		EntryDate:  entry.Date,
+		EntryPrice: entry.Close,
+		ExitDate:   out.Date,
+		ExitPrice:  out.Close,
+		Return:     out.Close/entry.Close - 1,
+	}, nil
+}
+
+// tickers lista los tickers distintos de events, ordenados
+func tickers(events []models.Stock) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, e := range events {
+		if !seen[e.Ticker] {
+			seen[e.Ticker] = true
+			result = append(result, e.Ticker)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// tracker acumula la curva de capital de una estrategia equiponderada
+type tracker struct {
+	equity      float64
+	peak        float64
+	maxDrawdown float64
+	positions   int
+	hits        int
+	sumReturns  float64
+}
+
+func newTracker() *tracker {
+	return &tracker{equity: 1, peak: 1}
+}
+
+// hold mantiene las posiciones durante el período, marcando el capital cada
+// día con cotización, y devuelve el retorno del período
+func (t *tracker) hold(positions []Position, prices Prices) float64 {
+	if len(positions) == 0 {
+		return 0
+	}
+
+	// Cotizaciones de cada posición dentro de su tenencia
+	bars := make([]Series, len(positions))
+	dates := make(map[time.Time]bool)
+	for i, p := range positions {
+		bars[i] = prices[p.Ticker].Between(p.EntryDate, p.ExitDate)
+		for _, b := range bars[i] {
+			dates[b.Date] = true
+		}
+	}
+	marks := make([]time.Time, 0, len(dates))
+	for d := range dates {
+		marks = append(marks, d)
+	}
+	sort.Slice(marks, func(i, j int) bool { return marks[i].Before(marks[j]) })
+
+	start := t.equity
+	next := make([]int, len(positions))
+	last := make([]float64, len(positions))
+	for i, p := range positions {
+		last[i] = p.EntryPrice
+	}
+	for _, d := range marks {
+		var value float64
+		for i, p := range positions {
+			for next[i] < len(bars[i]) && !bars[i][next[i]].Date.After(d) {
+				last[i] = bars[i][next[i]].Close
+				next[i]++
+			}
+			value += last[i] / p.EntryPrice
+		}
+		t.mark(start * value / float64(len(positions)))
+	}
+
+	var sum float64
+	for _, p := range positions {
+		sum += p.Return
+		t.positions++
+		if p.Return > 0 {
+			t.hits++
+		}
+	}
+	t.sumReturns += sum
+	periodReturn := sum / float64(len(positions))
+	t.mark(start * (1 + periodReturn))
+	return periodReturn
+}
+
+func (t *tracker) mark(equity float64) {
+	t.equity = equity
+	if equity > t.peak {
+		t.peak = equity
+	}
+	if dd := (t.peak - equity) / t.peak; dd > t.maxDrawdown {
+		t.maxDrawdown = dd
+	}
+}
+
+func (t *tracker) metrics() Metrics {
+	m := Metrics{
+		Positions:        t.positions,
+		CumulativeReturn: t.equity - 1,
+		MaxDrawdown:      t.maxDrawdown,
+	}
+	if t.positions > 0 {
+		m.HitRate = float64(t.hits) / float64(t.positions)
+		m.AvgForwardReturn = t.sumReturns / float64(t.positions)
+	}
+	return m
+}
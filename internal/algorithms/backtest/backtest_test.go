@@ -0,0 +1,120 @@
+package backtest
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+// linearSeries cotiza todos los días desde el 2025-01-01 con un precio que
+// cambia step por día
+func linearSeries(start, step float64, days int) Series {
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := make(Series, days)
+	for i := range series {
+		price := start + step*float64(i)
+		series[i] = Bar{Date: first.AddDate(0, 0, i), Open: price, High: price, Low: price, Close: price}
+	}
+	return series
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func backtestFixture() ([]models.Stock, Prices) {
+	dec30 := time.Date(2024, 12, 30, 15, 0, 0, 0, time.UTC)
+	jan15 := time.Date(2025, 1, 15, 15, 0, 0, 0, time.UTC)
+	events := []models.Stock{
+		{Ticker: "AAA", Company: "Alpha", Brokerage: "Goldman Sachs", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", Time: dec30},
+		{Ticker: "BBB", Company: "Beta", Brokerage: "UBS", Action: "downgraded by", RatingFrom: "Hold", RatingTo: "Sell", Time: dec30},
+		// Publicado después del primer rebalanceo: no puede influir en él
+		{Ticker: "DDD", Company: "Delta", Brokerage: "Goldman Sachs", Action: "upgraded by", RatingFrom: "Sell", RatingTo: "Strong Buy", Time: jan15},
+	}
+	prices := Prices{
+		"AAA": linearSeries(100, 1, 90),
+		"BBB": linearSeries(100, -1.5, 90),
+		"DDD": linearSeries(20, 0, 90),
+	}
+	return events, prices
+}
+
+func TestRun(t *testing.T) {
+	events, prices := backtestFixture()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	report, err := Run(events, prices, Config{Start: start, HoldingDays: 30, TopN: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 2025-01-01 y 2025-01-31; el tercero terminaría después de la última cotización
+	if len(report.Periods) != 2 {
+		t.Fatalf("Expected 2 periods, got %d", len(report.Periods))
+	}
+
+	first := report.Periods[0]
+	if len(first.Picks) != 1 || first.Picks[0].Ticker != "AAA" || !approx(first.Picks[0].Return, 0.30) {
+		t.Errorf("Expected AAA +30%% in the first period, got %+v", first.Picks)
+	}
+	// Baseline: AAA +30% y BBB -45%; DDD todavía no tenía ratings
+	if first.BaselineSize != 2 || !approx(first.BaselineReturn, -0.075) {
+		t.Errorf("Expected baseline of AAA and BBB, got %d %.4f", first.BaselineSize, first.BaselineReturn)
+	}
+
+	second := report.Periods[1]
+	if len(second.Picks) != 1 || second.Picks[0].Ticker != "DDD" || second.Picks[0].Return != 0 {
+		t.Errorf("Expected DDD flat in the second period, got %+v", second.Picks)
+	}
+
+	s := report.Strategy
+	if s.Positions != 2 || s.HitRate != 0.5 || !approx(s.AvgForwardReturn, 0.15) || !approx(s.CumulativeReturn, 0.30) {
+		t.Errorf("Unexpected strategy metrics: %+v", s)
+	}
+	if s.MaxDrawdown != 0 {
+		t.Errorf("Expected no drawdown for a rising pick, got %.4f", s.MaxDrawdown)
+	}
+
+	b := report.Baseline
+	if b.Positions != 5 || !approx(b.HitRate, 0.4) || b.MaxDrawdown <= 0 {
+		t.Errorf("Unexpected baseline metrics: %+v", b)
+	}
+	if !approx(report.ExcessReturn, s.CumulativeReturn-b.CumulativeReturn) {
+		t.Errorf("Unexpected excess return %.4f", report.ExcessReturn)
+	}
+}
+
+func TestRun_SkipsTickersWithoutPrices(t *testing.T) {
+	events, prices := backtestFixture()
+	delete(prices, "AAA")
+
+	report, err := Run(events, prices, Config{Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), HoldingDays: 30, TopN: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	first := report.Periods[0]
+	if len(first.Picks) != 0 || len(first.Skipped) != 1 || first.Skipped[0] != "AAA" || first.Return != 0 {
+		t.Errorf("Expected AAA skipped and no return, got %+v", first)
+	}
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	events, prices := backtestFixture()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for name, cfg := range map[string]Config{
+		"Missing start":    {HoldingDays: 30, TopN: 1},
+		"Zero holding":     {Start: start, TopN: 1},
+		"Zero top":         {Start: start, HoldingDays: 30},
+		"Holding too long": {Start: start, HoldingDays: 365, TopN: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Run(events, prices, cfg); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("Expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package backtest
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+// DefaultHorizons son los días después del evento en los que se mide si un
+// upgrade acertó
+var DefaultHorizons = []int{30, 90}
+
+const (
+	// DefaultMinSamples es cuántos upgrades o precios objetivo medidos hacen
+	// falta para que una métrica cuente en el peso
+	DefaultMinSamples = 5
+	// DefaultPriceCurrency es la moneda de las cotizaciones si no se configura otra
+	DefaultPriceCurrency = "USD"
+	// minMeasuredWeight evita que un mal historial anule al brokerage
+	minMeasuredWeight = 0.1
+)
+
+// LeaderboardConfig configura la medición del historial de los brokerages
+type LeaderboardConfig struct {
+	// Horizons son los días de tenencia de los upgrades; el último es también
+	// el horizonte contra el que se comparan los precios objetivo
+	Horizons   []int
+	MinSamples int
+	// Currency es la moneda de las cotizaciones; solo se comparan los precios
+	// objetivo en esa moneda
+	Currency string
+}
+
+// HorizonStats resume los upgrades de un brokerage a un horizonte
+type HorizonStats struct {
+	Days int `json:"days"`
+	// Upgrades son los upgrades con cotización al entrar y al final del horizonte
+	Upgrades int `json:"upgrades"`
+	// HitRate es la fracción de esos upgrades con retorno positivo
+	HitRate   float64 `json:"hit_rate"`
+	AvgReturn float64 `json:"avg_return"`
+}
+
+// BrokerageRecord es el historial medido de un brokerage
+type BrokerageRecord struct {
+	Brokerage string         `json:"brokerage"`
+	Upgrades  []HorizonStats `json:"upgrades"`
+	// Targets son los precios objetivo con cotización al final del horizonte
+	Targets int `json:"targets"`
+	// SkippedTargets son los precios objetivo en otra moneda que las
+	// cotizaciones, que no se pueden comparar
+	SkippedTargets int `json:"skipped_targets"`
+	// TargetError es el error absoluto promedio |cierre - objetivo| / objetivo
+	TargetError float64 `json:"target_error"`
+	// TargetAccuracy es 1 - TargetError, sin bajar de 0
+	TargetAccuracy float64 `json:"target_accuracy"`
+	// Score promedia los hit rates y la precisión de objetivos con muestras suficientes
+	Score float64 `json:"score"`
+	// Weight es la credibilidad medida para el recomendador; nil si ninguna
+	// métrica tiene muestras suficientes
+	Weight *float64 `json:"weight"`
+}
+
+// Leaderboard es el ranking de brokerages por su historial real
+type Leaderboard struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	// PricesUntil es la última cotización disponible al medir
+	PricesUntil time.Time `json:"prices_until"`
+	// Currency es la moneda de las cotizaciones contra la que se midieron los objetivos
+	Currency   string            `json:"currency"`
+	Horizons   []int             `json:"horizons"`
+	MinSamples int               `json:"min_samples"`
+	Brokerages []BrokerageRecord `json:"brokerages"`
+}
+
+// Weights devuelve el peso medido por brokerage, solo de los que tienen uno
+func (l *Leaderboard) Weights() map[string]float64 {
+	weights := make(map[string]float64)
+	for _, r := range l.Brokerages {
+		if r.Weight != nil {
+			weights[r.Brokerage] = *r.Weight
+		}
+	}
+	return weights
+}
+
+// BuildLeaderboard mide a cada brokerage (por events[i].Brokerage) contra
+// las cotizaciones: qué tan seguido sus upgrades subieron en cada horizonte
+// y qué tan cerca quedaron sus precios objetivo del cierre al último horizonte.
+// Los precios objetivo en otra moneda que cfg.Currency se cuentan aparte sin
+// medirse. Se entra al primer cierre después del evento para no usar precios que el
+// mercado ya tenía antes de publicarse.
+func BuildLeaderboard(events []models.Stock, prices Prices, cfg LeaderboardConfig) *Leaderboard {
+	if len(cfg.Horizons) == 0 {
+		cfg.Horizons = DefaultHorizons
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = DefaultMinSamples
+	}
+	if cfg.Currency == "" {
+		cfg.Currency = DefaultPriceCurrency
+	}
+	targetHorizon := cfg.Horizons[len(cfg.Horizons)-1]
+
+	type tally struct {
+		upgrades   []int
+		hits       []int
+		returns    []float64
+		targets    int
+		skipped    int
+		targetErrs float64
+	}
+	tallies := make(map[string]*tally)
+	get := func(brokerage string) *tally {
+		t, ok := tallies[brokerage]
+		if !ok {
+			t = &tally{
+				upgrades: make([]int, len(cfg.Horizons)),
+				hits:     make([]int, len(cfg.Horizons)),
+				returns:  make([]float64, len(cfg.Horizons)),
+			}
+			tallies[brokerage] = t
+		}
+		return t
+	}
+
+	for _, e := range events {
+		series, ok := prices[strings.ToUpper(e.Ticker)]
+		if !ok || e.Brokerage == "" {
+			continue
+		}
+		entry, err := series.OnOrAfter(e.Time, maxPriceGap)
+		if err != nil {
+			continue
+		}
+
+		t := get(e.Brokerage)
+		if isUpgrade(e) {
+			for i, days := range cfg.Horizons {
+				exit, err := series.OnOrAfter(entry.Date.AddDate(0, 0, days), maxPriceGap)
+				if err != nil {
+					continue
+				}
+				ret := exit.Close/entry.Close - 1
+				t.upgrades[i]++
+				t.returns[i] += ret
+				if ret > 0 {
+					t.hits[i]++
+				}
+			}
+		}
+
+		switch {
+		case e.TargetToValue == nil || *e.TargetToValue <= 0:
+			// sin precio objetivo no hay nada que medir
+		case e.TargetCurrency != cfg.Currency:
+			// Un objetivo en euros no se puede comparar con cierres en dólares
+			t.skipped++
+		default:
+			exit, err := series.OnOrAfter(entry.Date.AddDate(0, 0, targetHorizon), maxPriceGap)
+			if err == nil {
+				target := e.TargetToValue.Float64()
+				t.targets++
+				t.targetErrs += math.Abs(exit.Close-target) / target
+			}
+		}
+	}
+
+	board := &Leaderboard{
+		PricesUntil: prices.Last(),
+		Currency:    cfg.Currency,
+		Horizons:    cfg.Horizons,
+		MinSamples:  cfg.MinSamples,
+		Brokerages:  []BrokerageRecord{},
+	}
+	for name, t := range tallies {
+		record := BrokerageRecord{Brokerage: name, Upgrades: make([]HorizonStats, len(cfg.Horizons)), Targets: t.targets, SkippedTargets: t.skipped}
+		var components []float64
+		for i, days := range cfg.Horizons {
+			h := HorizonStats{Days: days, Upgrades: t.upgrades[i]}
+			if h.Upgrades > 0 {
+				h.HitRate = float64(t.hits[i]) / float64(h.Upgrades)
+				h.AvgReturn = t.returns[i] / float64(h.Upgrades)
+			}
+			if h.Upgrades >= cfg.MinSamples {
+				components = append(components, h.HitRate)
+			}
+			record.Upgrades[i] = h
+		}
+		if t.targets > 0 {
+			record.TargetError = t.targetErrs / float64(t.targets)
+			record.TargetAccuracy = math.Max(0, 1-record.TargetError)
+		}
+		if t.targets >= cfg.MinSamples {
+			components = append(components, record.TargetAccuracy)
+		}
+
+		if len(components) > 0 {
+			var sum float64
+			for _, c := range components {
+				sum += c
+			}
+			record.Score = sum / float64(len(components))
+			weight := math.Round(math.Max(minMeasuredWeight, record.Score)*100) / 100
+			record.Weight = &weight
+		}
+		board.Brokerages = append(board.Brokerages, record)
+	}
+
+	sortLeaderboard(board.Brokerages)
+	return board
+}
+
+// sortLeaderboard ordena primero los que tienen peso medido, por score
+// descendente, y luego por cantidad de muestras y nombre
+func sortLeaderboard(records []BrokerageRecord) {
+	samples := func(r BrokerageRecord) int {
+		n := r.Targets
+		for _, h := range r.Upgrades {
+			n += h.Upgrades
+		}
+		return n
+	}
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if (a.Weight != nil) != (b.Weight != nil) {
+			return a.Weight != nil
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if sa, sb := samples(a), samples(b); sa != sb {
+			return sa > sb
+		}
+		return a.Brokerage < b.Brokerage
+	})
+}
+
+// isUpgrade compara los ratings en la escala canónica; si alguno no está en
+// el vocabulario se guía por la acción ("upgraded by")
+func isUpgrade(e models.Stock) bool {
+	from, to := ratingRank(e.CanonicalRatingFrom, e.RatingFrom), ratingRank(e.CanonicalRatingTo, e.RatingTo)
+	if from >= 0 && to >= 0 {
+		return to < from
+	}
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(e.Action)), "upgrade")
+}
+
+// ratingRank es la posición del rating en models.CanonicalRatings (0 es el
+// mejor), usando el guardado al ingerir o el del vocabulario de fábrica; -1
+// si no se conoce
+func ratingRank(canonical, label string) int {
+	if canonical == "" {
+		canonical = models.DefaultCanonicalRating(label)
+	}
+	for i, r := range models.CanonicalRatings {
+		if r == canonical {
+			return i
+		}
+	}
+	return -1
+}
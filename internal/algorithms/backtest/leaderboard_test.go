@@ -0,0 +1,133 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+func leaderboardFixture() ([]models.Stock, Prices) {
+	jan1 := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+	target := func(s string) *models.Decimal {
+		price, _ := models.ParseTargetPrice(s)
+		return &price.Amount
+	}
+	events := []models.Stock{
+		// Entra al cierre del 2025-01-02 (101) y el objetivo se cumple justo a los 90 días
+		{Ticker: "UP", Brokerage: "Goldman Sachs", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", TargetToValue: target("$191.00"), TargetCurrency: "USD", Time: jan1},
+		// Entra a 99.75 y a los 90 días cierra en 77.25, lejos del objetivo
+		{Ticker: "DOWN", Brokerage: "UBS", Action: "upgraded by", RatingFrom: "Sell", RatingTo: "Hold", TargetToValue: target("$150.00"), TargetCurrency: "USD", Time: jan1},
+		// Un downgrade no cuenta como upgrade
+		{Ticker: "UP", Brokerage: "UBS", Action: "downgraded by", RatingFrom: "Buy", RatingTo: "Hold", Time: jan1},
+		// Sin cotización o sin horizonte completo no se mide
+		{Ticker: "NOPE", Brokerage: "Citi", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", Time: jan1},
+		{Ticker: "UP", Brokerage: "Citi", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", Time: jan1.AddDate(0, 6, 5)},
+	}
+	prices := Prices{
+		"UP":   linearSeries(100, 1, 200),
+		"DOWN": linearSeries(100, -0.25, 200),
+	}
+	return events, prices
+}
+
+func TestBuildLeaderboard(t *testing.T) {
+	events, prices := leaderboardFixture()
+
+	board := BuildLeaderboard(events, prices, LeaderboardConfig{MinSamples: 1})
+
+	if len(board.Horizons) != 2 || board.Horizons[0] != 30 || board.Horizons[1] != 90 {
+		t.Errorf("Expected default horizons, got %v", board.Horizons)
+	}
+	if len(board.Brokerages) != 3 {
+		t.Fatalf("Expected 3 brokerages, got %+v", board.Brokerages)
+	}
+
+	goldman, ubs, citi := board.Brokerages[0], board.Brokerages[1], board.Brokerages[2]
+	if goldman.Brokerage != "Goldman Sachs" || ubs.Brokerage != "UBS" || citi.Brokerage != "Citi" {
+		t.Fatalf("Unexpected order: %s, %s, %s", goldman.Brokerage, ubs.Brokerage, citi.Brokerage)
+	}
+
+	if h := goldman.Upgrades[0]; h.Days != 30 || h.Upgrades != 1 || h.HitRate != 1 || !approx(h.AvgReturn, 30.0/101) {
+		t.Errorf("Unexpected Goldman 30d stats: %+v", h)
+	}
+	if goldman.Targets != 1 || !approx(goldman.TargetAccuracy, 1) || goldman.Weight == nil || *goldman.Weight != 1 {
+		t.Errorf("Unexpected Goldman record: %+v", goldman)
+	}
+
+	if h := ubs.Upgrades[1]; h.Upgrades != 1 || h.HitRate != 0 {
+		t.Errorf("Expected UBS upgrade to miss at 90d, got %+v", h)
+	}
+	if !approx(ubs.TargetError, 0.485) || !approx(ubs.Score, 0.515/3) || ubs.Weight == nil || *ubs.Weight != 0.17 {
+		t.Errorf("Unexpected UBS record: %+v", ubs)
+	}
+
+	// Citi no tiene ningún upgrade medible
+	if citi.Upgrades[0].Upgrades != 0 || citi.Weight != nil {
+		t.Errorf("Expected Citi without measurements, got %+v", citi)
+	}
+
+	weights := board.Weights()
+	if len(weights) != 2 || weights["Goldman Sachs"] != 1 || weights["UBS"] != 0.17 {
+		t.Errorf("Unexpected weights: %v", weights)
+	}
+}
+
+func TestBuildLeaderboard_MinSamples(t *testing.T) {
+	events, prices := leaderboardFixture()
+
+	board := BuildLeaderboard(events, prices, LeaderboardConfig{MinSamples: 2})
+
+	if weights := board.Weights(); len(weights) != 0 {
+		t.Errorf("Expected no weights with too few samples, got %v", weights)
+	}
+	// Las métricas se reportan igual aunque no alcancen para un peso
+	if first := board.Brokerages[0]; first.Brokerage != "Goldman Sachs" || first.Upgrades[0].Upgrades != 1 || first.Weight != nil {
+		t.Errorf("Expected Goldman metrics without weight, got %+v", first)
+	}
+}
+
+func TestBuildLeaderboard_TargetCurrency(t *testing.T) {
+	events, prices := leaderboardFixture()
+	eur, _ := models.ParseTargetPrice("€500,00")
+	// Un objetivo en euros contra cierres en dólares no se mide
+	events = append(events, models.Stock{Ticker: "UP", Brokerage: "Goldman Sachs", Action: "reiterated by", RatingFrom: "Buy", RatingTo: "Buy",
+		TargetToValue: &eur.Amount, TargetCurrency: "EUR", Time: time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)})
+
+	board := BuildLeaderboard(events, prices, LeaderboardConfig{MinSamples: 1})
+	goldman := board.Brokerages[0]
+	if board.Currency != "USD" || goldman.Brokerage != "Goldman Sachs" {
+		t.Fatalf("Unexpected board: %s %+v", board.Currency, goldman)
+	}
+	if goldman.Targets != 1 || goldman.SkippedTargets != 1 || !approx(goldman.TargetAccuracy, 1) {
+		t.Errorf("Expected the EUR target to be skipped, got %+v", goldman)
+	}
+
+	// Con cotizaciones en euros se miden solo los objetivos en euros
+	board = BuildLeaderboard(events, prices, LeaderboardConfig{MinSamples: 1, Currency: "EUR"})
+	for _, r := range board.Brokerages {
+		if r.Brokerage == "Goldman Sachs" && (r.Targets != 1 || r.SkippedTargets != 1) {
+			t.Errorf("Expected only the EUR target to be measured, got %+v", r)
+		}
+	}
+}
+
+func TestIsUpgrade(t *testing.T) {
+	tests := []struct {
+		stock    models.Stock
+		expected bool
+	}{
+		{models.Stock{RatingFrom: "Hold", RatingTo: "Buy"}, true},
+		{models.Stock{RatingFrom: "Buy", RatingTo: "Buy", Action: "upgraded by"}, false},
+		{models.Stock{RatingFrom: "Equal-Weight", RatingTo: "Overweight"}, true},
+		{models.Stock{RatingFrom: "Meh", RatingTo: "Great", Action: "upgraded by"}, true},
+		{models.Stock{RatingFrom: "Meh", RatingTo: "Great", Action: "target raised by"}, false},
+		{models.Stock{RatingFrom: "x", RatingTo: "y", CanonicalRatingFrom: models.RatingSell, CanonicalRatingTo: models.RatingHold}, true},
+	}
+
+	for _, tt := range tests {
+		if got := isUpgrade(tt.stock); got != tt.expected {
+			t.Errorf("isUpgrade(%s -> %s, %q) = %v; expected %v", tt.stock.RatingFrom, tt.stock.RatingTo, tt.stock.Action, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bar es la cotización diaria de un ticker
+type Bar struct {
+	Date  time.Time `json:"date"`
+	Open  float64   `json:"open"`
+	High  float64   `json:"high"`
+	Low   float64   `json:"low"`
+	Close float64   `json:"close"`
+}
+
+// Series son las cotizaciones de un ticker ordenadas por fecha
+type Series []Bar
+
+// Prices agrupa las series por ticker (en mayúsculas)
+type Prices map[string]Series
+
+// ErrNoPrice se devuelve cuando un ticker no tiene cotización para la fecha pedida
+var ErrNoPrice = errors.New("sin cotización")
+
+// priceColumns son las columnas obligatorias del CSV; las demás (volume,
+// adj close) se ignoran
+var priceColumns = []string{"date", "open", "high", "low", "close"}
+
+// ReadSeries lee un CSV con encabezado date,open,high,low,close. Las fechas
+// van como 2006-01-02 o RFC3339; el orden de las filas no importa.
+func ReadSeries(r io.Reader) (Series, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("leyendo encabezado: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, col := range priceColumns {
+		if _, ok := index[col]; !ok {
+			return nil, fmt.Errorf("falta la columna %q", col)
+		}
+	}
+
+	var series Series
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("línea %d: %w", line, err)
+		}
+
+		bar, err := parseBar(record, index)
+		if err != nil {
+			return nil, fmt.Errorf("línea %d: %w", line, err)
+		}
+		series = append(series, bar)
+	}
+
+	sort.Slice(series, func(i, j int) bool { return series[i].Date.Before(series[j].Date) })
+	for i := 1; i < len(series); i++ {
+		if series[i].Date.Equal(series[i-1].Date) {
+			return nil, fmt.Errorf("fecha repetida %s", series[i].Date.Format("2006-01-02"))
+		}
+	}
+	return series, nil
+}
+
+func parseBar(record []string, index map[string]int) (Bar, error) {
+	field := func(name string) string {
+		i := index[name]
+		if i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var bar Bar
+	date, err := parseDate(field("date"))
+	if err != nil {
+		return bar, err
+	}
+	bar.Date = date
+
+	values := []*float64{&bar.Open, &bar.High, &bar.Low, &bar.Close}
+	for i, col := range priceColumns[1:] {
+		v, err := strconv.ParseFloat(field(col), 64)
+		if err != nil || v <= 0 {
+			return bar, fmt.Errorf("%s %q no es un precio válido", col, field(col))
+		}
+		*values[i] = v
+	}
+	return bar, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("fecha %q inválida (usa 2006-01-02 o RFC3339)", s)
+}
+
+// LoadPricesDir lee un CSV por ticker desde dir; el nombre del archivo es
+// el ticker (AAPL.csv, brk.b.csv)
+func LoadPricesDir(dir string) (Prices, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no hay archivos .csv en %s", dir)
+	}
+
+	prices := make(Prices, len(files))
+	for _, path := range files {
+		ticker := strings.ToUpper(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+		series, err := readSeriesFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		prices[ticker] = series
+	}
+	return prices, nil
+}
+
+func readSeriesFile(path string) (Series, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadSeries(f)
+}
+
+// OnOrAfter devuelve la primera cotización desde t; con maxGap > 0 no
+// acepta una cotización más de maxGap después de t
+func (s Series) OnOrAfter(t time.Time, maxGap time.Duration) (Bar, error) {
+	i := sort.Search(len(s), func(i int) bool { return !s[i].Date.Before(t) })
+	if i == len(s) || (maxGap > 0 && s[i].Date.Sub(t) > maxGap) {
+		return Bar{}, fmt.Errorf("%w desde %s", ErrNoPrice, t.Format("2006-01-02"))
+	}
+	return s[i], nil
+}
+
+// Between devuelve las cotizaciones con fecha en (from, to]
+func (s Series) Between(from, to time.Time) Series {
+	start := sort.Search(len(s), func(i int) bool { return s[i].Date.After(from) })
+	end := sort.Search(len(s), func(i int) bool { return s[i].Date.After(to) })
+	return s[start:end]
+}
+
+// Last devuelve la fecha de la última cotización de todas las series
+func (p Prices) Last() time.Time {
+	var last time.Time
+	for _, s := range p {
+		if len(s) > 0 && s[len(s)-1].Date.After(last) {
+			last = s[len(s)-1].Date
+		}
+	}
+	return last
+}
@@ -0,0 +1,77 @@
+package backtest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadSeries(t *testing.T) {
+	csv := `Date,Open,High,Low,Close,Volume
+2025-01-03,11,12,10,11.5,1000
+2025-01-02,10,11,9,10.5,1200
+`
+	series, err := ReadSeries(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(series) != 2 || series[0].Close != 10.5 || series[1].Close != 11.5 {
+		t.Errorf("Expected bars sorted by date, got %+v", series)
+	}
+}
+
+func TestReadSeries_Invalid(t *testing.T) {
+	testCases := map[string]string{
+		"Missing column": "date,open,high,low\n2025-01-02,1,1,1\n",
+		"Bad date":       "date,open,high,low,close\n02/01/2025,1,1,1,1\n",
+		"Bad price":      "date,open,high,low,close\n2025-01-02,1,1,1,N/A\n",
+		"Repeated date":  "date,open,high,low,close\n2025-01-02,1,1,1,1\n2025-01-02,1,1,1,1\n",
+	}
+
+	for name, csv := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadSeries(strings.NewReader(csv)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestLoadPricesDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "brk.b.csv"), []byte("date,open,high,low,close\n2025-01-02,1,1,1,1\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644)
+
+	prices, err := LoadPricesDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prices) != 1 || len(prices["BRK.B"]) != 1 {
+		t.Errorf("Expected BRK.B series, got %v", prices)
+	}
+
+	if _, err := LoadPricesDir(t.TempDir()); err == nil {
+		t.Error("Expected error for a directory without CSV files")
+	}
+}
+
+func TestSeries_OnOrAfter(t *testing.T) {
+	friday := time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)
+	monday := friday.AddDate(0, 0, 3)
+	series := Series{{Date: friday, Close: 1}, {Date: monday, Close: 2}}
+
+	// El sábado toma la cotización del lunes
+	bar, err := series.OnOrAfter(friday.AddDate(0, 0, 1), maxPriceGap)
+	if err != nil || bar.Close != 2 {
+		t.Errorf("Expected Monday bar, got %+v %v", bar, err)
+	}
+	if _, err := series.OnOrAfter(monday.AddDate(0, 0, 1), maxPriceGap); !errors.Is(err, ErrNoPrice) {
+		t.Errorf("Expected ErrNoPrice after the last bar, got %v", err)
+	}
+	if got := series.Between(friday, monday); len(got) != 1 || got[0].Close != 2 {
+		t.Errorf("Expected (friday, monday] to hold only Monday, got %+v", got)
+	}
+}
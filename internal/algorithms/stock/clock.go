@@ -0,0 +1,31 @@
+package stock
+
+import (
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+// Clock da el momento contra el que el recomendador mide la frescura de los
+// eventos; inyectarlo hace que el mismo historial dé siempre el mismo score
+type Clock func() time.Time
+
+// SystemClock es la hora real
+var SystemClock Clock = time.Now
+
+// FixedClock devuelve siempre t; sirve para recomendar "as of" una fecha,
+// para backtests y para tests
+func FixedClock(t time.Time) Clock {
+	return func() time.Time { return t }
+}
+
+// PublishedBy devuelve los eventos publicados hasta t inclusive, en el mismo orden
+func PublishedBy(stocks []models.Stock, t time.Time) []models.Stock {
+	published := make([]models.Stock, 0, len(stocks))
+	for _, st := range stocks {
+		if !st.Time.After(t) {
+			published = append(published, st)
+		}
+	}
+	return published
+}
@@ -0,0 +1,225 @@
+package stock
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// DefaultProfileName es el nombre del perfil con los valores de fábrica
+const DefaultProfileName = "default"
+
+// weightTolerance es el margen aceptado al validar que los pesos sumen 1
+const weightTolerance = 1e-6
+
+// ErrInvalidProfile se devuelve cuando un perfil de scoring no pasa la validación
+var ErrInvalidProfile = errors.New("perfil de scoring inválido")
+
+// Profile agrupa los pesos y umbrales del recomendador. Los perfiles se
+// cargan de un archivo YAML o JSON (ver application.ProfileStore).
+type Profile struct {
+	Name string `yaml:"name" json:"name"`
+	// Weights son los pesos de ModeEvent; deben sumar 1
+	Weights EventWeights `yaml:"weights" json:"weights"`
+	// TickerWeights son los pesos de ModeTicker; deben sumar 1
+	TickerWeights TickerWeights `yaml:"ticker_weights" json:"ticker_weights"`
+	// TargetBuckets van de mayor a menor variación; el último no lleva
+	// min_change y recoge todo lo demás
+	TargetBuckets []TargetBucket `yaml:"target_buckets" json:"target_buckets"`
+	// FreshnessBuckets van del más nuevo al más viejo; el último no lleva
+	// max_days y recoge todo lo demás
+	FreshnessBuckets []FreshnessBucket `yaml:"freshness_buckets" json:"freshness_buckets"`
+	// HighProfileKeywords dan bonificación si aparecen en el nombre de la compañía
+	HighProfileKeywords []string `yaml:"high_profile_keywords" json:"high_profile_keywords"`
+}
+
+// EventWeights son los pesos de cada criterio en ModeEvent
+type EventWeights struct {
+	Rating    float64 `yaml:"rating" json:"rating"`
+	Target    float64 `yaml:"target" json:"target"`
+	Temporal  float64 `yaml:"temporal" json:"temporal"`
+	Brokerage float64 `yaml:"brokerage" json:"brokerage"`
+	Consensus float64 `yaml:"consensus" json:"consensus"`
+}
+
+// TickerWeights son los pesos de cada criterio en ModeTicker
+type TickerWeights struct {
+	Rating     float64 `yaml:"rating" json:"rating"`
+	Target     float64 `yaml:"target" json:"target"`
+	Temporal   float64 `yaml:"temporal" json:"temporal"`
+	Brokerage  float64 `yaml:"brokerage" json:"brokerage"`
+	Dispersion float64 `yaml:"dispersion" json:"dispersion"`
+}
+
+// TargetBucket asigna un puntaje a las variaciones de precio objetivo >= MinChange (%)
+type TargetBucket struct {
+	MinChange *float64 `yaml:"min_change" json:"min_change"`
+	Score     float64  `yaml:"score" json:"score"`
+	Label     string   `yaml:"label" json:"label"`
+	// Code es el código del motivo; vacío usa "target_" más la etiqueta
+	Code string `yaml:"code" json:"code"`
+}
+
+// FreshnessBucket asigna un puntaje a los eventos de hace MaxDays días o menos
+type FreshnessBucket struct {
+	MaxDays *float64 `yaml:"max_days" json:"max_days"`
+	Score   float64  `yaml:"score" json:"score"`
+	Label   string   `yaml:"label" json:"label"`
+	// Range describe el rango en el motivo; vacío muestra los días del evento
+	Range string `yaml:"range" json:"range"`
+	// Code es el código del motivo; vacío usa "freshness_" más la etiqueta
+	Code string `yaml:"code" json:"code"`
+}
+
+// DefaultProfile devuelve los valores con los que se diseñó el recomendador
+func DefaultProfile() Profile {
+	return Profile{
+		Name:          DefaultProfileName,
+		Weights:       EventWeights{Rating: 0.35, Target: 0.25, Temporal: 0.20, Brokerage: 0.10, Consensus: 0.10},
+		TickerWeights: TickerWeights{Rating: 0.35, Target: 0.25, Temporal: 0.15, Brokerage: 0.10, Dispersion: 0.15},
+		TargetBuckets: []TargetBucket{
+			{MinChange: ptr(20), Score: 8.0, Label: "Major target increase", Code: "target_major_increase"},
+			{MinChange: ptr(10), Score: 6.0, Label: "Strong target increase", Code: "target_strong_increase"},
+			{MinChange: ptr(5), Score: 4.0, Label: "Moderate target increase", Code: "target_moderate_increase"},
+			{MinChange: ptr(0), Score: 2.0, Label: "Small target increase", Code: "target_small_increase"},
+			{MinChange: ptr(-5), Score: -2.0, Label: "Minor target decrease", Code: "target_minor_decrease"},
+			{MinChange: ptr(-10), Score: -4.0, Label: "Moderate target decrease", Code: "target_moderate_decrease"},
+			{MinChange: ptr(-20), Score: -6.0, Label: "Strong target decrease", Code: "target_strong_decrease"},
+			{Score: -8.0, Label: "Major target decrease", Code: "target_major_decrease"},
+		},
+		FreshnessBuckets: []FreshnessBucket{
+			{MaxDays: ptr(1), Score: 6.0, Label: "Breaking news", Range: "<1 day", Code: "freshness_breaking_news"},
+			{MaxDays: ptr(3), Score: 5.0, Label: "Very recent", Range: "1-3 days", Code: "freshness_very_recent"},
+			{MaxDays: ptr(7), Score: 4.0, Label: "Recent", Range: "3-7 days", Code: "freshness_recent"},
+			{MaxDays: ptr(14), Score: 3.0, Label: "Current", Range: "1-2 weeks", Code: "freshness_current"},
+			{MaxDays: ptr(30), Score: 1.5, Label: "Relevant", Range: "2-4 weeks", Code: "freshness_relevant"},
+			{MaxDays: ptr(60), Score: 0.5, Label: "Aging", Range: "1-2 months", Code: "freshness_aging"},
+			{Score: -1.0, Label: "Stale", Code: "freshness_stale"},
+		},
+		HighProfileKeywords: []string{"apple", "microsoft", "google", "amazon", "tesla", "nvidia", "meta"},
+	}
+}
+
+// defaultProfile se usa cuando Options no trae perfil
+var defaultProfile = DefaultProfile()
+
+func profileOrDefault(p *Profile) *Profile {
+	if p == nil {
+		return &defaultProfile
+	}
+	return p
+}
+
+// Validate revisa que los pesos sumen 1 y que los buckets estén ordenados
+// y terminen en un bucket sin límite
+func (p *Profile) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: falta name", ErrInvalidProfile)
+	}
+
+	w := p.Weights
+	if err := validateWeights(p.Name, "weights", w.Rating, w.Target, w.Temporal, w.Brokerage, w.Consensus); err != nil {
+		return err
+	}
+	tw := p.TickerWeights
+	if err := validateWeights(p.Name, "ticker_weights", tw.Rating, tw.Target, tw.Temporal, tw.Brokerage, tw.Dispersion); err != nil {
+		return err
+	}
+
+	if len(p.TargetBuckets) == 0 {
+		return fmt.Errorf("%w %q: target_buckets está vacío", ErrInvalidProfile, p.Name)
+	}
+	for i, b := range p.TargetBuckets {
+		last := i == len(p.TargetBuckets)-1
+		switch {
+		case last && b.MinChange != nil:
+			return fmt.Errorf("%w %q: el último target_bucket no debe tener min_change", ErrInvalidProfile, p.Name)
+		case !last && b.MinChange == nil:
+			return fmt.Errorf("%w %q: target_buckets[%d] necesita min_change", ErrInvalidProfile, p.Name, i)
+		case i > 0 && !last && *b.MinChange >= *p.TargetBuckets[i-1].MinChange:
+			return fmt.Errorf("%w %q: target_buckets debe ir de mayor a menor min_change", ErrInvalidProfile, p.Name)
+		}
+	}
+
+	if len(p.FreshnessBuckets) == 0 {
+		return fmt.Errorf("%w %q: freshness_buckets está vacío", ErrInvalidProfile, p.Name)
+	}
+	for i, b := range p.FreshnessBuckets {
+		last := i == len(p.FreshnessBuckets)-1
+		switch {
+		case last && b.MaxDays != nil:
+			return fmt.Errorf("%w %q: el último freshness_bucket no debe tener max_days", ErrInvalidProfile, p.Name)
+		case !last && b.MaxDays == nil:
+			return fmt.Errorf("%w %q: freshness_buckets[%d] necesita max_days", ErrInvalidProfile, p.Name, i)
+		case i > 0 && !last && *b.MaxDays <= *p.FreshnessBuckets[i-1].MaxDays:
+			return fmt.Errorf("%w %q: freshness_buckets debe ir de menor a mayor max_days", ErrInvalidProfile, p.Name)
+		}
+	}
+
+	return nil
+}
+
+func validateWeights(profile, field string, weights ...float64) error {
+	sum := 0.0
+	for _, w := range weights {
+		if w < 0 {
+			return fmt.Errorf("%w %q: %s no admite pesos negativos", ErrInvalidProfile, profile, field)
+		}
+		sum += w
+	}
+	if math.Abs(sum-1) > weightTolerance {
+		return fmt.Errorf("%w %q: %s suma %.4f y debe sumar 1", ErrInvalidProfile, profile, field, sum)
+	}
+	return nil
+}
+
+// targetChangeScore ubica la variación porcentual del precio objetivo en su bucket
+func (p *Profile) targetChangeScore(percentChange float64) (float64, factorReason) {
+	bucket := p.TargetBuckets[len(p.TargetBuckets)-1]
+	for _, b := range p.TargetBuckets {
+		if b.MinChange != nil && percentChange >= *b.MinChange {
+			bucket = b
+			break
+		}
+	}
+	var reason factorReason
+	reason.add(bucketCode(bucket.Code, "target", bucket.Label), fmt.Sprintf("%s (%+.1f%%, %+.1f)", bucket.Label, percentChange, bucket.Score))
+	return bucket.Score, reason
+}
+
+// freshnessScore ubica la antigüedad del evento en su bucket
+func (p *Profile) freshnessScore(days float64) (float64, factorReason) {
+	bucket := p.FreshnessBuckets[len(p.FreshnessBuckets)-1]
+	for _, b := range p.FreshnessBuckets {
+		if b.MaxDays != nil && days <= *b.MaxDays {
+			bucket = b
+			break
+		}
+	}
+	var reason factorReason
+	code := bucketCode(bucket.Code, "freshness", bucket.Label)
+	if bucket.Range == "" {
+		reason.add(code, fmt.Sprintf("%s (%.0f days, %+.1f)", bucket.Label, days, bucket.Score))
+	} else {
+		reason.add(code, fmt.Sprintf("%s (%s, %+.1f)", bucket.Label, bucket.Range, bucket.Score))
+	}
+	return bucket.Score, reason
+}
+
+// bucketCode devuelve el código del bucket o lo arma con prefix y la
+// etiqueta en snake_case ("Very recent" -> "freshness_very_recent")
+func bucketCode(code, prefix, label string) string {
+	if code != "" {
+		return code
+	}
+	words := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(append([]string{prefix}, words...), "_")
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
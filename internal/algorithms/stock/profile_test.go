@@ -0,0 +1,109 @@
+package stock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+func TestDefaultProfile_Validate(t *testing.T) {
+	p := DefaultProfile()
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Expected default profile to be valid, got %v", err)
+	}
+}
+
+func TestProfile_ValidateInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(p *Profile)
+	}{
+		{"Missing name", func(p *Profile) { p.Name = "" }},
+		{"Weights do not sum to 1", func(p *Profile) { p.Weights.Rating = 0.5 }},
+		{"Negative weight", func(p *Profile) { p.TickerWeights.Rating, p.TickerWeights.Target = 0.7, -0.1 }},
+		{"Empty target buckets", func(p *Profile) { p.TargetBuckets = nil }},
+		{"Unsorted target buckets", func(p *Profile) { p.TargetBuckets[1].MinChange = ptr(30) }},
+		{"Target catch-all not last", func(p *Profile) { p.TargetBuckets[7].MinChange = ptr(-50) }},
+		{"Unsorted freshness buckets", func(p *Profile) { p.FreshnessBuckets[2].MaxDays = ptr(2) }},
+		{"Freshness bucket without limit", func(p *Profile) { p.FreshnessBuckets[0].MaxDays = nil }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := DefaultProfile()
+			tc.mutate(&p)
+			if err := p.Validate(); !errors.Is(err, ErrInvalidProfile) {
+				t.Errorf("Expected ErrInvalidProfile, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProfile_Buckets(t *testing.T) {
+	p := DefaultProfile()
+
+	score, reason := p.targetChangeScore(25)
+	if score != 8.0 || reason.String() != "Major target increase (+25.0%, +8.0)" {
+		t.Errorf("Unexpected target bucket: %.1f %q", score, reason)
+	}
+	score, reason = p.targetChangeScore(-30)
+	if score != -8.0 || reason.String() != "Major target decrease (-30.0%, -8.0)" {
+		t.Errorf("Unexpected catch-all target bucket: %.1f %q", score, reason)
+	}
+
+	score, reason = p.freshnessScore(2)
+	if score != 5.0 || reason.String() != "Very recent (1-3 days, +5.0)" {
+		t.Errorf("Unexpected freshness bucket: %.1f %q", score, reason)
+	}
+	score, reason = p.freshnessScore(90)
+	if score != -1.0 || reason.String() != "Stale (90 days, -1.0)" {
+		t.Errorf("Unexpected catch-all freshness bucket: %.1f %q", score, reason)
+	}
+}
+
+func TestProfile_BucketCodes(t *testing.T) {
+	p := DefaultProfile()
+	_, reason := p.targetChangeScore(12)
+	if reason.codes[0] != "target_strong_increase" {
+		t.Errorf("Expected built-in code, got %v", reason.codes)
+	}
+
+	// Sin code el bucket usa su etiqueta en snake_case
+	p.FreshnessBuckets = []FreshnessBucket{{MaxDays: ptr(7), Score: 5, Label: "Fresh-ish news"}, {Score: 0, Label: "Old"}}
+	_, reason = p.freshnessScore(2)
+	if reason.codes[0] != "freshness_fresh_ish_news" {
+		t.Errorf("Expected code derived from label, got %v", reason.codes)
+	}
+}
+
+func TestRecommend_WithProfile(t *testing.T) {
+	now := testNow
+	stocks := []models.Stock{
+		// Buen rating pero precio objetivo un poco a la baja
+		{Ticker: "RATE", Company: "Rating Co", Brokerage: "UBS", RatingTo: "Strong Buy", TargetFrom: "$100.00", TargetTo: "$97.00", Time: now},
+		// Rating neutral pero precio objetivo al alza
+		{Ticker: "TRGT", Company: "Target Co", Brokerage: "UBS", RatingTo: "Hold", TargetFrom: "$100.00", TargetTo: "$106.00", Time: now},
+	}
+
+	targetOnly := DefaultProfile()
+	targetOnly.Name = "target-only"
+	targetOnly.Weights = EventWeights{Target: 1}
+	if err := targetOnly.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if recs := Recommend(stocks, Options{Limit: 1, Clock: FixedClock(now)}); recs[0].Ticker != "RATE" {
+		t.Errorf("Expected RATE with the default profile, got %s", recs[0].Ticker)
+	}
+	if recs := Recommend(stocks, Options{Limit: 1, Profile: &targetOnly, Clock: FixedClock(now)}); recs[0].Ticker != "TRGT" {
+		t.Errorf("Expected TRGT with the target-only profile, got %s", recs[0].Ticker)
+	}
+
+	// Las keywords de alto perfil salen del perfil
+	targetOnly.HighProfileKeywords = []string{"Rating"}
+	bonus, _ := calculateBonusScore(stocks[0], now, &targetOnly)
+	if bonus != 1.0 {
+		t.Errorf("Expected high-profile bonus from profile keywords, got %.1f", bonus)
+	}
+}
@@ -1,9 +1,9 @@
 package stock
 
 import (
+	"errors"
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -11,23 +11,84 @@ import (
 )
 
 type StockRecommendation struct {
-	Ticker     string
-	Company    string
-	Score      int
+	Ticker  string
+	Company string
+	Score   int
+	// Reason es el detalle legible; Breakdown trae lo mismo por criterio
 	Reason     string
+	Breakdown  []Factor
 	Rating     string
 	TargetFrom string
 	TargetTo   string
 	Time       time.Time
 }
 
+// Mode elige cómo se agrupan los eventos al recomendar
+type Mode string
+
+const (
+	// ModeEvent puntúa cada evento y se queda con el mejor de cada ticker
+	ModeEvent Mode = "event"
+	// ModeTicker combina todos los eventos de un ticker en un solo score
+	ModeTicker Mode = "ticker"
+)
+
+// ErrUnknownMode se devuelve al pedir un modo de recomendación que no existe
+var ErrUnknownMode = errors.New("modo de recomendación desconocido")
+
+// ParseMode interpreta el modo; vacío es ModeEvent
+func ParseMode(s string) (Mode, error) {
+	switch Mode(strings.ToLower(s)) {
+	case "", ModeEvent:
+		return ModeEvent, nil
+	case ModeTicker:
+		return ModeTicker, nil
+	}
+	return "", fmt.Errorf("%w %q (usa %s o %s)", ErrUnknownMode, s, ModeEvent, ModeTicker)
+}
+
+// Options configura una recomendación
+type Options struct {
+	Limit int
+	Mode  Mode
+	// Profile son los pesos y umbrales a usar; nil usa DefaultProfile
+	Profile *Profile
+	// BrokerageWeights es la credibilidad por nombre crudo de brokerage; nil
+	// usa los pesos de fábrica y los nombres que falten valen DefaultBrokerageWeight
+	BrokerageWeights map[string]float64
+	// Clock es el momento contra el que se mide la frescura; nil usa SystemClock
+	Clock Clock
+}
+
+// DefaultBrokerageWeight es la credibilidad de los brokerages sin peso propio
+const DefaultBrokerageWeight = 0.7
+
+// Recommend devuelve el top de recomendaciones en el modo pedido
+func Recommend(stocks []models.Stock, opts Options) []StockRecommendation {
+	profile := profileOrDefault(opts.Profile)
+	weights := brokerageWeightsOrDefault(opts.BrokerageWeights)
+	clock := opts.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	now := clock()
+	if opts.Mode == ModeTicker {
+		return recommendByTicker(stocks, opts.Limit, weights, now, profile)
+	}
+	return recommendByEvent(stocks, opts.Limit, weights, now, profile)
+}
+
+// RecommendStocks puntúa cada evento por separado con el perfil por defecto
 func RecommendStocks(stocks []models.Stock, limit int) []StockRecommendation {
+	return recommendByEvent(stocks, limit, getBrokerageWeights(), SystemClock(), &defaultProfile)
+}
+
+// recommendByEvent puntúa cada evento y deja el mejor de cada ticker (ModeEvent)
+func recommendByEvent(stocks []models.Stock, limit int, brokerageWeight map[string]float64, now time.Time, profile *Profile) []StockRecommendation {
 	recommendations := []StockRecommendation{}
-	now := time.Now()
 
 	// Mapas para análisis de tendencias y consenso
 	tickerAnalysis := make(map[string][]models.Stock)
-	brokerageWeight := getBrokerageWeights()
 
 	// Agrupar por ticker para análisis de consenso
 	for _, st := range stocks {
@@ -35,59 +96,14 @@ func RecommendStocks(stocks []models.Stock, limit int) []StockRecommendation {
 	}
 
 	for _, st := range stocks {
-		score := 0.0
-		reason := []string{}
-
-		// === 1. ANÁLISIS DE RATING (Peso: 35%) ===
-		ratingScore, ratingReason := calculateRatingScore(st)
-		score += ratingScore * 0.35
-		if ratingReason != "" {
-			reason = append(reason, ratingReason)
-		}
-
-		// === 2. ANÁLISIS DE PRECIO OBJETIVO (Peso: 25%) ===
-		targetScore, targetReason := calculateTargetScore(st)
-		score += targetScore * 0.25
-		if targetReason != "" {
-			reason = append(reason, targetReason)
-		}
-
-		// === 3. ANÁLISIS TEMPORAL Y MOMENTUM (Peso: 20%) ===
-		timeScore, timeReason := calculateTemporalScore(st, now)
-		score += timeScore * 0.20
-		if timeReason != "" {
-			reason = append(reason, timeReason)
-		}
-
-		// === 4. CREDIBILIDAD DEL BROKERAGE (Peso: 10%) ===
-		brokerScore, brokerReason := calculateBrokerageScore(st, brokerageWeight)
-		score += brokerScore * 0.10
-		if brokerReason != "" {
-			reason = append(reason, brokerReason)
-		}
-
-		// === 5. CONSENSO DE MERCADO (Peso: 10%) ===
-		consensusScore, consensusReason := calculateConsensusScore(st, tickerAnalysis[st.Ticker])
-		score += consensusScore * 0.10
-		if consensusReason != "" {
-			reason = append(reason, consensusReason)
-		}
-
-		// === BONIFICACIONES ESPECIALES ===
-		bonusScore, bonusReason := calculateBonusScore(st, now)
-		score += bonusScore
-		if bonusReason != "" {
-			reason = append(reason, bonusReason)
-		}
-
-		// Convertir a entero para mantener compatibilidad
-		finalScore := int(score * 10) // Multiplicar por 10 para más granularidad
+		breakdown := scoreStock(st, tickerAnalysis[st.Ticker], brokerageWeight, now, profile)
 
 		recommendations = append(recommendations, StockRecommendation{
 			Ticker:     st.Ticker,
 			Company:    st.Company,
-			Score:      finalScore,
-			Reason:     joinReasons(reason),
+			Score:      breakdown.Score,
+			Reason:     breakdown.reason(),
+			Breakdown:  breakdown.Factors,
 			Rating:     st.RatingTo,
 			TargetFrom: st.TargetFrom,
 			TargetTo:   st.TargetTo,
@@ -95,6 +111,11 @@ func RecommendStocks(stocks []models.Stock, limit int) []StockRecommendation {
 		})
 	}
 
+	return rankRecommendations(recommendations, limit)
+}
+
+// rankRecommendations ordena por score, deja el mejor de cada ticker y recorta al top N
+func rankRecommendations(recommendations []StockRecommendation, limit int) []StockRecommendation {
 	// Ordenar por score descendente
 	sort.Slice(recommendations, func(i, j int) bool {
 		if recommendations[i].Score == recommendations[j].Score {
@@ -115,9 +136,123 @@ func RecommendStocks(stocks []models.Stock, limit int) []StockRecommendation {
 	return uniqueRecommendations
 }
 
+// Factor es el aporte de un criterio al score de un evento
+type Factor struct {
+	Name string `json:"name"`
+	// Score es el puntaje del criterio antes de aplicar el peso
+	Score        float64 `json:"score"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+	Reason       string  `json:"reason"`
+	// Codes son los códigos estables de cada tramo de Reason, en el mismo
+	// orden (ej. "rating_buy", "upgrade_bonus")
+	Codes []string `json:"codes"`
+}
+
+// factorReason acumula los tramos del motivo de un criterio con su código
+type factorReason struct {
+	codes []string
+	texts []string
+}
+
+func (r *factorReason) add(code, text string) {
+	r.codes = append(r.codes, code)
+	r.texts = append(r.texts, text)
+}
+
+func (r factorReason) String() string {
+	return joinReasons(r.texts)
+}
+
+// ScoreBreakdown detalla cómo se calculó el score de un evento
+type ScoreBreakdown struct {
+	// Score usa la misma escala que StockRecommendation.Score
+	Score   int      `json:"score"`
+	Factors []Factor `json:"factors"`
+}
+
+func (b ScoreBreakdown) reason() string {
+	reasons := make([]string, 0, len(b.Factors))
+	for _, f := range b.Factors {
+		if f.Reason != "" {
+			reasons = append(reasons, f.Reason)
+		}
+	}
+	return joinReasons(reasons)
+}
+
+// ExplainScore calcula el score de un evento con el detalle por criterio.
+// tickerEvents son todos los eventos del ticker, para el consenso; un
+// profile nil usa DefaultProfile y brokerageWeights nil los pesos de fábrica.
+func ExplainScore(st models.Stock, tickerEvents []models.Stock, now time.Time, profile *Profile, brokerageWeights map[string]float64) ScoreBreakdown {
+	return scoreStock(st, tickerEvents, brokerageWeightsOrDefault(brokerageWeights), now, profileOrDefault(profile))
+}
+
+// scoreStock combina los criterios del recomendador para un evento
+func scoreStock(st models.Stock, tickerEvents []models.Stock, brokerageWeight map[string]float64, now time.Time, profile *Profile) ScoreBreakdown {
+	var b breakdownBuilder
+	w := profile.Weights
+
+	// === 1. ANÁLISIS DE RATING (Peso por defecto: 35%) ===
+	ratingScore, ratingReason := calculateRatingScore(st)
+	b.add("rating", w.Rating, ratingScore, ratingReason)
+
+	// === 2. ANÁLISIS DE PRECIO OBJETIVO (Peso por defecto: 25%) ===
+	targetScore, targetReason := calculateTargetScore(st, profile)
+	b.add("target", w.Target, targetScore, targetReason)
+
+	// === 3. ANÁLISIS TEMPORAL Y MOMENTUM (Peso por defecto: 20%) ===
+	timeScore, timeReason := calculateTemporalScore(st, now, profile)
+	b.add("temporal", w.Temporal, timeScore, timeReason)
+
+	// === 4. CREDIBILIDAD DEL BROKERAGE (Peso por defecto: 10%) ===
+	brokerScore, brokerReason := calculateBrokerageScore(st, brokerageWeight)
+	b.add("brokerage", w.Brokerage, brokerScore, brokerReason)
+
+	// === 5. CONSENSO DE MERCADO (Peso por defecto: 10%) ===
+	consensusScore, consensusReason := calculateConsensusScore(st, tickerEvents)
+	b.add("consensus", w.Consensus, consensusScore, consensusReason)
+
+	// === BONIFICACIONES ESPECIALES ===
+	bonusScore, bonusReason := calculateBonusScore(st, now, profile)
+	b.add("bonus", 1.0, bonusScore, bonusReason)
+
+	return b.finish()
+}
+
+// breakdownBuilder acumula los criterios en el orden en que se suman
+type breakdownBuilder struct {
+	breakdown ScoreBreakdown
+	score     float64
+}
+
+func (b *breakdownBuilder) add(name string, weight, value float64, reason factorReason) {
+	contribution := value * weight
+	b.score += contribution
+	codes := reason.codes
+	if codes == nil {
+		codes = []string{}
+	}
+	b.breakdown.Factors = append(b.breakdown.Factors, Factor{
+		Name:         name,
+		Score:        value,
+		Weight:       weight,
+		Contribution: contribution,
+		Reason:       reason.String(),
+		Codes:        codes,
+	})
+}
+
+func (b *breakdownBuilder) finish() ScoreBreakdown {
+	// Convertir a entero para mantener compatibilidad
+	b.breakdown.Score = int(b.score * 10) // Multiplicar por 10 para más granularidad
+	return b.breakdown
+}
+
 // === FUNCIONES AUXILIARES PARA ANÁLISIS AVANZADO ===
 
-// getBrokerageWeights retorna pesos de credibilidad para diferentes brokerages
+// getBrokerageWeights retorna pesos de credibilidad de fábrica para
+// diferentes brokerages; en el servidor vienen de la tabla brokerages
 func getBrokerageWeights() map[string]float64 {
 	return map[string]float64{
 		// Tier 1: Analistas premium
@@ -136,50 +271,52 @@ func getBrokerageWeights() map[string]float64 {
 		"Cowen":         0.8,
 		"Piper Sandler": 0.75,
 		// Tier 3: Otros
-		"Default": 0.7, // Para brokerages no listados
+		"Default": DefaultBrokerageWeight, // Para brokerages no listados
 	}
 }
 
+// brokerageWeightsOrDefault completa los pesos recibidos con la entrada
+// "Default"; nil devuelve los pesos de fábrica
+func brokerageWeightsOrDefault(weights map[string]float64) map[string]float64 {
+	if weights == nil {
+		return getBrokerageWeights()
+	}
+	if _, ok := weights["Default"]; ok {
+		return weights
+	}
+	merged := make(map[string]float64, len(weights)+1)
+	for name, w := range weights {
+		merged[name] = w
+	}
+	merged["Default"] = DefaultBrokerageWeight
+	return merged
+}
+
 // calculateRatingScore analiza el rating con mayor sophisticación
-func calculateRatingScore(stock models.Stock) (float64, string) {
-	var score float64
-	var reason string
+func calculateRatingScore(stock models.Stock) (float64, factorReason) {
+	var reason factorReason
 
 	// Análisis del rating actual
-	switch strings.ToLower(strings.TrimSpace(stock.RatingTo)) {
-	case "strong buy", "outperform", "overweight":
-		score = 10.0
-		reason = "Strong Buy rating (+10.0)"
-	case "buy", "positive":
-		score = 7.5
-		reason = "Buy rating (+7.5)"
-	case "hold", "neutral", "market perform":
-		score = 3.0
-		reason = "Hold rating (+3.0)"
-	case "underweight", "underperform":
-		score = -5.0
-		reason = "Underperform rating (-5.0)"
-	case "sell", "strong sell":
-		score = -10.0
-		reason = "Sell rating (-10.0)"
-	default:
-		score = 2.0
-		reason = "Unknown rating (+2.0)"
+	level, ok := ratingScale[canonicalRatingTo(stock)]
+	if !ok {
+		level = unknownRating
 	}
+	score := level.score
+	reason.add(level.code, fmt.Sprintf("%s rating (%+.1f)", level.text, level.score))
 
 	// Bonificación por upgrade de rating
 	if stock.RatingFrom != "" && stock.RatingTo != "" {
-		fromScore := getRatingNumericValue(stock.RatingFrom)
-		toScore := getRatingNumericValue(stock.RatingTo)
+		fromScore := ratingValue(canonicalRatingFrom(stock))
+		toScore := ratingValue(canonicalRatingTo(stock))
 
 		if toScore > fromScore {
 			upgradeBonus := (toScore - fromScore) * 2.5
 			score += upgradeBonus
-			reason += fmt.Sprintf("; Upgrade bonus (+%.1f)", upgradeBonus)
+			reason.add("upgrade_bonus", fmt.Sprintf("Upgrade bonus (+%.1f)", upgradeBonus))
 		} else if toScore < fromScore {
 			downgradepenalty := (fromScore - toScore) * 1.5
 			score -= downgradepenalty
-			reason += fmt.Sprintf("; Downgrade penalty (-%.1f)", downgradepenalty)
+			reason.add("downgrade_penalty", fmt.Sprintf("Downgrade penalty (-%.1f)", downgradepenalty))
 		}
 	}
 
@@ -187,189 +324,171 @@ func calculateRatingScore(stock models.Stock) (float64, string) {
 }
 
 // calculateTargetScore analiza precios objetivo con más detalle
-func calculateTargetScore(stock models.Stock) (float64, string) {
+func calculateTargetScore(stock models.Stock, profile *Profile) (float64, factorReason) {
+	var reason factorReason
 	if stock.TargetFrom == "" || stock.TargetTo == "" {
-		return 2.0, "No target data (+2.0)"
+		reason.add("target_missing", "No target data (+2.0)")
+		return 2.0, reason
 	}
 
-	fromPrice := parsePrice(stock.TargetFrom)
-	toPrice := parsePrice(stock.TargetTo)
-
-	if fromPrice == 0 || toPrice == 0 {
-		return 1.0, "Invalid target data (+1.0)"
+	fromPrice, toPrice, ok := targetPrices(stock)
+	if !ok {
+		reason.add("target_invalid", "Invalid target data (+1.0)")
+		return 1.0, reason
 	}
 
 	// Calcular porcentaje de cambio en el precio objetivo
 	percentChange := ((toPrice - fromPrice) / fromPrice) * 100
-
-	var score float64
-	var reason string
-
-	switch {
-	case percentChange >= 20:
-		score = 8.0
-		reason = fmt.Sprintf("Major target increase (+%.1f%%, +8.0)", percentChange)
-	case percentChange >= 10:
-		score = 6.0
-		reason = fmt.Sprintf("Strong target increase (+%.1f%%, +6.0)", percentChange)
-	case percentChange >= 5:
-		score = 4.0
-		reason = fmt.Sprintf("Moderate target increase (+%.1f%%, +4.0)", percentChange)
-	case percentChange >= 0:
-		score = 2.0
-		reason = fmt.Sprintf("Small target increase (+%.1f%%, +2.0)", percentChange)
-	case percentChange >= -5:
-		score = -2.0
-		reason = fmt.Sprintf("Minor target decrease (%.1f%%, -2.0)", percentChange)
-	case percentChange >= -10:
-		score = -4.0
-		reason = fmt.Sprintf("Moderate target decrease (%.1f%%, -4.0)", percentChange)
-	case percentChange >= -20:
-		score = -6.0
-		reason = fmt.Sprintf("Strong target decrease (%.1f%%, -6.0)", percentChange)
-	default:
-		score = -8.0
-		reason = fmt.Sprintf("Major target decrease (%.1f%%, -8.0)", percentChange)
-	}
+	score, reason := profile.targetChangeScore(percentChange)
 
 	// Bonificación por precio objetivo alto (indica confianza)
 	if toPrice > 100 {
 		score += 1.0
-		reason += "; High target confidence (+1.0)"
+		reason.add("high_target_confidence", "High target confidence (+1.0)")
 	}
 
 	return score, reason
 }
 
 // calculateTemporalScore evalúa timing y momentum
-func calculateTemporalScore(stock models.Stock, now time.Time) (float64, string) {
+func calculateTemporalScore(stock models.Stock, now time.Time, profile *Profile) (float64, factorReason) {
 	days := now.Sub(stock.Time).Hours() / 24
 
-	var score float64
-	var reason string
-
 	// Análisis de frescura de la información
-	switch {
-	case days <= 1:
-		score = 6.0
-		reason = "Breaking news (<1 day, +6.0)"
-	case days <= 3:
-		score = 5.0
-		reason = "Very recent (1-3 days, +5.0)"
-	case days <= 7:
-		score = 4.0
-		reason = "Recent (3-7 days, +4.0)"
-	case days <= 14:
-		score = 3.0
-		reason = "Current (1-2 weeks, +3.0)"
-	case days <= 30:
-		score = 1.5
-		reason = "Relevant (2-4 weeks, +1.5)"
-	case days <= 60:
-		score = 0.5
-		reason = "Aging (1-2 months, +0.5)"
-	default:
-		score = -1.0
-		reason = fmt.Sprintf("Stale (%.0f days, -1.0)", days)
-	}
+	score, reason := profile.freshnessScore(days)
 
 	// Bonificación por timing de mercado (evitar weekends en análisis crítico)
 	weekday := stock.Time.Weekday()
 	if weekday >= 1 && weekday <= 5 { // Monday to Friday
 		score += 0.5
-		reason += "; Market timing (+0.5)"
+		reason.add("market_timing", "Market timing (+0.5)")
 	}
 
 	return score, reason
 }
 
 // calculateBrokerageScore evalúa credibilidad del brokerage
-func calculateBrokerageScore(stock models.Stock, weights map[string]float64) (float64, string) {
+func calculateBrokerageScore(stock models.Stock, weights map[string]float64) (float64, factorReason) {
+	code := "brokerage_weighted"
 	weight, exists := weights[stock.Brokerage]
 	if !exists {
 		weight = weights["Default"]
+		code = "brokerage_default"
 	}
 
 	score := weight * 5.0 // Base score multiplied by weight
-	reason := fmt.Sprintf("%s credibility (%.1fx, +%.1f)", stock.Brokerage, weight, score)
+	var reason factorReason
+	reason.add(code, fmt.Sprintf("%s credibility (%.1fx, +%.1f)", stock.Brokerage, weight, score))
 
 	return score, reason
 }
 
 // calculateConsensusScore analiza consenso de múltiples analistas
-func calculateConsensusScore(stock models.Stock, allAnalysis []models.Stock) (float64, string) {
+func calculateConsensusScore(stock models.Stock, allAnalysis []models.Stock) (float64, factorReason) {
+	var reason factorReason
 	if len(allAnalysis) <= 1 {
-		return 1.0, "Single analysis (+1.0)"
-	}
-
-	// Contar ratings positivos vs negativos
-	var positive, negative, neutral int
-	for _, analysis := range allAnalysis {
-		switch strings.ToLower(strings.TrimSpace(analysis.RatingTo)) {
-		case "strong buy", "outperform", "overweight", "buy", "positive":
-			positive++
-		case "sell", "strong sell", "underweight", "underperform":
-			negative++
-		default:
-			neutral++
-		}
+		reason.add("consensus_single", "Single analysis (+1.0)")
+		return 1.0, reason
 	}
 
-	total := len(allAnalysis)
-	positiveRatio := float64(positive) / float64(total)
+	consensus := CalculateConsensus(allAnalysis)
+	positive, total, positiveRatio := consensus.Positive, consensus.Total, consensus.PositiveRatio
 
 	var score float64
-	var reason string
 
 	switch {
 	case positiveRatio >= 0.8:
 		score = 5.0
-		reason = fmt.Sprintf("Strong consensus (%d/%d positive, +5.0)", positive, total)
+		reason.add("consensus_strong", fmt.Sprintf("Strong consensus (%d/%d positive, +5.0)", positive, total))
 	case positiveRatio >= 0.6:
 		score = 3.0
-		reason = fmt.Sprintf("Good consensus (%d/%d positive, +3.0)", positive, total)
+		reason.add("consensus_good", fmt.Sprintf("Good consensus (%d/%d positive, +3.0)", positive, total))
 	case positiveRatio >= 0.4:
 		score = 1.0
-		reason = fmt.Sprintf("Mixed consensus (%d/%d positive, +1.0)", positive, total)
+		reason.add("consensus_mixed", fmt.Sprintf("Mixed consensus (%d/%d positive, +1.0)", positive, total))
 	case positiveRatio >= 0.2:
 		score = -1.0
-		reason = fmt.Sprintf("Negative consensus (%d/%d positive, -1.0)", positive, total)
+		reason.add("consensus_negative", fmt.Sprintf("Negative consensus (%d/%d positive, -1.0)", positive, total))
 	default:
 		score = -3.0
-		reason = fmt.Sprintf("Very negative consensus (%d/%d positive, -3.0)", positive, total)
+		reason.add("consensus_very_negative", fmt.Sprintf("Very negative consensus (%d/%d positive, -3.0)", positive, total))
 	}
 
 	return score, reason
 }
 
+// Buckets de consenso según el rating_to de cada evento
+const (
+	ConsensusPositive = "positive"
+	ConsensusNeutral  = "neutral"
+	ConsensusNegative = "negative"
+)
+
+// Consensus cuenta los eventos de un ticker por bucket
+type Consensus struct {
+	Positive      int     `json:"positive"`
+	Neutral       int     `json:"neutral"`
+	Negative      int     `json:"negative"`
+	Total         int     `json:"total"`
+	PositiveRatio float64 `json:"positive_ratio"`
+}
+
+// CalculateConsensus agrupa los eventos con los mismos buckets que usa
+// calculateConsensusScore
+func CalculateConsensus(events []models.Stock) Consensus {
+	var c Consensus
+	for _, e := range events {
+		switch ConsensusBucket(canonicalRatingTo(e)) {
+		case ConsensusPositive:
+			c.Positive++
+		case ConsensusNegative:
+			c.Negative++
+		default:
+			c.Neutral++
+		}
+	}
+	c.Total = len(events)
+	if c.Total > 0 {
+		c.PositiveRatio = float64(c.Positive) / float64(c.Total)
+	}
+	return c
+}
+
+// ConsensusBucket clasifica un rating canónico en positive, neutral o negative
+func ConsensusBucket(rating string) string {
+	if level, ok := ratingScale[rating]; ok {
+		return level.bucket
+	}
+	return ConsensusNeutral
+}
+
 // calculateBonusScore aplica bonificaciones especiales
-func calculateBonusScore(stock models.Stock, now time.Time) (float64, string) {
+func calculateBonusScore(stock models.Stock, now time.Time, profile *Profile) (float64, factorReason) {
 	var totalBonus float64
-	var reasons []string
+	var reason factorReason
 
 	// Bonificación por acción específica
 	switch strings.ToLower(strings.TrimSpace(stock.Action)) {
 	case "initiates", "initiated":
 		totalBonus += 2.0
-		reasons = append(reasons, "New coverage (+2.0)")
+		reason.add("new_coverage", "New coverage (+2.0)")
 	case "reiterates", "reiterated":
 		totalBonus += 1.0
-		reasons = append(reasons, "Reaffirmed position (+1.0)")
+		reason.add("reaffirmed_position", "Reaffirmed position (+1.0)")
 	case "raises", "raised":
 		totalBonus += 1.5
-		reasons = append(reasons, "Raised expectations (+1.5)")
+		reason.add("raised_expectations", "Raised expectations (+1.5)")
 	case "lowers", "lowered":
 		totalBonus -= 1.5
-		reasons = append(reasons, "Lowered expectations (-1.5)")
+		reason.add("lowered_expectations", "Lowered expectations (-1.5)")
 	}
 
 	// Bonificación por empresa de alto perfil (basado en nombre)
 	companyName := strings.ToLower(stock.Company)
-	highProfileKeywords := []string{"apple", "microsoft", "google", "amazon", "tesla", "nvidia", "meta"}
-	for _, keyword := range highProfileKeywords {
-		if strings.Contains(companyName, keyword) {
+	for _, keyword := range profile.HighProfileKeywords {
+		if strings.Contains(companyName, strings.ToLower(keyword)) {
 			totalBonus += 1.0
-			reasons = append(reasons, "High-profile company (+1.0)")
+			reason.add("high_profile_company", "High-profile company (+1.0)")
 			break
 		}
 	}
@@ -377,7 +496,6 @@ func calculateBonusScore(stock models.Stock, now time.Time) (float64, string) {
 	// Penalización por volatilidad excesiva (múltiples cambios recientes)
 	// Esta lógica se podría expandir con datos históricos
 
-	reason := joinReasons(reasons)
 	return totalBonus, reason
 }
 
@@ -396,22 +514,53 @@ func filterUniqueByTicker(recommendations []StockRecommendation) []StockRecommen
 	return unique
 }
 
-// getRatingNumericValue convierte rating a valor numérico para comparaciones
-func getRatingNumericValue(rating string) float64 {
-	switch strings.ToLower(strings.TrimSpace(rating)) {
-	case "strong buy", "outperform", "overweight":
-		return 5.0
-	case "buy", "positive":
-		return 4.0
-	case "hold", "neutral", "market perform":
-		return 3.0
-	case "underweight", "underperform":
-		return 2.0
-	case "sell", "strong sell":
-		return 1.0
-	default:
-		return 3.0 // Default to neutral
+// ratingLevel es cómo puntúa el recomendador un nivel de la escala canónica
+type ratingLevel struct {
+	// score es el puntaje del criterio rating
+	score float64
+	// value ubica el nivel en una escala de 1 a 5 para upgrades y dispersión
+	value  float64
+	bucket string
+	code   string
+	text   string
+}
+
+// ratingScale es la única tabla de ratings del recomendador: puntaje,
+// upgrades, consenso y dispersión salen de acá
+var ratingScale = map[string]ratingLevel{
+	models.RatingStrongBuy:    {10.0, 5.0, ConsensusPositive, "rating_strong_buy", "Strong Buy"},
+	models.RatingBuy:          {7.5, 4.0, ConsensusPositive, "rating_buy", "Buy"},
+	models.RatingHold:         {3.0, 3.0, ConsensusNeutral, "rating_hold", "Hold"},
+	models.RatingUnderperform: {-5.0, 2.0, ConsensusNegative, "rating_underperform", "Underperform"},
+	models.RatingSell:         {-10.0, 1.0, ConsensusNegative, "rating_sell", "Sell"},
+}
+
+// unknownRating puntúa los textos que no están en el vocabulario
+var unknownRating = ratingLevel{2.0, 3.0, ConsensusNeutral, "rating_unknown", "Unknown"}
+
+// ratingValue convierte un rating canónico a la escala 1-5; los
+// desconocidos valen lo mismo que hold
+func ratingValue(rating string) float64 {
+	if level, ok := ratingScale[rating]; ok {
+		return level.value
+	}
+	return unknownRating.value
+}
+
+// canonicalRatingTo devuelve el rating canónico guardado al ingerir el
+// evento o, si no tiene (eventos armados en memoria), el del vocabulario de fábrica
+func canonicalRatingTo(e models.Stock) string {
+	if e.CanonicalRatingTo != "" {
+		return e.CanonicalRatingTo
 	}
+	return models.DefaultCanonicalRating(e.RatingTo)
+}
+
+func canonicalRatingFrom(e models.Stock) string {
+	if e.CanonicalRatingFrom != "" {
+		return e.CanonicalRatingFrom
+	}
+	return models.DefaultCanonicalRating(e.RatingFrom)
 }
 
 func joinReasons(rs []string) string {
@@ -428,12 +577,20 @@ func joinReasons(rs []string) string {
 	return out
 }
 
-func parsePrice(s string) float64 {
-	s = strings.ReplaceAll(s, "$", "")
-	s = strings.ReplaceAll(s, ",", ".")
-	price, err := strconv.ParseFloat(s, 64)
+// targetPrices devuelve los precios objetivo del evento: los guardados al
+// ingerirlo o, si no vienen de la base, los que da ParseTargetPrice. ok es
+// false si falta alguno o si están en monedas distintas.
+func targetPrices(e models.Stock) (from, to float64, ok bool) {
+	if e.TargetFromValue != nil && e.TargetToValue != nil {
+		return e.TargetFromValue.Float64(), e.TargetToValue.Float64(), true
+	}
+	fromPrice, err := models.ParseTargetPrice(e.TargetFrom)
 	if err != nil {
-		return 0
+		return 0, 0, false
+	}
+	toPrice, err := models.ParseTargetPrice(e.TargetTo)
+	if err != nil || toPrice.Currency != fromPrice.Currency {
+		return 0, 0, false
 	}
-	return price
+	return fromPrice.Amount.Float64(), toPrice.Amount.Float64(), true
 }
@@ -1,6 +1,7 @@
 package stock
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -8,9 +9,13 @@ import (
 	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
 )
 
+// testNow es un miércoles fijo; los tests miden la frescura contra él con
+// FixedClock para no depender de la hora ni del día en que corren
+var testNow = time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC)
+
 func TestRecommendStocks(t *testing.T) {
 	// Arrange - Crear datos de prueba
-	now := time.Now()
+	now := testNow
 	testStocks := []models.Stock{
 		{
 			ID:         uuid.New(),
@@ -51,7 +56,7 @@ func TestRecommendStocks(t *testing.T) {
 	}
 
 	// Act - Ejecutar la función
-	recommendations := RecommendStocks(testStocks, 10)
+	recommendations := Recommend(testStocks, Options{Limit: 10, Clock: FixedClock(now)})
 
 	// Assert - Verificar resultados
 	if len(recommendations) != 3 {
@@ -128,6 +133,37 @@ func TestCalculateRatingScore(t *testing.T) {
 			expectedScore: 15.0, // 10.0 base + 5.0 upgrade bonus
 			expectUpgrade: true,
 		},
+		{
+			name: "Outperform is a buy",
+			stock: models.Stock{
+				RatingTo: "Outperform",
+			},
+			expectedScore: 7.5,
+		},
+		{
+			name: "Provider wording from the default vocabulary",
+			stock: models.Stock{
+				RatingFrom: "Equal-Weight",
+				RatingTo:   "Sector Outperform",
+			},
+			expectedScore: 10.0, // 7.5 base + 2.5 upgrade bonus
+			expectUpgrade: true,
+		},
+		{
+			name: "Canonical rating stored at ingestion wins",
+			stock: models.Stock{
+				RatingTo:          "Conviction List",
+				CanonicalRatingTo: models.RatingStrongBuy,
+			},
+			expectedScore: 10.0,
+		},
+		{
+			name: "Unmapped rating",
+			stock: models.Stock{
+				RatingTo: "Conviction List",
+			},
+			expectedScore: 2.0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -138,11 +174,11 @@ func TestCalculateRatingScore(t *testing.T) {
 				t.Errorf("Expected score around %.1f, got %.1f", tt.expectedScore, score)
 			}
 
-			if tt.expectUpgrade && !contains(reason, "Upgrade bonus") {
+			if tt.expectUpgrade && !contains(reason.String(), "Upgrade bonus") {
 				t.Error("Expected upgrade bonus in reason")
 			}
 
-			if reason == "" {
+			if reason.String() == "" {
 				t.Error("Expected non-empty reason")
 			}
 		})
@@ -193,13 +229,13 @@ func TestCalculateTargetScore(t *testing.T) {
 				TargetTo:   tt.targetTo,
 			}
 
-			score, reason := calculateTargetScore(stock)
+			score, reason := calculateTargetScore(stock, &defaultProfile)
 
 			if score < tt.expectedScore-0.1 || score > tt.expectedScore+0.1 {
 				t.Errorf("Expected score around %.1f, got %.1f", tt.expectedScore, score)
 			}
 
-			if reason == "" {
+			if reason.String() == "" {
 				t.Error("Expected non-empty reason")
 			}
 		})
@@ -207,7 +243,7 @@ func TestCalculateTargetScore(t *testing.T) {
 }
 
 func TestCalculateTemporalScore(t *testing.T) {
-	now := time.Now()
+	now := testNow
 
 	tests := []struct {
 		name          string
@@ -242,13 +278,13 @@ func TestCalculateTemporalScore(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			stock := models.Stock{Time: tt.stockTime}
-			score, reason := calculateTemporalScore(stock, now)
+			score, reason := calculateTemporalScore(stock, now, &defaultProfile)
 
 			if score < tt.minScore || score > tt.maxScore {
 				t.Errorf("Expected score between %.1f-%.1f, got %.1f", tt.minScore, tt.maxScore, score)
 			}
 
-			if reason == "" {
+			if reason.String() == "" {
 				t.Error("Expected non-empty reason")
 			}
 		})
@@ -304,23 +340,28 @@ func TestFilterUniqueByTicker(t *testing.T) {
 	}
 }
 
-func TestParsePrice(t *testing.T) {
+func TestTargetPrices(t *testing.T) {
+	stored := models.Decimal(1000000)
 	tests := []struct {
-		input    string
-		expected float64
+		name     string
+		stock    models.Stock
+		from, to float64
+		ok       bool
 	}{
-		{"$123.45", 123.45},
-		{"100.00", 100.00},
-		{"$1,500.50", 0.0}, // El parsePrice actual no maneja comas correctamente
-		{"", 0.0},          // String vacío
-		{"invalid", 0.0},   // Valor inválido
+		{"Dollars", models.Stock{TargetFrom: "$123.45", TargetTo: "100.00"}, 123.45, 100, true},
+		{"Thousands separator", models.Stock{TargetFrom: "$1,250.00", TargetTo: "$1,500.50"}, 1250, 1500.50, true},
+		{"Euros", models.Stock{TargetFrom: "€1.250,00", TargetTo: "1.300,00 €"}, 1250, 1300, true},
+		{"Stored values win", models.Stock{TargetFrom: "x", TargetTo: "y", TargetFromValue: &stored, TargetToValue: &stored}, 100, 100, true},
+		{"Missing", models.Stock{TargetFrom: "", TargetTo: "$10"}, 0, 0, false},
+		{"Invalid", models.Stock{TargetFrom: "invalid", TargetTo: "$10"}, 0, 0, false},
+		{"Mixed currencies", models.Stock{TargetFrom: "$10", TargetTo: "€12"}, 0, 0, false},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			result := parsePrice(tt.input)
-			if result != tt.expected {
-				t.Errorf("parsePrice(%s) = %.2f; expected %.2f", tt.input, result, tt.expected)
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, ok := targetPrices(tt.stock)
+			if ok != tt.ok || from != tt.from || to != tt.to {
+				t.Errorf("targetPrices() = %.2f, %.2f, %v; expected %.2f, %.2f, %v", from, to, ok, tt.from, tt.to, tt.ok)
 			}
 		})
 	}
@@ -349,3 +390,271 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCalculateConsensus(t *testing.T) {
+	events := []models.Stock{
+		{RatingTo: "Buy"},
+		{RatingTo: " Outperform"},
+		{RatingTo: "Hold"},
+		{RatingTo: "Underweight"},
+	}
+
+	c := CalculateConsensus(events)
+	// Outperform cuenta como positivo igual que en el puntaje del rating
+	if c.Positive != 2 || c.Neutral != 1 || c.Negative != 1 || c.Total != 4 || c.PositiveRatio != 0.5 {
+		t.Errorf("Unexpected consensus: %+v", c)
+	}
+
+	if empty := CalculateConsensus(nil); empty.Total != 0 || empty.PositiveRatio != 0 {
+		t.Errorf("Expected empty consensus, got %+v", empty)
+	}
+}
+
+func TestExplainScore(t *testing.T) {
+	now := testNow
+	st := models.Stock{
+		Ticker:     "AAPL",
+		Company:    "Apple Inc.",
+		Brokerage:  "Goldman Sachs",
+		Action:     "Initiates",
+		RatingFrom: "Hold",
+		RatingTo:   "Buy",
+		TargetFrom: "$150.00",
+		TargetTo:   "$180.00",
+		Time:       now.Add(-time.Hour),
+	}
+
+	breakdown := ExplainScore(st, []models.Stock{st}, now, nil, nil)
+	if len(breakdown.Factors) != 6 {
+		t.Fatalf("Expected 6 factors, got %+v", breakdown.Factors)
+	}
+
+	// La suma de los aportes da el mismo score que Recommend
+	total := 0.0
+	for _, f := range breakdown.Factors {
+		total += f.Contribution
+	}
+	if int(total*10) != breakdown.Score {
+		t.Errorf("Expected contributions to add up to %d, got %.2f", breakdown.Score, total*10)
+	}
+	if recs := Recommend([]models.Stock{st}, Options{Limit: 1, Clock: FixedClock(now)}); recs[0].Score != breakdown.Score || recs[0].Reason != breakdown.reason() {
+		t.Errorf("Expected same score and reason as Recommend, got %+v", recs[0])
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	testCases := []struct {
+		input     string
+		expected  Mode
+		expectErr bool
+	}{
+		{"", ModeEvent, false},
+		{"event", ModeEvent, false},
+		{"Ticker", ModeTicker, false},
+		{"best", "", true},
+	}
+
+	for _, tc := range testCases {
+		mode, err := ParseMode(tc.input)
+		if tc.expectErr != (err != nil) || mode != tc.expected {
+			t.Errorf("ParseMode(%q) = %q, %v", tc.input, mode, err)
+		}
+	}
+}
+
+func TestRecommend_TickerMode(t *testing.T) {
+	now := testNow
+	var stocks []models.Stock
+
+	// OUTL: un upgrade fuerte aislado y cinco downgrades
+	stocks = append(stocks, models.Stock{Ticker: "OUTL", Company: "Outlier Corp", Brokerage: "Goldman Sachs", Action: "Initiates",
+		RatingFrom: "Sell", RatingTo: "Strong Buy", TargetFrom: "$100.00", TargetTo: "$150.00", Time: now.Add(-time.Hour)})
+	for i, broker := range []string{"UBS", "Barclays", "Jefferies", "Cowen", "Citigroup"} {
+		stocks = append(stocks, models.Stock{Ticker: "OUTL", Company: "Outlier Corp", Brokerage: broker, Action: "downgraded by",
+			RatingFrom: "Buy", RatingTo: "Sell", TargetFrom: "$100.00", TargetTo: "$80.00", Time: now.Add(-time.Duration(i+2) * time.Hour)})
+	}
+
+	// STDY: varios brokerages de acuerdo en Buy
+	for i, broker := range []string{"Morgan Stanley", "JPMorgan", "Wells Fargo"} {
+		stocks = append(stocks, models.Stock{Ticker: "STDY", Company: "Steady Inc.", Brokerage: broker, Action: "target raised by",
+			RatingFrom: "Buy", RatingTo: "Buy", TargetFrom: "$50.00", TargetTo: "$55.00", Time: now.Add(-time.Duration(i+1) * time.Hour)})
+	}
+
+	byEvent := Recommend(stocks, Options{Limit: 2, Mode: ModeEvent, Clock: FixedClock(now)})
+	if byEvent[0].Ticker != "OUTL" {
+		t.Fatalf("Expected the outlier upgrade to win in event mode, got %+v", byEvent)
+	}
+
+	byTicker := Recommend(stocks, Options{Limit: 2, Mode: ModeTicker, Clock: FixedClock(now)})
+	if len(byTicker) != 2 || byTicker[0].Ticker != "STDY" {
+		t.Fatalf("Expected STDY first in ticker mode, got %+v", byTicker)
+	}
+	if byTicker[1].Score >= 0 {
+		t.Errorf("Expected OUTL to score negative when all events count, got %d", byTicker[1].Score)
+	}
+	// La recomendación muestra el evento más reciente del ticker
+	if byTicker[1].Rating != "Strong Buy" {
+		t.Errorf("Expected latest OUTL rating, got %q", byTicker[1].Rating)
+	}
+}
+
+func TestExplainTickerScore(t *testing.T) {
+	now := testNow
+	agree := []models.Stock{
+		{Ticker: "AAA", Brokerage: "UBS", RatingTo: "Buy", Time: now},
+		{Ticker: "AAA", Brokerage: "Citigroup", RatingTo: "Buy", Time: now},
+	}
+	split := []models.Stock{
+		{Ticker: "BBB", Brokerage: "UBS", RatingTo: "Strong Buy", Time: now},
+		{Ticker: "BBB", Brokerage: "Citigroup", RatingTo: "Sell", Time: now},
+	}
+
+	dispersion := func(b ScoreBreakdown) float64 {
+		for _, f := range b.Factors {
+			if f.Name == "dispersion" {
+				return f.Score
+			}
+		}
+		t.Fatal("Missing dispersion factor")
+		return 0
+	}
+
+	if got := dispersion(ExplainTickerScore(agree, now, nil, nil)); got != 5.0 {
+		t.Errorf("Expected full agreement score 5.0, got %.2f", got)
+	}
+	if got := dispersion(ExplainTickerScore(split, now, nil, nil)); got != -5.0 {
+		t.Errorf("Expected maximum dispersion score -5.0, got %.2f", got)
+	}
+
+	// Un rating viejo pesa menos que uno reciente del mismo brokerage
+	history := []models.Stock{
+		{Ticker: "CCC", Brokerage: "UBS", RatingTo: "Sell", Time: now.Add(-90 * 24 * time.Hour)},
+		{Ticker: "CCC", Brokerage: "UBS", RatingTo: "Buy", Time: now},
+	}
+	rating := ExplainTickerScore(history, now, nil, nil).Factors[0]
+	if rating.Name != "rating" || rating.Score <= 0 {
+		t.Errorf("Expected recent Buy to dominate the weighted rating, got %+v", rating)
+	}
+}
+
+func TestExplainTickerScore_BrokerageWeights(t *testing.T) {
+	now := testNow
+	jpm := uuid.New()
+	events := []models.Stock{
+		{Ticker: "AAA", Brokerage: "JP Morgan", BrokerageID: &jpm, RatingTo: "Sell", Time: now.Add(-time.Hour)},
+		{Ticker: "AAA", Brokerage: "J.P. Morgan", BrokerageID: &jpm, RatingTo: "Buy", Time: now},
+	}
+
+	coverage := func(b ScoreBreakdown) Factor {
+		for _, f := range b.Factors {
+			if f.Name == "brokerage" {
+				return f
+			}
+		}
+		t.Fatal("Missing brokerage factor")
+		return Factor{}
+	}
+
+	// Sin pesos propios los nombres no listados valen DefaultBrokerageWeight
+	if got := coverage(ExplainTickerScore(events, now, nil, nil)); got.Score != DefaultBrokerageWeight*5 {
+		t.Errorf("Expected default weight, got %+v", got)
+	}
+
+	// Los dos nombres son el mismo brokerage canónico: cuenta una sola vez
+	got := coverage(ExplainTickerScore(events, now, nil, map[string]float64{"J.P. Morgan": 1.0}))
+	if got.Score != 5.0 || !strings.Contains(got.Reason, "1 brokerages") {
+		t.Errorf("Expected one brokerage with weight 1.0, got %+v", got)
+	}
+}
+
+func TestRecommend_Breakdown(t *testing.T) {
+	now := time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC) // miércoles
+	st := models.Stock{
+		Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Action: "upgraded by",
+		RatingFrom: "Hold", RatingTo: "Buy", TargetFrom: "$150.00", TargetTo: "$180.00", Time: now.Add(-12 * time.Hour),
+	}
+
+	rec := recommendByEvent([]models.Stock{st}, 1, getBrokerageWeights(), now, &defaultProfile)[0]
+
+	expected := []struct {
+		name  string
+		codes []string
+	}{
+		{"rating", []string{"rating_buy", "upgrade_bonus"}},
+		{"target", []string{"target_major_increase", "high_target_confidence"}},
+		{"temporal", []string{"freshness_breaking_news", "market_timing"}},
+		{"brokerage", []string{"brokerage_weighted"}},
+		{"consensus", []string{"consensus_single"}},
+		{"bonus", []string{"high_profile_company"}},
+	}
+	if len(rec.Breakdown) != len(expected) {
+		t.Fatalf("Expected %d factors, got %+v", len(expected), rec.Breakdown)
+	}
+
+	var total float64
+	var reasons []string
+	for i, f := range rec.Breakdown {
+		if f.Name != expected[i].name || strings.Join(f.Codes, ",") != strings.Join(expected[i].codes, ",") {
+			t.Errorf("Factor %d: expected %s %v, got %s %v", i, expected[i].name, expected[i].codes, f.Name, f.Codes)
+		}
+		if f.Contribution != f.Score*f.Weight {
+			t.Errorf("Factor %s: contribution %.2f should be score × weight", f.Name, f.Contribution)
+		}
+		total += f.Contribution
+		reasons = append(reasons, f.Reason)
+	}
+
+	// La suma de aportes es el score sin escalar y Reason se conserva
+	if rec.Score != int(total*10) {
+		t.Errorf("Expected score %d from contributions, got %d", int(total*10), rec.Score)
+	}
+	if rec.Reason != strings.Join(reasons, "; ") {
+		t.Errorf("Expected Reason to join factor reasons, got %q", rec.Reason)
+	}
+}
+
+func TestRecommend_BreakdownTicker(t *testing.T) {
+	now := testNow
+	stocks := []models.Stock{
+		{Ticker: "AAA", Brokerage: "UBS", RatingTo: "Buy", Time: now},
+		{Ticker: "AAA", Brokerage: "Citigroup", RatingTo: "Sell", Time: now},
+	}
+
+	rec := Recommend(stocks, Options{Limit: 1, Mode: ModeTicker, Clock: FixedClock(now)})[0]
+
+	codes := make(map[string][]string)
+	for _, f := range rec.Breakdown {
+		codes[f.Name] = f.Codes
+	}
+	if codes["target"][0] != "target_missing" || codes["dispersion"][0] != "dispersion_disagree" || codes["brokerage"][0] != "brokerage_coverage" {
+		t.Errorf("Unexpected ticker codes: %v", codes)
+	}
+}
+
+func TestRecommend_Clock(t *testing.T) {
+	stocks := []models.Stock{
+		{Ticker: "AAA", Brokerage: "UBS", RatingTo: "Buy", Time: testNow.Add(-12 * time.Hour)},
+	}
+
+	fresh := Recommend(stocks, Options{Limit: 1, Clock: FixedClock(testNow)})[0]
+	stale := Recommend(stocks, Options{Limit: 1, Clock: FixedClock(testNow.AddDate(0, 0, 90))})[0]
+	if fresh.Score <= stale.Score {
+		t.Errorf("Expected freshness measured against the clock, got %d vs %d", fresh.Score, stale.Score)
+	}
+	if again := Recommend(stocks, Options{Limit: 1, Clock: FixedClock(testNow)})[0]; again.Score != fresh.Score || again.Reason != fresh.Reason {
+		t.Errorf("Expected the same clock to give the same recommendation, got %+v", again)
+	}
+}
+
+func TestPublishedBy(t *testing.T) {
+	stocks := []models.Stock{
+		{Ticker: "OLD", Time: testNow.Add(-time.Hour)},
+		{Ticker: "NOW", Time: testNow},
+		{Ticker: "NEW", Time: testNow.Add(time.Hour)},
+	}
+
+	published := PublishedBy(stocks, testNow)
+	if len(published) != 2 || published[0].Ticker != "OLD" || published[1].Ticker != "NOW" {
+		t.Errorf("Expected events up to and including testNow, got %+v", published)
+	}
+}
@@ -0,0 +1,208 @@
+package stock
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+// recencyHalfLifeDays es la vida media del peso de un evento en ModeTicker:
+// un rating de hace 30 días pesa la mitad que uno de hoy
+const recencyHalfLifeDays = 30.0
+
+// recommendByTicker combina todos los eventos de cada ticker en un solo
+// score, así un upgrade aislado no tapa varios downgrades (ModeTicker)
+func recommendByTicker(stocks []models.Stock, limit int, brokerageWeight map[string]float64, now time.Time, profile *Profile) []StockRecommendation {
+	byTicker := make(map[string][]models.Stock)
+	for _, st := range stocks {
+		byTicker[st.Ticker] = append(byTicker[st.Ticker], st)
+	}
+
+	recommendations := make([]StockRecommendation, 0, len(byTicker))
+	for _, events := range byTicker {
+		latest := latestEvent(events)
+		breakdown := scoreTicker(events, brokerageWeight, now, profile)
+
+		recommendations = append(recommendations, StockRecommendation{
+			Ticker:     latest.Ticker,
+			Company:    latest.Company,
+			Score:      breakdown.Score,
+			Reason:     breakdown.reason(),
+			Breakdown:  breakdown.Factors,
+			Rating:     latest.RatingTo,
+			TargetFrom: latest.TargetFrom,
+			TargetTo:   latest.TargetTo,
+			Time:       latest.Time,
+		})
+	}
+
+	return rankRecommendations(recommendations, limit)
+}
+
+// ExplainTickerScore calcula el score de ModeTicker con el detalle por
+// criterio; un profile nil usa DefaultProfile y brokerageWeights nil los
+// pesos de fábrica
+func ExplainTickerScore(events []models.Stock, now time.Time, profile *Profile, brokerageWeights map[string]float64) ScoreBreakdown {
+	return scoreTicker(events, brokerageWeightsOrDefault(brokerageWeights), now, profileOrDefault(profile))
+}
+
+// scoreTicker puntúa un ticker completo. El rating usa todos los eventos
+// ponderados por antigüedad; precio objetivo, credibilidad y dispersión usan
+// la última opinión de cada brokerage.
+func scoreTicker(events []models.Stock, brokerageWeight map[string]float64, now time.Time, profile *Profile) ScoreBreakdown {
+	var b breakdownBuilder
+	w := profile.TickerWeights
+	latest := latestEvent(events)
+	current := latestByBrokerage(events)
+
+	// === 1. RATING PONDERADO POR ANTIGÜEDAD (Peso por defecto: 35%) ===
+	ratingScore, ratingReason := calculateWeightedRatingScore(events, now)
+	b.add("rating", w.Rating, ratingScore, ratingReason)
+
+	// === 2. TENDENCIA DEL PRECIO OBJETIVO ENTRE BROKERAGES (Peso por defecto: 25%) ===
+	targetScore, targetReason := calculateTargetTrendScore(current, profile)
+	b.add("target", w.Target, targetScore, targetReason)
+
+	// === 3. FRESCURA DEL ÚLTIMO EVENTO (Peso por defecto: 15%) ===
+	timeScore, timeReason := calculateTemporalScore(latest, now, profile)
+	b.add("temporal", w.Temporal, timeScore, timeReason)
+
+	// === 4. CREDIBILIDAD PROMEDIO DE LA COBERTURA (Peso por defecto: 10%) ===
+	brokerScore, brokerReason := calculateCoverageScore(current, brokerageWeight)
+	b.add("brokerage", w.Brokerage, brokerScore, brokerReason)
+
+	// === 5. DISPERSIÓN ENTRE BROKERAGES (Peso por defecto: 15%) ===
+	dispersionScore, dispersionReason := calculateDispersionScore(current)
+	b.add("dispersion", w.Dispersion, dispersionScore, dispersionReason)
+
+	return b.finish()
+}
+
+// calculateWeightedRatingScore promedia calculateRatingScore de cada evento
+// con un peso que decae a la mitad cada recencyHalfLifeDays
+func calculateWeightedRatingScore(events []models.Stock, now time.Time) (float64, factorReason) {
+	var sum, weights float64
+	for _, e := range events {
+		days := math.Max(now.Sub(e.Time).Hours()/24, 0)
+		weight := math.Pow(0.5, days/recencyHalfLifeDays)
+		score, _ := calculateRatingScore(e)
+		sum += score * weight
+		weights += weight
+	}
+	score := sum / weights
+	var reason factorReason
+	reason.add("rating_recency_weighted", fmt.Sprintf("Recency-weighted rating over %d events (%+.1f)", len(events), score))
+	return score, reason
+}
+
+// calculateTargetTrendScore promedia la variación del precio objetivo de
+// cada brokerage y la ubica en los mismos buckets que calculateTargetScore
+func calculateTargetTrendScore(current []models.Stock, profile *Profile) (float64, factorReason) {
+	var sum float64
+	var count int
+	for _, e := range current {
+		from, to, ok := targetPrices(e)
+		if !ok {
+			continue
+		}
+		sum += (to - from) / from * 100
+		count++
+	}
+	if count == 0 {
+		var reason factorReason
+		reason.add("target_missing", "No target data (+2.0)")
+		return 2.0, reason
+	}
+
+	score, reason := profile.targetChangeScore(sum / float64(count))
+	reason.texts[0] = fmt.Sprintf("%s across %d brokerages", reason.texts[0], count)
+	return score, reason
+}
+
+// calculateCoverageScore promedia la credibilidad de los brokerages que cubren el ticker
+func calculateCoverageScore(current []models.Stock, weights map[string]float64) (float64, factorReason) {
+	var sum float64
+	for _, e := range current {
+		weight, exists := weights[e.Brokerage]
+		if !exists {
+			weight = weights["Default"]
+		}
+		sum += weight
+	}
+	avg := sum / float64(len(current))
+	score := avg * 5.0
+	var reason factorReason
+	reason.add("brokerage_coverage", fmt.Sprintf("Coverage by %d brokerages (%.2fx avg, +%.1f)", len(current), avg, score))
+	return score, reason
+}
+
+// calculateDispersionScore premia el acuerdo entre brokerages: con desviación
+// estándar 0 suma 5 y con la máxima posible (2 en la escala 1-5) resta 5
+func calculateDispersionScore(current []models.Stock) (float64, factorReason) {
+	var reason factorReason
+	if len(current) <= 1 {
+		reason.add("dispersion_single", "Single brokerage (+1.0)")
+		return 1.0, reason
+	}
+
+	values := make([]float64, len(current))
+	var mean float64
+	for i, e := range current {
+		values[i] = ratingValue(canonicalRatingTo(e))
+		mean += values[i]
+	}
+	mean /= float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(values)))
+
+	score := 5.0 - 5.0*stddev
+	if stddev <= 0.5 {
+		reason.add("dispersion_agree", fmt.Sprintf("Brokerages agree (σ=%.2f, %+.1f)", stddev, score))
+	} else {
+		reason.add("dispersion_disagree", fmt.Sprintf("Brokerages disagree (σ=%.2f, %+.1f)", stddev, score))
+	}
+	return score, reason
+}
+
+// latestByBrokerage deja el evento más reciente de cada brokerage. Los
+// eventos resueltos a un brokerage canónico se agrupan por él aunque lleguen
+// con nombres distintos.
+func latestByBrokerage(events []models.Stock) []models.Stock {
+	latest := make(map[string]models.Stock)
+	order := []string{}
+	for _, e := range events {
+		key := e.Brokerage
+		if e.BrokerageID != nil {
+			key = e.BrokerageID.String()
+		}
+		current, ok := latest[key]
+		if !ok {
+			order = append(order, key)
+		}
+		if !ok || e.Time.After(current.Time) {
+			latest[key] = e
+		}
+	}
+
+	result := make([]models.Stock, 0, len(order))
+	for _, brokerage := range order {
+		result = append(result, latest[brokerage])
+	}
+	return result
+}
+
+func latestEvent(events []models.Stock) models.Stock {
+	latest := events[0]
+	for _, e := range events[1:] {
+		if e.Time.After(latest.Time) {
+			latest = e
+		}
+	}
+	return latest
+}
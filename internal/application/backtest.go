@@ -0,0 +1,43 @@
+package application
+
+import (
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/backtest"
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/stock"
+)
+
+// BacktestRequest configura un backtest sobre los eventos guardados
+type BacktestRequest struct {
+	Start       time.Time
+	End         time.Time
+	HoldingDays int
+	TopN        int
+	Mode        stock.Mode
+	// Profile es el perfil de scoring; vacío usa el default
+	Profile string
+}
+
+// Backtest re-ejecuta el recomendador sobre el historial guardado con el
+// mismo perfil y pesos de brokerage que usa GET /api/stocks/recommend
+func (s *StockService) Backtest(prices backtest.Prices, req BacktestRequest) (*backtest.Report, error) {
+	profile, err := s.profiles.Get(req.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	stocks, err := s.stocks.ListForRecommendation()
+	if err != nil {
+		return nil, err
+	}
+
+	return backtest.Run(stocks, prices, backtest.Config{
+		Start:            req.Start,
+		End:              req.End,
+		HoldingDays:      req.HoldingDays,
+		TopN:             req.TopN,
+		Mode:             req.Mode,
+		Profile:          profile,
+		BrokerageWeights: s.brokerageWeights(stocks),
+	})
+}
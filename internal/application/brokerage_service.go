@@ -0,0 +1,293 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBrokerageNotFound = errors.New("brokerage no encontrado")
+	// ErrInvalidBrokerage se devuelve cuando el nombre, tier o peso no son válidos
+	ErrInvalidBrokerage = errors.New("brokerage inválido")
+	// ErrBrokerageConflict se devuelve si el nombre o un alias ya resuelve a otro brokerage
+	ErrBrokerageConflict = errors.New("el nombre ya pertenece a otro brokerage")
+)
+
+// BrokerageInput son los campos que un admin envía al crear o editar un
+// brokerage; al editar, los campos nil no se modifican y Aliases reemplaza
+// la lista completa
+type BrokerageInput struct {
+	Name    *string   `json:"name"`
+	Tier    *int      `json:"tier"`
+	Weight  *float64  `json:"weight"`
+	Aliases *[]string `json:"aliases"`
+}
+
+// BrokerageDirectory resuelve nombres crudos de brokerage por su nombre
+// normalizado, contra el nombre canónico y los alias de cada brokerage
+type BrokerageDirectory struct {
+	byKey map[string]*models.Brokerage
+	byID  map[uuid.UUID]*models.Brokerage
+}
+
+func NewBrokerageDirectory(brokerages []models.Brokerage) *BrokerageDirectory {
+	d := &BrokerageDirectory{
+		byKey: make(map[string]*models.Brokerage),
+		byID:  make(map[uuid.UUID]*models.Brokerage, len(brokerages)),
+	}
+	for i := range brokerages {
+		b := &brokerages[i]
+		d.byID[b.ID] = b
+		for _, key := range brokerageKeys(*b) {
+			d.byKey[key] = b
+		}
+	}
+	return d
+}
+
+// Resolve devuelve el brokerage canónico de raw, o nil si no coincide con ninguno
+func (d *BrokerageDirectory) Resolve(raw string) *models.Brokerage {
+	return d.byKey[models.NormalizeBrokerageName(raw)]
+}
+
+// Lookup devuelve el brokerage con ese ID, o nil
+func (d *BrokerageDirectory) Lookup(id uuid.UUID) *models.Brokerage {
+	return d.byID[id]
+}
+
+// Weights arma el peso de credibilidad por nombre crudo para el
+// recomendador. Usa el brokerage guardado en el evento y, si no tiene,
+// resuelve el nombre; los que no resuelven quedan con el peso por defecto.
+func (d *BrokerageDirectory) Weights(stocks []models.Stock) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, st := range stocks {
+		if _, ok := weights[st.Brokerage]; ok {
+			continue
+		}
+		if b := d.ForStock(st); b != nil {
+			weights[st.Brokerage] = b.Weight
+		}
+	}
+	return weights
+}
+
+// ForStock devuelve el brokerage guardado en el evento o, si no tiene, el
+// que resuelve su nombre; nil si ninguno
+func (d *BrokerageDirectory) ForStock(st models.Stock) *models.Brokerage {
+	if st.BrokerageID != nil {
+		if b := d.Lookup(*st.BrokerageID); b != nil {
+			return b
+		}
+	}
+	return d.Resolve(st.Brokerage)
+}
+
+// conflicts lista los nombres de b que ya resuelven a otro brokerage
+func (d *BrokerageDirectory) conflicts(b models.Brokerage) []string {
+	var taken []string
+	for _, key := range brokerageKeys(b) {
+		if other, ok := d.byKey[key]; ok && other.ID != b.ID {
+			taken = append(taken, fmt.Sprintf("%s (%s)", key, other.Name))
+		}
+	}
+	return taken
+}
+
+// brokerageKeys devuelve las llaves normalizadas del nombre y los alias
+func brokerageKeys(b models.Brokerage) []string {
+	keys := []string{models.NormalizeBrokerageName(b.Name)}
+	for _, a := range b.Aliases {
+		keys = append(keys, a.Normalized)
+	}
+	return keys
+}
+
+// BrokerageService administra la tabla de brokerages y mantiene en memoria
+// el directorio con el que se resuelven los eventos al ingerirlos
+type BrokerageService struct {
+	repo *repository.BrokerageRepository
+
+	mu        sync.RWMutex
+	directory *BrokerageDirectory
+}
+
+func NewBrokerageService(repo *repository.BrokerageRepository) *BrokerageService {
+	return &BrokerageService{repo: repo, directory: NewBrokerageDirectory(nil)}
+}
+
+// Load lee los brokerages de la base y reemplaza el directorio en memoria
+func (s *BrokerageService) Load() error {
+	brokerages, err := s.repo.List()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.directory = NewBrokerageDirectory(brokerages)
+	s.mu.Unlock()
+	return nil
+}
+
+// Directory devuelve el directorio vigente; no se modifica después de armarlo
+func (s *BrokerageService) Directory() *BrokerageDirectory {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.directory
+}
+
+// ResolveBrokerage implementa repository.BrokerageResolver
+func (s *BrokerageService) ResolveBrokerage(raw string) *uuid.UUID {
+	b := s.Directory().Resolve(raw)
+	if b == nil {
+		return nil
+	}
+	id := b.ID
+	return &id
+}
+
+// ResolveStocks vuelve a resolver el brokerage de los eventos guardados
+func (s *BrokerageService) ResolveStocks() (int64, error) {
+	return s.repo.ResolveStocks(s)
+}
+
+func (s *BrokerageService) List() ([]models.Brokerage, error) {
+	return s.repo.List()
+}
+
+func (s *BrokerageService) Get(id uuid.UUID) (*models.Brokerage, error) {
+	b, err := s.repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBrokerageNotFound
+	}
+	return b, err
+}
+
+// Create valida y guarda un brokerage nuevo; name, tier y weight son obligatorios
+func (s *BrokerageService) Create(input BrokerageInput) (*models.Brokerage, error) {
+	if input.Name == nil || input.Tier == nil || input.Weight == nil {
+		return nil, fmt.Errorf("%w: name, tier y weight son obligatorios", ErrInvalidBrokerage)
+	}
+
+	b := &models.Brokerage{ID: uuid.New()}
+	applyBrokerageInput(b, input)
+	if err := s.check(*b); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(b); err != nil {
+		return nil, err
+	}
+	s.refresh()
+	log.Printf("🏦 Brokerage %s creado (tier %d, peso %.2f)", b.Name, b.Tier, b.Weight)
+	return b, nil
+}
+
+// Update aplica los cambios y vuelve a resolver los eventos guardados
+func (s *BrokerageService) Update(id uuid.UUID, input BrokerageInput) (*models.Brokerage, error) {
+	b, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyBrokerageInput(b, input)
+	if err := s.check(*b); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(b); err != nil {
+		return nil, err
+	}
+	s.refresh()
+	log.Printf("🏦 Brokerage %s actualizado (tier %d, peso %.2f)", b.Name, b.Tier, b.Weight)
+	return b, nil
+}
+
+// Delete borra el brokerage; sus eventos pasan a usar el peso por defecto
+func (s *BrokerageService) Delete(id uuid.UUID) error {
+	deleted, err := s.repo.Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrBrokerageNotFound
+	}
+	s.refresh()
+	return nil
+}
+
+// check valida los campos y que ningún nombre resuelva ya a otro brokerage
+func (s *BrokerageService) check(b models.Brokerage) error {
+	if err := validateBrokerage(b); err != nil {
+		return err
+	}
+	if taken := s.Directory().conflicts(b); len(taken) > 0 {
+		return fmt.Errorf("%w: %s", ErrBrokerageConflict, strings.Join(taken, ", "))
+	}
+	return nil
+}
+
+// refresh recarga el directorio y re-resuelve los eventos después de un cambio
+func (s *BrokerageService) refresh() {
+	if err := s.Load(); err != nil {
+		log.Printf("⚠️ Error recargando brokerages: %v", err)
+		return
+	}
+	updated, err := s.ResolveStocks()
+	if err != nil {
+		log.Printf("⚠️ Error resolviendo brokerages de los eventos: %v", err)
+		return
+	}
+	if updated > 0 {
+		log.Printf("🔗 %d eventos re-asignados a su brokerage canónico", updated)
+	}
+}
+
+func applyBrokerageInput(b *models.Brokerage, input BrokerageInput) {
+	if input.Name != nil {
+		b.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Tier != nil {
+		b.Tier = *input.Tier
+	}
+	if input.Weight != nil {
+		b.Weight = *input.Weight
+	}
+	if input.Aliases != nil {
+		b.Aliases = brokerageAliases(*input.Aliases)
+	}
+}
+
+// brokerageAliases normaliza los alias y descarta los vacíos o repetidos
+func brokerageAliases(names []string) []models.BrokerageAlias {
+	aliases := make([]models.BrokerageAlias, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := models.NormalizeBrokerageName(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		aliases = append(aliases, models.BrokerageAlias{Normalized: key, Alias: name})
+	}
+	return aliases
+}
+
+func validateBrokerage(b models.Brokerage) error {
+	switch {
+	case models.NormalizeBrokerageName(b.Name) == "":
+		return fmt.Errorf("%w: name es obligatorio", ErrInvalidBrokerage)
+	case b.Tier < 1 || b.Tier > 3:
+		return fmt.Errorf("%w: tier debe estar entre 1 y 3", ErrInvalidBrokerage)
+	case b.Weight <= 0 || b.Weight > 1:
+		return fmt.Errorf("%w: weight debe ser mayor que 0 y como máximo 1", ErrInvalidBrokerage)
+	}
+	return nil
+}
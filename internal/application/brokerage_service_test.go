@@ -0,0 +1,136 @@
+package application
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+func testBrokerages() []models.Brokerage {
+	return []models.Brokerage{
+		{ID: uuid.New(), Name: "JPMorgan", Tier: 1, Weight: 1.0, Aliases: brokerageAliases([]string{"JPMorgan Chase"})},
+		{ID: uuid.New(), Name: "Bank of America", Tier: 1, Weight: 0.95, Aliases: brokerageAliases([]string{"BofA Securities", "Merrill Lynch"})},
+		{ID: uuid.New(), Name: "Piper Sandler", Tier: 2, Weight: 0.75},
+	}
+}
+
+func TestBrokerageDirectory_Resolve(t *testing.T) {
+	directory := NewBrokerageDirectory(testBrokerages())
+
+	testCases := []struct {
+		raw      string
+		expected string
+	}{
+		{"JPMorgan", "JPMorgan"},
+		{"J.P. Morgan", "JPMorgan"},
+		{"JPMorgan Chase & Co.", "JPMorgan"},
+		{"BofA Securities", "Bank of America"},
+		{"Merrill Lynch", "Bank of America"},
+		{"Piper Sandler Companies", "Piper Sandler"},
+		{"Needham", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.raw, func(t *testing.T) {
+			b := directory.Resolve(tc.raw)
+			got := ""
+			if b != nil {
+				got = b.Name
+			}
+			if got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestBrokerageDirectory_Weights(t *testing.T) {
+	brokerages := testBrokerages()
+	directory := NewBrokerageDirectory(brokerages)
+	piper := brokerages[2].ID
+
+	weights := directory.Weights([]models.Stock{
+		{Brokerage: "J.P. Morgan"},
+		{Brokerage: "Piper Jaffray", BrokerageID: &piper},
+		{Brokerage: "Needham"},
+	})
+
+	if weights["J.P. Morgan"] != 1.0 {
+		t.Errorf("Expected J.P. Morgan to resolve by name, got %v", weights)
+	}
+	if weights["Piper Jaffray"] != 0.75 {
+		t.Errorf("Expected Piper Jaffray to use its stored brokerage, got %v", weights)
+	}
+	if _, ok := weights["Needham"]; ok {
+		t.Errorf("Expected unknown brokerage to be left to the default weight, got %v", weights)
+	}
+}
+
+func TestBrokerageService_Check(t *testing.T) {
+	brokerages := testBrokerages()
+	service := &BrokerageService{directory: NewBrokerageDirectory(brokerages)}
+
+	// Un alias que ya resuelve a otro brokerage es un conflicto
+	b := models.Brokerage{ID: uuid.New(), Name: "Merrill", Tier: 2, Weight: 0.8, Aliases: brokerageAliases([]string{"Merrill Lynch"})}
+	err := service.check(b)
+	if !errors.Is(err, ErrBrokerageConflict) || !strings.Contains(err.Error(), "Bank of America") {
+		t.Errorf("Expected conflict with Bank of America, got %v", err)
+	}
+
+	// Editar un brokerage con sus propios alias no es un conflicto
+	if err := service.check(brokerages[1]); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, invalid := range []models.Brokerage{
+		{Name: "...", Tier: 1, Weight: 1},
+		{Name: "Needham", Tier: 4, Weight: 1},
+		{Name: "Needham", Tier: 3, Weight: 0},
+		{Name: "Needham", Tier: 3, Weight: 1.5},
+	} {
+		if err := service.check(invalid); !errors.Is(err, ErrInvalidBrokerage) {
+			t.Errorf("Expected invalid brokerage for %+v, got %v", invalid, err)
+		}
+	}
+}
+
+func TestBrokerageAliases(t *testing.T) {
+	aliases := brokerageAliases([]string{" BofA ", "BofA Securities", "", "Merrill Lynch"})
+
+	if len(aliases) != 2 {
+		t.Fatalf("Expected duplicates and blanks to be dropped, got %+v", aliases)
+	}
+	if aliases[0].Alias != "BofA" || aliases[0].Normalized != "bofa" {
+		t.Errorf("Unexpected alias: %+v", aliases[0])
+	}
+}
+
+func TestStockService_GetRecommendWithBrokerages(t *testing.T) {
+	now := time.Now()
+	event := func(ticker, brokerage string) models.Stock {
+		return models.Stock{Ticker: ticker, Company: ticker, Brokerage: brokerage, Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", TargetFrom: "$100.00", TargetTo: "$110.00", Time: now}
+	}
+	repo := repository.NewMemoryStockRepository(event("AAA", "Needham"), event("BBB", "Piper Sandler"))
+	service := NewStockService(nil, repo, nil, nil)
+
+	// Con los pesos de fábrica Piper Sandler (0.75) supera al default (0.7)
+	recs, _ := service.GetRecommend(RecommendRequest{Limit: 1})
+	if recs[0].Ticker != "BBB" {
+		t.Fatalf("Expected BBB with built-in weights, got %+v", recs)
+	}
+
+	// La tabla de brokerages manda sobre los pesos de fábrica
+	service.SetBrokerages(&BrokerageService{directory: NewBrokerageDirectory([]models.Brokerage{
+		{ID: uuid.New(), Name: "Needham", Tier: 1, Weight: 1.0},
+		{ID: uuid.New(), Name: "Piper Sandler", Tier: 3, Weight: 0.5},
+	})})
+	recs, _ = service.GetRecommend(RecommendRequest{Limit: 1})
+	if recs[0].Ticker != "AAA" {
+		t.Errorf("Expected AAA with database weights, got %+v", recs)
+	}
+}
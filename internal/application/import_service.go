@@ -0,0 +1,123 @@
+package application
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/importer"
+)
+
+// DefaultImportProvider es el proveedor que se registra en los eventos importados
+const DefaultImportProvider = "import"
+
+// ImportOptions configura una importación masiva
+type ImportOptions struct {
+	Format  importer.Format
+	Mapping importer.ColumnMapping
+	// Provider se guarda en cada models.Stock importado
+	Provider string
+	// Source es el nombre del archivo, solo para el reporte
+	Source string
+}
+
+// RowError describe por qué se rechazó una fila
+type RowError struct {
+	Line   int                   `json:"line"`
+	Ticker string                `json:"ticker,omitempty"`
+	Errors []importer.FieldError `json:"errors"`
+}
+
+// ImportReport resume una importación con el detalle de las filas rechazadas
+type ImportReport struct {
+	Source    string     `json:"source,omitempty"`
+	Provider  string     `json:"provider"`
+	TotalRows int        `json:"total_rows"`
+	Inserted  int        `json:"inserted"`
+	Updated   int        `json:"updated"`
+	Unchanged int        `json:"unchanged"`
+	Failed    int        `json:"failed"`
+	Errors    []RowError `json:"errors"`
+}
+
+// ImportService importa historial de ratings desde archivos CSV o JSON-lines
+// usando el mismo upsert por llave natural que la sincronización
+type ImportService struct {
+	stocks repository.StockRepository
+	// ratings es el vocabulario con el que se validan las filas; nil usa el de fábrica
+	ratings *RatingService
+}
+
+func NewImportService(stocks repository.StockRepository, ratings *RatingService) *ImportService {
+	return &ImportService{stocks: stocks, ratings: ratings}
+}
+
+// Import valida cada fila y hace upsert de las válidas. Una fila inválida no
+// detiene la importación; queda en el reporte con su número de línea.
+func (s *ImportService) Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	if opts.Provider == "" {
+		opts.Provider = DefaultImportProvider
+	}
+	report := &ImportReport{Source: opts.Source, Provider: opts.Provider, Errors: []RowError{}}
+
+	err := importer.Read(r, opts.Format, opts.Mapping, func(row importer.Row) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		report.TotalRows++
+
+		stock := toStockModel(row.Stock, opts.Provider)
+		fieldErrors := append(row.Errors, validationFieldErrors(row.Errors, ValidateStock(stock, s.ratings.Taxonomy(), time.Now()))...)
+		if len(fieldErrors) > 0 {
+			report.reject(row, fieldErrors)
+			return nil
+		}
+
+		outcome, err := s.stocks.Upsert(stock)
+		if err != nil {
+			log.Printf("⚠️ Error importando línea %d (%s): %v", row.Line, row.Stock.Ticker, err)
+			report.reject(row, []importer.FieldError{{Message: err.Error()}})
+			return nil
+		}
+		report.record(outcome)
+		return nil
+	})
+
+	return report, err
+}
+
+func (r *ImportReport) record(outcome repository.UpsertOutcome) {
+	switch outcome {
+	case repository.UpsertInserted:
+		r.Inserted++
+	case repository.UpsertUpdated:
+		r.Updated++
+	case repository.UpsertUnchanged:
+		r.Unchanged++
+	}
+}
+
+func (r *ImportReport) reject(row importer.Row, errs []importer.FieldError) {
+	r.Failed++
+	r.Errors = append(r.Errors, RowError{Line: row.Line, Ticker: row.Stock.Ticker, Errors: errs})
+}
+
+// validationFieldErrors convierte los problemas de validación al formato del
+// reporte, omitiendo los campos que ya fallaron al parsear la fila
+func validationFieldErrors(parseErrors []importer.FieldError, issues []ValidationIssue) []importer.FieldError {
+	failed := make(map[string]bool, len(parseErrors))
+	for _, e := range parseErrors {
+		failed[e.Field] = true
+	}
+
+	var errs []importer.FieldError
+	for _, issue := range issues {
+		if failed[issue.Field] {
+			continue
+		}
+		errs = append(errs, importer.FieldError{Field: issue.Field, Message: issue.String()})
+	}
+	return errs
+}
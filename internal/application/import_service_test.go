@@ -0,0 +1,41 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/importer"
+)
+
+func TestImportService_Import(t *testing.T) {
+	repo := repository.NewMemoryStockRepository()
+	service := NewImportService(repo, nil)
+
+	input := "ticker,company,brokerage,rating_from,rating_to,target_to,time\n" +
+		"AAPL,Apple Inc.,UBS,Hold,Buy,$200.00,2025-01-10\n" +
+		"AAPL,Apple Inc.,UBS,Hold,Buy,$200.00,2025-01-10\n" +
+		"msft,Microsoft,UBS,Hold,Buy,$400.00,2025-01-11\n" +
+		"NVDA,NVIDIA,Citi,Hold,Moon,$900.00,2025-01-12\n"
+
+	report, err := service.Import(context.Background(), strings.NewReader(input), ImportOptions{Format: importer.FormatCSV})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.TotalRows != 4 || report.Inserted != 1 || report.Unchanged != 1 || report.Failed != 2 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if report.Provider != DefaultImportProvider {
+		t.Errorf("Expected provider %q, got %q", DefaultImportProvider, report.Provider)
+	}
+	if len(report.Errors) != 2 || report.Errors[0].Line != 4 || report.Errors[1].Errors[0].Field != "rating_to" {
+		t.Errorf("Unexpected row errors: %+v", report.Errors)
+	}
+
+	stored, _, _ := repo.Search(repository.StockQuery{Page: 1, PageSize: 10})
+	if len(stored) != 1 || stored[0].Provider != DefaultImportProvider {
+		t.Errorf("Expected 1 imported stock, got %+v", stored)
+	}
+}
@@ -0,0 +1,138 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/backtest"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+var (
+	// ErrLeaderboardUnavailable se devuelve si no hay cotizaciones configuradas
+	// o si el ranking todavía no se calculó
+	ErrLeaderboardUnavailable = errors.New("leaderboard de brokerages no disponible")
+	// ErrInvalidWeightSource se devuelve con un ?weights= desconocido
+	ErrInvalidWeightSource = errors.New("weights inválido")
+)
+
+// WeightSource elige de dónde salen los pesos de credibilidad del recomendador
+type WeightSource string
+
+const (
+	// WeightsConfigured usa los pesos de la tabla brokerages
+	WeightsConfigured WeightSource = "configured"
+	// WeightsMeasured usa los pesos del leaderboard; los brokerages sin
+	// muestras suficientes conservan el configurado
+	WeightsMeasured WeightSource = "measured"
+)
+
+// ParseWeightSource interpreta ?weights=; vacío es WeightsConfigured
+func ParseWeightSource(s string) (WeightSource, error) {
+	switch WeightSource(s) {
+	case "", WeightsConfigured:
+		return WeightsConfigured, nil
+	case WeightsMeasured:
+		return WeightsMeasured, nil
+	}
+	return "", fmt.Errorf("%w: %q (usa configured o measured)", ErrInvalidWeightSource, s)
+}
+
+// LeaderboardService mide el historial de cada brokerage contra un
+// directorio de cotizaciones (el mismo formato del backtest) y guarda en
+// memoria el último ranking
+type LeaderboardService struct {
+	stocks     repository.StockRepository
+	brokerages *BrokerageService
+	pricesDir  string
+	config     backtest.LeaderboardConfig
+	now        func() time.Time
+
+	mu    sync.RWMutex
+	board *backtest.Leaderboard
+}
+
+// NewLeaderboardService crea el servicio; brokerages puede ser nil y entonces
+// se agrupa por el nombre crudo del evento
+func NewLeaderboardService(stocks repository.StockRepository, brokerages *BrokerageService, pricesDir string) *LeaderboardService {
+	return &LeaderboardService{stocks: stocks, brokerages: brokerages, pricesDir: pricesDir, now: time.Now}
+}
+
+// SetCurrency fija la moneda de las cotizaciones (vacío = USD)
+func (s *LeaderboardService) SetCurrency(currency string) {
+	s.config.Currency = strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// Refresh vuelve a leer las cotizaciones y los eventos y recalcula el ranking
+func (s *LeaderboardService) Refresh() (*backtest.Leaderboard, error) {
+	prices, err := backtest.LoadPricesDir(s.pricesDir)
+	if err != nil {
+		return nil, fmt.Errorf("leyendo cotizaciones: %w", err)
+	}
+	events, err := s.stocks.ListForRecommendation()
+	if err != nil {
+		return nil, err
+	}
+
+	// Se mide al brokerage canónico, no a cada variante del nombre
+	named := make([]models.Stock, len(events))
+	for i, e := range events {
+		e.Brokerage = s.brokerageName(e)
+		named[i] = e
+	}
+
+	board := backtest.BuildLeaderboard(named, prices, s.config)
+	board.GeneratedAt = s.now()
+
+	s.mu.Lock()
+	s.board = board
+	s.mu.Unlock()
+
+	log.Printf("🏆 Leaderboard de brokerages: %d medidos, %d con peso propio (cotizaciones hasta %s)",
+		len(board.Brokerages), len(board.Weights()), board.PricesUntil.Format("2006-01-02"))
+	return board, nil
+}
+
+// Current devuelve el último ranking calculado
+func (s *LeaderboardService) Current() (*backtest.Leaderboard, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.board == nil {
+		return nil, fmt.Errorf("%w: todavía no se calculó", ErrLeaderboardUnavailable)
+	}
+	return s.board, nil
+}
+
+// Weights arma el peso medido por nombre crudo de brokerage para stocks; los
+// brokerages sin peso medido no aparecen
+func (s *LeaderboardService) Weights(stocks []models.Stock) (map[string]float64, error) {
+	board, err := s.Current()
+	if err != nil {
+		return nil, err
+	}
+	measured := board.Weights()
+	weights := make(map[string]float64)
+	for _, st := range stocks {
+		if w, ok := measured[s.brokerageName(st)]; ok {
+			weights[st.Brokerage] = w
+		}
+	}
+	return weights, nil
+}
+
+// brokerageName devuelve el nombre canónico del brokerage del evento o, si
+// no resuelve, el nombre crudo
+func (s *LeaderboardService) brokerageName(e models.Stock) string {
+	if s.brokerages == nil {
+		return e.Brokerage
+	}
+	if b := s.brokerages.Directory().ForStock(e); b != nil {
+		return b.Name
+	}
+	return e.Brokerage
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+// writePrices escribe un CSV por ticker con cierres que cambian step por día
+// desde el 2025-01-01
+func writePrices(t *testing.T, steps map[string]float64) string {
+	dir := t.TempDir()
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for ticker, step := range steps {
+		var b strings.Builder
+		b.WriteString("date,open,high,low,close\n")
+		for i := 0; i < 120; i++ {
+			price := 100 + step*float64(i)
+			fmt.Fprintf(&b, "%s,%.2f,%.2f,%.2f,%.2f\n", first.AddDate(0, 0, i).Format("2006-01-02"), price, price, price, price)
+		}
+		if err := os.WriteFile(filepath.Join(dir, ticker+".csv"), []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLeaderboardService(t *testing.T) {
+	jan1 := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+	repo := repository.NewMemoryStockRepository(
+		models.Stock{Ticker: "AAPL", Company: "Apple", Brokerage: "Goldman Sachs", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", Time: jan1},
+		models.Stock{Ticker: "MSFT", Company: "Microsoft", Brokerage: "UBS", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", Time: jan1},
+	)
+	service := NewLeaderboardService(repo, nil, writePrices(t, map[string]float64{"AAPL": 1, "MSFT": -0.5}))
+	service.config.MinSamples = 1
+
+	if _, err := service.Current(); !errors.Is(err, ErrLeaderboardUnavailable) {
+		t.Errorf("Expected ErrLeaderboardUnavailable before the first refresh, got %v", err)
+	}
+
+	board, err := service.Refresh()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if board.GeneratedAt.IsZero() || len(board.Brokerages) != 2 || board.Brokerages[0].Brokerage != "Goldman Sachs" {
+		t.Errorf("Unexpected leaderboard: %+v", board)
+	}
+
+	stocks, _ := repo.ListForRecommendation()
+	weights, err := service.Weights(stocks)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if weights["Goldman Sachs"] != 1 || weights["UBS"] != 0.1 {
+		t.Errorf("Unexpected measured weights: %v", weights)
+	}
+}
+
+func TestLeaderboardService_MissingPrices(t *testing.T) {
+	service := NewLeaderboardService(repository.NewMemoryStockRepository(), nil, t.TempDir())
+	if _, err := service.Refresh(); err == nil {
+		t.Error("Expected error without price files")
+	}
+}
+
+func TestStockService_GetRecommendMeasuredWeights(t *testing.T) {
+	jan1 := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+	repo := repository.NewMemoryStockRepository(
+		models.Stock{Ticker: "MSFT", Company: "Microsoft", Brokerage: "UBS", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", Time: jan1},
+	)
+	service := NewStockService(nil, repo, nil, nil)
+
+	if _, err := service.GetRecommend(RecommendRequest{Limit: 1, Weights: WeightsMeasured}); !errors.Is(err, ErrLeaderboardUnavailable) {
+		t.Errorf("Expected ErrLeaderboardUnavailable without leaderboard, got %v", err)
+	}
+
+	leaderboard := NewLeaderboardService(repo, nil, writePrices(t, map[string]float64{"MSFT": -0.5}))
+	leaderboard.config.MinSamples = 1
+	if _, err := leaderboard.Refresh(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	service.SetLeaderboard(leaderboard)
+
+	// UBS vale 0.85 de fábrica y 0.1 medido (sus upgrades cayeron)
+	for source, expected := range map[WeightSource]float64{WeightsConfigured: 0.85 * 5, WeightsMeasured: 0.1 * 5} {
+		recs, err := service.GetRecommend(RecommendRequest{Limit: 1, Weights: source})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if brokerage := recs[0].Breakdown[3]; brokerage.Name != "brokerage" || brokerage.Score != expected {
+			t.Errorf("%s: expected brokerage score %.2f, got %+v", source, expected, brokerage)
+		}
+	}
+}
+
+func TestParseWeightSource(t *testing.T) {
+	for input, expected := range map[string]WeightSource{"": WeightsConfigured, "configured": WeightsConfigured, "measured": WeightsMeasured} {
+		if got, err := ParseWeightSource(input); err != nil || got != expected {
+			t.Errorf("ParseWeightSource(%q) = %q, %v", input, got, err)
+		}
+	}
+	if _, err := ParseWeightSource("opinion"); !errors.Is(err, ErrInvalidWeightSource) {
+		t.Errorf("Expected ErrInvalidWeightSource, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package application
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/external"
+)
+
+// ErrReplayUnsupported se devuelve si el proveedor no puede decodificar páginas archivadas
+var ErrReplayUnsupported = errors.New("el proveedor no soporta re-procesar páginas archivadas")
+
+// PayloadArchive implementa external.PayloadArchiver guardando cada
+// respuesta comprimida en la tabla raw_payloads
+type PayloadArchive struct {
+	repo *repository.RawPayloadRepository
+}
+
+func NewPayloadArchive(repo *repository.RawPayloadRepository) *PayloadArchive {
+	return &PayloadArchive{repo: repo}
+}
+
+func (a *PayloadArchive) ArchivePayload(ctx context.Context, payload external.RawPayload) error {
+	body, err := compressPayload(payload.Body)
+	if err != nil {
+		return err
+	}
+
+	return a.repo.Create(&models.RawPayload{
+		Provider:   payload.Provider,
+		PageToken:  payload.PageToken,
+		URL:        payload.URL,
+		StatusCode: payload.StatusCode,
+		Body:       body,
+		Size:       len(payload.Body),
+		FetchedAt:  payload.FetchedAt,
+	})
+}
+
+// ReplayOptions selecciona qué páginas archivadas re-procesar
+type ReplayOptions struct {
+	Provider string
+	Since    time.Time
+	Until    time.Time
+	// IDs limita el replay a páginas puntuales (ignora Since/Until)
+	IDs []uuid.UUID
+}
+
+// ReplayResult resume un replay; Pages cuenta las páginas re-procesadas
+type ReplayResult struct {
+	SyncResult
+	// DecodeFailed son las páginas que el mapeo actual no pudo decodificar
+	DecodeFailed int `json:"decode_failed"`
+}
+
+// ReplayService re-ingiere páginas archivadas con el mapeo actual del
+// proveedor, sin volver a llamarlo
+type ReplayService struct {
+	stocks   *StockService
+	payloads *repository.RawPayloadRepository
+}
+
+func NewReplayService(stocks *StockService, payloads *repository.RawPayloadRepository) *ReplayService {
+	return &ReplayService{stocks: stocks, payloads: payloads}
+}
+
+// Replay decodifica cada página archivada (solo respuestas 200, de la más
+// vieja a la más nueva) y la pasa por la misma validación y upsert que la
+// sincronización. No toca el checkpoint del proveedor.
+func (s *ReplayService) Replay(ctx context.Context, opts ReplayOptions) (*ReplayResult, error) {
+	result := &ReplayResult{}
+
+	provider, err := s.stocks.providers.Get(opts.Provider)
+	if err != nil {
+		return result, err
+	}
+	result.Provider = provider.Name()
+
+	decoder, ok := provider.(external.PayloadDecoder)
+	if !ok {
+		return result, fmt.Errorf("%w: %s", ErrReplayUnsupported, provider.Name())
+	}
+
+	ids := opts.IDs
+	if len(ids) == 0 {
+		ids, err = s.payloads.ListIDs(repository.RawPayloadFilter{
+			Provider:   provider.Name(),
+			Since:      opts.Since,
+			Until:      opts.Until,
+			StatusCode: http.StatusOK,
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		payload, err := s.payloads.FindByID(id)
+		if err != nil {
+			return result, err
+		}
+		if payload.Provider != provider.Name() {
+			return result, fmt.Errorf("la página %s es de %q, no de %q", id, payload.Provider, provider.Name())
+		}
+
+		page, err := decodeArchivedPage(decoder, payload)
+		if err != nil {
+			result.DecodeFailed++
+			log.Printf("⚠️ No se pudo decodificar la página %s: %v", id, err)
+			continue
+		}
+
+		result.Pages++
+		for _, item := range page.Items {
+			s.stocks.ingest(toStockModel(item, provider.Name()), &result.SyncResult)
+		}
+	}
+
+	return result, nil
+}
+
+func decodeArchivedPage(decoder external.PayloadDecoder, payload *models.RawPayload) (*external.Page, error) {
+	body, err := decompressPayload(payload.Body)
+	if err != nil {
+		return nil, err
+	}
+	return decoder.DecodePayload(body)
+}
+
+func compressPayload(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressPayload(body []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/config"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/external"
+)
+
+func TestCompressPayload_RoundTrip(t *testing.T) {
+	body := bytes.Repeat([]byte(`{"ticker":"AAPL","company":"Apple Inc."}`), 50)
+
+	compressed, err := compressPayload(body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(compressed) >= len(body) {
+		t.Errorf("Expected compressed body to be smaller (%d >= %d)", len(compressed), len(body))
+	}
+
+	restored, err := decompressPayload(compressed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(restored, body) {
+		t.Error("Expected restored body to match the original")
+	}
+}
+
+func TestDecodeArchivedPage_UsesCurrentMapping(t *testing.T) {
+	body, _ := compressPayload([]byte(`{"rows":[{"sym":"NVDA","name":"NVIDIA","at":"2025-01-10T00:00:00Z"}]}`))
+	payload := &models.RawPayload{Provider: "secondary", StatusCode: 200, Body: body}
+
+	mapping := external.FieldMapping{
+		ItemsPath: "rows",
+		Fields:    map[string]string{"ticker": "sym", "company": "name", "time": "at"},
+	}
+	provider := external.NewMappedHTTPProvider("secondary", "http://unused", "", mapping, &config.Config{})
+
+	page, err := decodeArchivedPage(provider, payload)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Ticker != "NVDA" || page.Items[0].Company != "NVIDIA" {
+		t.Errorf("Unexpected page: %+v", page.Items)
+	}
+}
@@ -0,0 +1,163 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrQuarantineNotFound = errors.New("registro en cuarentena no encontrado")
+	// ErrQuarantineResolved se devuelve al modificar un registro ya re-admitido o descartado
+	ErrQuarantineResolved = errors.New("el registro ya fue resuelto")
+)
+
+// StillInvalidError se devuelve al re-admitir un registro que sigue sin pasar la validación
+type StillInvalidError struct {
+	Issues []ValidationIssue
+}
+
+func (e *StillInvalidError) Error() string {
+	return fmt.Sprintf("el registro sigue siendo inválido: %s", joinIssues(e.Issues))
+}
+
+// QuarantineFix son las correcciones que un admin aplica a un registro;
+// los campos nil no se modifican
+type QuarantineFix struct {
+	Ticker     *string    `json:"ticker"`
+	Company    *string    `json:"company"`
+	Brokerage  *string    `json:"brokerage"`
+	Action     *string    `json:"action"`
+	RatingFrom *string    `json:"rating_from"`
+	RatingTo   *string    `json:"rating_to"`
+	TargetFrom *string    `json:"target_from"`
+	TargetTo   *string    `json:"target_to"`
+	Time       *time.Time `json:"time"`
+}
+
+// QuarantineService permite revisar, corregir, re-admitir o descartar los
+// registros que no pasaron la validación
+type QuarantineService struct {
+	repo   *repository.QuarantineRepository
+	stocks repository.StockRepository
+	// ratings es el vocabulario con el que se re-validan los registros; nil usa el de fábrica
+	ratings *RatingService
+}
+
+func NewQuarantineService(repo *repository.QuarantineRepository, stocks repository.StockRepository, ratings *RatingService) *QuarantineService {
+	return &QuarantineService{repo: repo, stocks: stocks, ratings: ratings}
+}
+
+func (s *QuarantineService) List(status models.QuarantineStatus, page, pageSize int) ([]models.QuarantinedStock, int64, error) {
+	return s.repo.List(status, page, pageSize)
+}
+
+func (s *QuarantineService) Get(id uuid.UUID) (*models.QuarantinedStock, error) {
+	q, err := s.repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrQuarantineNotFound
+	}
+	return q, err
+}
+
+// Fix aplica las correcciones y recalcula los motivos; el registro sigue
+// pendiente hasta que se re-admita
+func (s *QuarantineService) Fix(id uuid.UUID, fix QuarantineFix) (*models.QuarantinedStock, []ValidationIssue, error) {
+	q, err := s.pending(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applyQuarantineFix(q, fix)
+	issues := ValidateStock(q.ToStock(), s.ratings.Taxonomy(), time.Now())
+	q.Reasons = joinIssues(issues)
+
+	if err := s.repo.Save(q); err != nil {
+		return nil, nil, err
+	}
+	return q, issues, nil
+}
+
+// Readmit valida de nuevo el registro y, si pasa, lo guarda en stocks con
+// el mismo upsert por llave natural que la sincronización
+func (s *QuarantineService) Readmit(id uuid.UUID) (*models.QuarantinedStock, repository.UpsertOutcome, error) {
+	q, err := s.pending(id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stock := q.ToStock()
+	if issues := ValidateStock(stock, s.ratings.Taxonomy(), time.Now()); len(issues) > 0 {
+		return q, 0, &StillInvalidError{Issues: issues}
+	}
+
+	outcome, err := s.stocks.Upsert(stock)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resolveQuarantine(q, models.QuarantineReadmitted)
+	if err := s.repo.Save(q); err != nil {
+		return nil, 0, err
+	}
+	log.Printf("✅ %s re-admitido desde cuarentena", q.Ticker)
+	return q, outcome, nil
+}
+
+// Discard marca el registro como descartado; se conserva para que una
+// sincronización posterior no lo vuelva a poner en cuarentena
+func (s *QuarantineService) Discard(id uuid.UUID) (*models.QuarantinedStock, error) {
+	q, err := s.pending(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resolveQuarantine(q, models.QuarantineDiscarded)
+	if err := s.repo.Save(q); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (s *QuarantineService) pending(id uuid.UUID) (*models.QuarantinedStock, error) {
+	q, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if q.Status != models.QuarantinePending {
+		return q, ErrQuarantineResolved
+	}
+	return q, nil
+}
+
+func applyQuarantineFix(q *models.QuarantinedStock, fix QuarantineFix) {
+	set := func(dst *string, v *string) {
+		if v != nil {
+			*dst = strings.TrimSpace(*v)
+		}
+	}
+	set(&q.Ticker, fix.Ticker)
+	set(&q.Company, fix.Company)
+	set(&q.Brokerage, fix.Brokerage)
+	set(&q.Action, fix.Action)
+	set(&q.RatingFrom, fix.RatingFrom)
+	set(&q.RatingTo, fix.RatingTo)
+	set(&q.TargetFrom, fix.TargetFrom)
+	set(&q.TargetTo, fix.TargetTo)
+	if fix.Time != nil {
+		q.Time = *fix.Time
+	}
+}
+
+func resolveQuarantine(q *models.QuarantinedStock, status models.QuarantineStatus) {
+	now := time.Now()
+	q.Status = status
+	q.ResolvedAt = &now
+}
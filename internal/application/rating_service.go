@@ -0,0 +1,167 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+var (
+	ErrRatingMappingNotFound = errors.New("rating no encontrado en el vocabulario")
+	// ErrInvalidRatingMapping se devuelve cuando el texto está vacío o el nivel no es de la escala
+	ErrInvalidRatingMapping = errors.New("traducción de rating inválida")
+)
+
+// RatingMappingInput es la traducción que un admin agrega o reemplaza
+type RatingMappingInput struct {
+	Label  string `json:"label"`
+	Rating string `json:"rating"`
+}
+
+// RatingTaxonomy traduce textos de rating a la escala canónica por su texto
+// normalizado
+type RatingTaxonomy struct {
+	byKey map[string]string
+}
+
+func NewRatingTaxonomy(mappings []models.RatingMapping) *RatingTaxonomy {
+	t := &RatingTaxonomy{byKey: make(map[string]string, len(mappings))}
+	for _, m := range mappings {
+		t.byKey[m.Normalized] = m.Rating
+	}
+	return t
+}
+
+// DefaultRatingTaxonomy es el vocabulario de fábrica, el mismo que siembra
+// la migración
+func DefaultRatingTaxonomy() *RatingTaxonomy {
+	t := &RatingTaxonomy{byKey: make(map[string]string, len(models.DefaultRatingMappings))}
+	for label, rating := range models.DefaultRatingMappings {
+		t.byKey[label] = rating
+	}
+	return t
+}
+
+// Resolve devuelve el rating canónico de label, o vacío si no está
+func (t *RatingTaxonomy) Resolve(label string) string {
+	return t.byKey[models.NormalizeRatingLabel(label)]
+}
+
+// builtinRatingTaxonomy es el vocabulario de los servicios sin RatingService;
+// no se modifica
+var builtinRatingTaxonomy = DefaultRatingTaxonomy()
+
+// RatingService administra el vocabulario de ratings y lo mantiene en
+// memoria para la validación y la resolución al ingerir
+type RatingService struct {
+	repo *repository.RatingRepository
+
+	mu       sync.RWMutex
+	taxonomy *RatingTaxonomy
+}
+
+func NewRatingService(repo *repository.RatingRepository) *RatingService {
+	return &RatingService{repo: repo, taxonomy: DefaultRatingTaxonomy()}
+}
+
+// Load lee el vocabulario de la base y reemplaza el vigente
+func (s *RatingService) Load() error {
+	mappings, err := s.repo.List()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.taxonomy = NewRatingTaxonomy(mappings)
+	s.mu.Unlock()
+	return nil
+}
+
+// Taxonomy devuelve el vocabulario vigente; no se modifica después de
+// armarlo. Un RatingService nil devuelve el vocabulario de fábrica.
+func (s *RatingService) Taxonomy() *RatingTaxonomy {
+	if s == nil {
+		return builtinRatingTaxonomy
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.taxonomy
+}
+
+// ResolveRating implementa repository.RatingResolver
+func (s *RatingService) ResolveRating(label string) string {
+	return s.Taxonomy().Resolve(label)
+}
+
+// ResolveStocks vuelve a resolver los ratings canónicos de los eventos guardados
+func (s *RatingService) ResolveStocks() (int64, error) {
+	return s.repo.ResolveStocks(s)
+}
+
+func (s *RatingService) List() ([]models.RatingMapping, error) {
+	return s.repo.List()
+}
+
+// Unmapped lista los textos de rating que llegan y no están en el vocabulario
+func (s *RatingService) Unmapped() ([]repository.UnmappedRating, error) {
+	return s.repo.Unmapped(s)
+}
+
+// Put agrega o reemplaza la traducción de input.Label y re-resuelve los
+// eventos guardados. Los registros pendientes en cuarentena con ese texto no
+// se re-admiten solos: un admin los re-admite desde la cuarentena.
+func (s *RatingService) Put(input RatingMappingInput) (*models.RatingMapping, error) {
+	label := strings.TrimSpace(input.Label)
+	m := &models.RatingMapping{
+		Normalized: models.NormalizeRatingLabel(label),
+		Label:      label,
+		Rating:     strings.ToLower(strings.TrimSpace(input.Rating)),
+	}
+	if m.Normalized == "" {
+		return nil, fmt.Errorf("%w: label es obligatorio", ErrInvalidRatingMapping)
+	}
+	if !models.IsCanonicalRating(m.Rating) {
+		return nil, fmt.Errorf("%w: rating debe ser uno de %s", ErrInvalidRatingMapping, strings.Join(models.CanonicalRatings, ", "))
+	}
+
+	if err := s.repo.Save(m); err != nil {
+		return nil, err
+	}
+	s.refresh()
+	log.Printf("🏷️ Rating %q traducido a %s", m.Label, m.Rating)
+	return m, nil
+}
+
+// Delete quita un texto del vocabulario; los eventos nuevos con ese texto
+// vuelven a ir a cuarentena
+func (s *RatingService) Delete(label string) error {
+	deleted, err := s.repo.Delete(models.NormalizeRatingLabel(label))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrRatingMappingNotFound
+	}
+	s.refresh()
+	return nil
+}
+
+// refresh recarga el vocabulario y re-resuelve los eventos después de un cambio
+func (s *RatingService) refresh() {
+	if err := s.Load(); err != nil {
+		log.Printf("⚠️ Error recargando el vocabulario de ratings: %v", err)
+		return
+	}
+	updated, err := s.ResolveStocks()
+	if err != nil {
+		log.Printf("⚠️ Error resolviendo ratings de los eventos: %v", err)
+		return
+	}
+	if updated > 0 {
+		log.Printf("🔗 %d ratings de eventos re-asignados a la escala canónica", updated)
+	}
+}
@@ -0,0 +1,84 @@
+package application
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+func TestRatingTaxonomy_Resolve(t *testing.T) {
+	taxonomy := NewRatingTaxonomy([]models.RatingMapping{
+		{Normalized: "conviction list", Label: "Conviction List", Rating: models.RatingStrongBuy},
+		{Normalized: "equal weight", Label: "Equal Weight", Rating: models.RatingHold},
+	})
+
+	testCases := []struct {
+		label    string
+		expected string
+	}{
+		{"Conviction List", models.RatingStrongBuy},
+		{"Equal-Weight", models.RatingHold},
+		{"Buy", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			if got := taxonomy.Resolve(tc.label); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+
+	if got := DefaultRatingTaxonomy().Resolve("Peer Perform"); got != models.RatingHold {
+		t.Errorf("Expected Peer Perform in the default vocabulary, got %q", got)
+	}
+}
+
+func TestValidateStock_UsesGivenTaxonomy(t *testing.T) {
+	stock := validStock()
+	stock.RatingTo = "Conviction List"
+	if issues := ValidateStock(stock, DefaultRatingTaxonomy(), stock.Time); len(issues) != 1 || issues[0].Code != IssueUnknownRating {
+		t.Fatalf("Expected unknown rating with the default vocabulary, got %v", issues)
+	}
+
+	// Un texto agregado por un admin deja de ir a cuarentena
+	taxonomy := NewRatingTaxonomy([]models.RatingMapping{
+		{Normalized: "conviction list", Label: "Conviction List", Rating: models.RatingStrongBuy},
+		{Normalized: "hold", Label: "Hold", Rating: models.RatingHold},
+	})
+	stock.RatingFrom = "Hold"
+	if issues := ValidateStock(stock, taxonomy, stock.Time); len(issues) != 0 {
+		t.Errorf("Expected admin-added rating to be valid, got %v", issues)
+	}
+}
+
+func TestRatingService_Taxonomy(t *testing.T) {
+	var missing *RatingService
+	if got := missing.Taxonomy().Resolve("Peer Perform"); got != models.RatingHold {
+		t.Errorf("Expected a nil service to use the default vocabulary, got %q", got)
+	}
+
+	// Cada servicio tiene su propio vocabulario
+	custom := &RatingService{taxonomy: NewRatingTaxonomy([]models.RatingMapping{
+		{Normalized: "conviction list", Label: "Conviction List", Rating: models.RatingStrongBuy},
+	})}
+	builtin := NewRatingService(nil)
+	if got := custom.ResolveRating("Conviction List"); got != models.RatingStrongBuy {
+		t.Errorf("Expected the service vocabulary, got %q", got)
+	}
+	if got := builtin.ResolveRating("Conviction List"); got != "" {
+		t.Errorf("Expected another service not to see the mapping, got %q", got)
+	}
+
+	stocks := NewStockService(nil, repository.NewMemoryStockRepository(), nil, nil)
+	stocks.SetRatings(custom)
+	if _, err := ParseStockFilter(url.Values{"rating_to": {"Conviction List"}}, stocks.RatingTaxonomy()); err != nil {
+		t.Errorf("Expected the filter to accept the service vocabulary, got %v", err)
+	}
+	if _, err := ParseStockFilter(url.Values{"rating_to": {"Conviction List"}}, builtin.Taxonomy()); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("Expected ErrInvalidFilter with the default vocabulary, got %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/stock"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnknownProfile se devuelve al pedir un perfil de scoring que no existe
+var ErrUnknownProfile = errors.New("perfil de scoring desconocido")
+
+// ProfileStore guarda los perfiles de scoring cargados de un archivo YAML o
+// JSON y los recarga cuando el archivo cambia. Sin archivo solo existe el
+// perfil "default" con los valores de fábrica.
+type ProfileStore struct {
+	path string
+
+	mu          sync.RWMutex
+	profiles    map[string]*stock.Profile
+	defaultName string
+	modTime     time.Time
+}
+
+// NewBuiltinProfileStore tiene solo el perfil de fábrica; no puede fallar
+func NewBuiltinProfileStore() *ProfileStore {
+	builtin := stock.DefaultProfile()
+	return &ProfileStore{
+		profiles:    map[string]*stock.Profile{builtin.Name: &builtin},
+		defaultName: builtin.Name,
+	}
+}
+
+// NewProfileStore carga los perfiles de path; vacío usa solo el perfil de fábrica
+func NewProfileStore(path string) (*ProfileStore, error) {
+	if path == "" {
+		return NewBuiltinProfileStore(), nil
+	}
+	s := &ProfileStore{path: path}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get devuelve el perfil con ese nombre; vacío devuelve el default del archivo
+func (s *ProfileStore) Get(name string) (*stock.Profile, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if name == "" {
+		name = s.defaultName
+	}
+	p, ok := s.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (disponibles: %s)", ErrUnknownProfile, name, strings.Join(s.namesLocked(), ", "))
+	}
+	return p, nil
+}
+
+// Names lista los perfiles disponibles ordenados
+func (s *ProfileStore) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.namesLocked()
+}
+
+func (s *ProfileStore) namesLocked() []string {
+	names := make([]string, 0, len(s.profiles))
+	for name := range s.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Reload vuelve a leer el archivo. Si falla la lectura o la validación se
+// conservan los perfiles anteriores.
+func (s *ProfileStore) Reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("leyendo perfiles de scoring: %w", err)
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("leyendo perfiles de scoring: %w", err)
+	}
+
+	profiles, defaultName, err := parseProfiles(data, filepath.Ext(s.path))
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.path, err)
+	}
+
+	s.mu.Lock()
+	s.profiles, s.defaultName, s.modTime = profiles, defaultName, info.ModTime()
+	s.mu.Unlock()
+	return nil
+}
+
+// Watch revisa cada interval si el archivo cambió y lo recarga. No hace
+// nada sin archivo o con interval <= 0.
+func (s *ProfileStore) Watch(ctx context.Context, interval time.Duration) {
+	if s.path == "" || interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.reloadIfChanged()
+			}
+		}
+	}()
+}
+
+func (s *ProfileStore) reloadIfChanged() {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		log.Printf("⚠️ No se pudo revisar %s: %v", s.path, err)
+		return
+	}
+
+	s.mu.RLock()
+	changed := !info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if !changed {
+		return
+	}
+
+	if err := s.Reload(); err != nil {
+		log.Printf("⚠️ Perfiles de scoring sin cambios, el archivo nuevo no es válido: %v", err)
+		return
+	}
+	log.Printf("🔄 Perfiles de scoring recargados: %s", strings.Join(s.Names(), ", "))
+}
+
+// parseProfiles interpreta el archivo según su extensión. Cada perfil parte
+// de los valores de fábrica, así el archivo solo lista lo que cambia.
+//
+//	default: conservador
+//	profiles:
+//	  - name: conservador
+//	    weights: {rating: 0.4, target: 0.2, temporal: 0.2, brokerage: 0.1, consensus: 0.1}
+func parseProfiles(data []byte, ext string) (map[string]*stock.Profile, string, error) {
+	var defaultName string
+	var decoders []func(*stock.Profile) error
+
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		var file struct {
+			Default  string      `yaml:"default"`
+			Profiles []yaml.Node `yaml:"profiles"`
+		}
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, "", err
+		}
+		defaultName = file.Default
+		for _, node := range file.Profiles {
+			decoders = append(decoders, func(p *stock.Profile) error {
+				// Se vuelve a serializar para rechazar campos desconocidos,
+				// igual que con JSON
+				raw, err := yaml.Marshal(&node)
+				if err != nil {
+					return err
+				}
+				decoder := yaml.NewDecoder(bytes.NewReader(raw))
+				decoder.KnownFields(true)
+				return decoder.Decode(p)
+			})
+		}
+	case ".json":
+		var file struct {
+			Default  string            `json:"default"`
+			Profiles []json.RawMessage `json:"profiles"`
+		}
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, "", err
+		}
+		defaultName = file.Default
+		for _, raw := range file.Profiles {
+			decoders = append(decoders, func(p *stock.Profile) error {
+				decoder := json.NewDecoder(bytes.NewReader(raw))
+				decoder.DisallowUnknownFields()
+				return decoder.Decode(p)
+			})
+		}
+	default:
+		return nil, "", fmt.Errorf("formato de perfiles %q no soportado (usa .yaml, .yml o .json)", ext)
+	}
+
+	if len(decoders) == 0 {
+		return nil, "", fmt.Errorf("%w: el archivo no tiene perfiles", stock.ErrInvalidProfile)
+	}
+
+	builtin := stock.DefaultProfile()
+	profiles := map[string]*stock.Profile{builtin.Name: &builtin}
+	custom := make(map[string]bool)
+	for i, decode := range decoders {
+		p := stock.DefaultProfile()
+		p.Name = ""
+		if err := decode(&p); err != nil {
+			return nil, "", fmt.Errorf("perfil %d: %w", i+1, err)
+		}
+		if err := p.Validate(); err != nil {
+			return nil, "", err
+		}
+		if custom[p.Name] {
+			return nil, "", fmt.Errorf("%w: el perfil %q está repetido", stock.ErrInvalidProfile, p.Name)
+		}
+		custom[p.Name] = true
+		profiles[p.Name] = &p
+	}
+
+	if defaultName == "" {
+		defaultName = stock.DefaultProfileName
+	}
+	if _, ok := profiles[defaultName]; !ok {
+		return nil, "", fmt.Errorf("%w: el perfil default %q no existe", stock.ErrInvalidProfile, defaultName)
+	}
+	return profiles, defaultName, nil
+}
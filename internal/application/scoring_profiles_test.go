@@ -0,0 +1,142 @@
+package application
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/stock"
+)
+
+const testProfilesYAML = `
+default: conservative
+profiles:
+  - name: conservative
+    weights: {rating: 0.5, target: 0.2, temporal: 0.1, brokerage: 0.1, consensus: 0.1}
+    high_profile_keywords: [berkshire]
+  - name: momentum
+    freshness_buckets:
+      - {max_days: 2, score: 8, label: Hot, range: "<2 days"}
+      - {score: 0, label: Cold}
+`
+
+func writeProfiles(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewBuiltinProfileStore(t *testing.T) {
+	store := NewBuiltinProfileStore()
+	profile, err := store.Get("")
+	if err != nil || profile.Name != stock.DefaultProfileName {
+		t.Fatalf("Expected the built-in default profile, got %v (%v)", profile, err)
+	}
+	if names := store.Names(); len(names) != 1 {
+		t.Errorf("Expected only the built-in profile, got %v", names)
+	}
+}
+
+func TestProfileStore_YAML(t *testing.T) {
+	store, err := NewProfileStore(writeProfiles(t, "profiles.yaml", testProfilesYAML))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if names := store.Names(); len(names) != 3 || names[0] != "conservative" || names[1] != "default" {
+		t.Errorf("Unexpected profiles: %v", names)
+	}
+
+	p, err := store.Get("")
+	if err != nil || p.Name != "conservative" || p.Weights.Rating != 0.5 {
+		t.Fatalf("Expected conservative as default, got %+v %v", p, err)
+	}
+	// Lo que el archivo no lista se hereda de los valores de fábrica
+	if len(p.TargetBuckets) != len(stock.DefaultProfile().TargetBuckets) || p.TickerWeights.Dispersion != 0.15 {
+		t.Errorf("Expected inherited defaults, got %+v", p)
+	}
+
+	momentum, _ := store.Get("momentum")
+	if len(momentum.FreshnessBuckets) != 2 || momentum.Weights.Rating != 0.35 {
+		t.Errorf("Unexpected momentum profile: %+v", momentum)
+	}
+
+	if _, err := store.Get("aggressive"); !errors.Is(err, ErrUnknownProfile) {
+		t.Errorf("Expected ErrUnknownProfile, got %v", err)
+	}
+}
+
+func TestProfileStore_JSON(t *testing.T) {
+	store, err := NewProfileStore(writeProfiles(t, "profiles.json",
+		`{"profiles": [{"name": "ticker-heavy", "ticker_weights": {"rating": 0.2, "target": 0.2, "temporal": 0.2, "brokerage": 0.2, "dispersion": 0.2}}]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Sin "default" en el archivo se usa el perfil de fábrica
+	if p, _ := store.Get(""); p.Name != stock.DefaultProfileName {
+		t.Errorf("Expected built-in default, got %q", p.Name)
+	}
+	if p, err := store.Get("ticker-heavy"); err != nil || p.TickerWeights.Dispersion != 0.2 {
+		t.Errorf("Unexpected ticker-heavy profile: %+v %v", p, err)
+	}
+}
+
+func TestProfileStore_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"Weights do not sum to 1", "p.yaml", "profiles:\n  - name: bad\n    weights: {rating: 0.9}\n"},
+		{"Unknown field", "p.yaml", "profiles:\n  - name: typo\n    wieghts: {rating: 1}\n"},
+		{"Unknown JSON field", "p.json", `{"profiles": [{"name": "typo", "wieghts": {}}]}`},
+		{"Duplicated name", "p.yaml", "profiles:\n  - name: a\n  - name: a\n"},
+		{"Missing default", "p.yaml", "default: b\nprofiles:\n  - name: a\n"},
+		{"No profiles", "p.yaml", "default: default\n"},
+		{"Unsupported format", "p.toml", "profiles = []"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewProfileStore(writeProfiles(t, tc.file, tc.content)); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestProfileStore_ReloadIfChanged(t *testing.T) {
+	path := writeProfiles(t, "profiles.yaml", testProfilesYAML)
+	store, err := NewProfileStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	touch := func(content string, offset time.Duration) {
+		os.WriteFile(path, []byte(content), 0o644)
+		mtime := time.Now().Add(offset)
+		os.Chtimes(path, mtime, mtime)
+	}
+
+	// Un archivo inválido no reemplaza los perfiles cargados
+	touch("profiles:\n  - name: broken\n    weights: {rating: 2}\n", time.Minute)
+	store.reloadIfChanged()
+	if _, err := store.Get("conservative"); err != nil {
+		t.Errorf("Expected previous profiles after invalid reload, got %v", err)
+	}
+
+	touch("profiles:\n  - name: fresh\n", 2*time.Minute)
+	store.reloadIfChanged()
+	if _, err := store.Get("fresh"); err != nil {
+		t.Errorf("Expected reloaded profile, got %v", err)
+	}
+	if _, err := store.Get("conservative"); !errors.Is(err, ErrUnknownProfile) {
+		t.Errorf("Expected removed profile to be gone, got %v", err)
+	}
+}
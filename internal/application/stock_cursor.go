@@ -0,0 +1,126 @@
+package application
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+// ErrInvalidCursor se devuelve cuando el cursor no se puede decodificar o
+// no corresponde al orden pedido
+var ErrInvalidCursor = errors.New("cursor inválido")
+
+// StockPageRequest pide una página del listado por cursor
+type StockPageRequest struct {
+	// Cursor es un next_cursor o prev_cursor anterior; vacío es la primera página
+	Cursor string
+	Limit  int
+	Search string
+	Filter repository.StockFilter
+	// Asc ordena del más viejo al más nuevo (sort=time)
+	Asc       bool
+	WithTotal bool
+}
+
+// StockPage es una página del listado por cursor. Los cursores vacíos
+// indican que no hay más páginas en esa dirección.
+type StockPage struct {
+	Stocks     []models.Stock
+	NextCursor string
+	PrevCursor string
+	Total      *int64
+}
+
+// cursorToken es el contenido del cursor opaco
+type cursorToken struct {
+	Time time.Time `json:"t"`
+	ID   uuid.UUID `json:"id"`
+	// Prev indica que el cursor pide la página anterior
+	Prev bool `json:"p,omitempty"`
+	// Asc guarda el orden con el que se emitió el cursor
+	Asc bool `json:"a,omitempty"`
+}
+
+func encodeCursor(stock models.Stock, prev, asc bool) string {
+	raw, _ := json.Marshal(cursorToken{Time: stock.Time, ID: stock.ID, Prev: prev, Asc: asc})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(cursor string) (*cursorToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var token cursorToken
+	if err := json.Unmarshal(raw, &token); err != nil || token.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &token, nil
+}
+
+// GetStocksPage pagina el listado por cursor sobre (time, id). A diferencia
+// de GetStocks no usa offset, y las páginas no se corren si una
+// sincronización inserta eventos mientras se recorre.
+func (s *StockService) GetStocksPage(req StockPageRequest) (*StockPage, error) {
+	query := repository.StockKeysetQuery{
+		Limit:     req.Limit,
+		Search:    req.Search,
+		Filter:    req.Filter,
+		Asc:       req.Asc,
+		WithTotal: req.WithTotal,
+	}
+
+	var token *cursorToken
+	if req.Cursor != "" {
+		var err error
+		if token, err = decodeCursor(req.Cursor); err != nil {
+			return nil, err
+		}
+		// Un cursor emitido con otro orden apuntaría a otra página
+		if token.Asc != req.Asc {
+			return nil, ErrInvalidCursor
+		}
+		position := &repository.StockCursor{Time: token.Time, ID: token.ID}
+		if token.Prev {
+			query.Before = position
+		} else {
+			query.After = position
+		}
+	}
+
+	result, err := s.stocks.SearchKeyset(query)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &StockPage{Stocks: result.Stocks, Total: result.Total}
+	if len(page.Stocks) == 0 {
+		return page, nil
+	}
+	first, last := page.Stocks[0], page.Stocks[len(page.Stocks)-1]
+
+	switch {
+	case token == nil:
+		// Primera página: no hay nada antes
+		if result.HasMore {
+			page.NextCursor = encodeCursor(last, false, req.Asc)
+		}
+	case token.Prev:
+		// Se llegó desde una página posterior, así que siempre hay siguiente
+		page.NextCursor = encodeCursor(last, false, req.Asc)
+		if result.HasMore {
+			page.PrevCursor = encodeCursor(first, true, req.Asc)
+		}
+	default:
+		page.PrevCursor = encodeCursor(first, true, req.Asc)
+		if result.HasMore {
+			page.NextCursor = encodeCursor(last, false, req.Asc)
+		}
+	}
+	return page, nil
+}
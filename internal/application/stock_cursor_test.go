@@ -0,0 +1,92 @@
+package application
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+func tickers(stocks []models.Stock) []string {
+	result := make([]string, len(stocks))
+	for i, s := range stocks {
+		result[i] = s.Ticker
+	}
+	return result
+}
+
+func TestStockService_GetStocksPage(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := repository.NewMemoryStockRepository()
+	for i, ticker := range []string{"A", "B", "C", "D", "E"} {
+		repo.Upsert(models.Stock{Ticker: ticker, Brokerage: "UBS", Time: base.Add(time.Duration(i) * time.Hour)})
+	}
+	service := NewStockService(nil, repo, nil, nil)
+
+	first, err := service.GetStocksPage(StockPageRequest{Limit: 2, WithTotal: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := tickers(first.Stocks); len(got) != 2 || got[0] != "E" || got[1] != "D" {
+		t.Fatalf("Unexpected first page: %v", got)
+	}
+	if first.PrevCursor != "" || first.NextCursor == "" || first.Total == nil || *first.Total != 5 {
+		t.Errorf("Unexpected first page cursors: %+v", first)
+	}
+
+	// Un evento nuevo no corre las páginas siguientes
+	repo.Upsert(models.Stock{Ticker: "F", Brokerage: "UBS", Time: base.Add(10 * time.Hour)})
+
+	second, _ := service.GetStocksPage(StockPageRequest{Limit: 2, Cursor: first.NextCursor})
+	if got := tickers(second.Stocks); len(got) != 2 || got[0] != "C" || got[1] != "B" {
+		t.Fatalf("Unexpected second page: %v", got)
+	}
+	if second.Total != nil {
+		t.Error("Expected no total unless requested")
+	}
+
+	last, _ := service.GetStocksPage(StockPageRequest{Limit: 2, Cursor: second.NextCursor})
+	if got := tickers(last.Stocks); len(got) != 1 || got[0] != "A" || last.NextCursor != "" {
+		t.Fatalf("Unexpected last page: %v next=%q", got, last.NextCursor)
+	}
+
+	back, _ := service.GetStocksPage(StockPageRequest{Limit: 2, Cursor: last.PrevCursor})
+	if got := tickers(back.Stocks); len(got) != 2 || got[0] != "C" || got[1] != "B" {
+		t.Fatalf("Unexpected previous page: %v", got)
+	}
+	if back.PrevCursor == "" || back.NextCursor == "" {
+		t.Errorf("Expected both cursors on a middle page: %+v", back)
+	}
+
+	// Al volver al principio aparece el evento insertado durante el recorrido
+	start, _ := service.GetStocksPage(StockPageRequest{Limit: 2, Cursor: back.PrevCursor})
+	if got := tickers(start.Stocks); got[0] != "E" || got[1] != "D" || start.PrevCursor == "" {
+		t.Errorf("Unexpected page before the middle one: %v prev=%q", got, start.PrevCursor)
+	}
+}
+
+func TestStockService_GetStocksPageAscending(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := repository.NewMemoryStockRepository(
+		models.Stock{Ticker: "A", Time: base},
+		models.Stock{Ticker: "B", Time: base.Add(time.Hour)},
+		models.Stock{Ticker: "C", Time: base.Add(2 * time.Hour)},
+	)
+	service := NewStockService(nil, repo, nil, nil)
+
+	first, _ := service.GetStocksPage(StockPageRequest{Limit: 2, Asc: true})
+	if got := tickers(first.Stocks); got[0] != "A" || got[1] != "B" {
+		t.Fatalf("Unexpected ascending page: %v", got)
+	}
+
+	// El cursor queda atado al orden con el que se emitió
+	if _, err := service.GetStocksPage(StockPageRequest{Limit: 2, Cursor: first.NextCursor}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for a different order, got %v", err)
+	}
+
+	if _, err := service.GetStocksPage(StockPageRequest{Limit: 2, Cursor: "not-a-cursor"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+// ErrInvalidFilter se devuelve cuando un filtro del listado no es válido
+var ErrInvalidFilter = errors.New("filtro inválido")
+
+// ParseStockFilter arma los filtros del listado desde los query params; los
+// ratings se validan contra el vocabulario ratings.
+// Los filtros multi-valor se repiten: ?brokerage=UBS&brokerage=Citi
+func ParseStockFilter(values url.Values, ratings *RatingTaxonomy) (repository.StockFilter, error) {
+	var f repository.StockFilter
+
+	f.Brokerages = nonEmpty(values["brokerage"])
+	f.Actions = nonEmpty(values["action"])
+
+	var err error
+	if f.RatingsTo, err = parseRatings("rating_to", values["rating_to"], ratings); err != nil {
+		return f, err
+	}
+	if f.RatingsFrom, err = parseRatings("rating_from", values["rating_from"], ratings); err != nil {
+		return f, err
+	}
+
+	if f.From, err = parseFilterTime("from", values.Get("from"), false); err != nil {
+		return f, err
+	}
+	if f.To, err = parseFilterTime("to", values.Get("to"), true); err != nil {
+		return f, err
+	}
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		return f, fmt.Errorf("%w: from debe ser anterior a to", ErrInvalidFilter)
+	}
+
+	switch direction := strings.ToLower(values.Get("direction")); direction {
+	case "":
+	case repository.DirectionUpgrade, repository.DirectionDowngrade:
+		f.Direction = direction
+	default:
+		return f, fmt.Errorf("%w: direction debe ser %s o %s", ErrInvalidFilter, repository.DirectionUpgrade, repository.DirectionDowngrade)
+	}
+
+	if raw := values.Get("min_target_change"); raw != "" {
+		pct, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return f, fmt.Errorf("%w: min_target_change debe ser un número (porcentaje)", ErrInvalidFilter)
+		}
+		f.MinTargetChangePct = &pct
+	}
+
+	return f, nil
+}
+
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func parseRatings(param string, values []string, taxonomy *RatingTaxonomy) ([]string, error) {
+	ratings := nonEmpty(values)
+	for _, r := range ratings {
+		if taxonomy.Resolve(r) == "" {
+			return nil, fmt.Errorf("%w: %s %q desconocido", ErrInvalidFilter, param, r)
+		}
+	}
+	return ratings, nil
+}
+
+// parseFilterTime acepta RFC3339 o YYYY-MM-DD; con endOfDay una fecha sola
+// incluye el día completo
+func parseFilterTime(param, raw string, endOfDay bool) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s debe ser RFC3339 o YYYY-MM-DD", ErrInvalidFilter, param)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseStockFilter(t *testing.T) {
+	values, _ := url.ParseQuery("brokerage=UBS&brokerage=Citi&rating_to=Buy&rating_from=hold&action=upgraded+by" +
+		"&from=2025-01-01&to=2025-01-31&direction=Upgrade&min_target_change=10.5")
+
+	f, err := ParseStockFilter(values, DefaultRatingTaxonomy())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(f.Brokerages) != 2 || f.Brokerages[1] != "Citi" {
+		t.Errorf("Unexpected brokerages: %v", f.Brokerages)
+	}
+	if len(f.RatingsTo) != 1 || len(f.RatingsFrom) != 1 || len(f.Actions) != 1 {
+		t.Errorf("Unexpected ratings/actions: %+v", f)
+	}
+	if !f.From.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected from: %v", f.From)
+	}
+	// Una fecha sola en "to" incluye el día completo
+	if !f.To.After(time.Date(2025, 1, 31, 23, 59, 0, 0, time.UTC)) {
+		t.Errorf("Expected to to include the whole day, got %v", f.To)
+	}
+	if f.Direction != "upgrade" {
+		t.Errorf("Expected direction upgrade, got %q", f.Direction)
+	}
+	if f.MinTargetChangePct == nil || *f.MinTargetChangePct != 10.5 {
+		t.Errorf("Unexpected min target change: %v", f.MinTargetChangePct)
+	}
+}
+
+func TestParseStockFilter_Empty(t *testing.T) {
+	f, err := ParseStockFilter(url.Values{"brokerage": {" "}}, DefaultRatingTaxonomy())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.Brokerages != nil || f.From != nil || f.MinTargetChangePct != nil {
+		t.Errorf("Expected empty filter, got %+v", f)
+	}
+}
+
+func TestParseStockFilter_Invalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{"Unknown rating", "rating_to=Moon"},
+		{"Bad date", "from=yesterday"},
+		{"Inverted range", "from=2025-02-01&to=2025-01-01"},
+		{"Bad direction", "direction=sideways"},
+		{"Bad percent", "min_target_change=ten"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tc.query)
+			if _, err := ParseStockFilter(values, DefaultRatingTaxonomy()); !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("Expected ErrInvalidFilter, got %v", err)
+			}
+		})
+	}
+}
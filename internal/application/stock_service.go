@@ -1,95 +1,429 @@
 package application
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/stock"
 	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
-	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/db"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/dto"
 	"github.com/juanF18/EquiSignal-Backend/internal/interface/external"
 )
 
+var (
+	// ErrSyncInProgress se devuelve cuando ya hay una sincronización corriendo
+	ErrSyncInProgress = errors.New("ya hay una sincronización en curso")
+	// ErrIncrementalUnsupported se devuelve si el proveedor no entrega los eventos ordenados
+	ErrIncrementalUnsupported = errors.New("el proveedor no soporta sincronización incremental")
+	// ErrInvalidAsOf se devuelve al pedir una recomendación "as of" una fecha futura
+	ErrInvalidAsOf = errors.New("as_of inválido")
+)
+
 type StockService struct {
-	api *external.ExternalAPI
+	providers  *external.Registry
+	stocks     repository.StockRepository
+	syncStates repository.SyncStateRepository
+	quarantine *repository.QuarantineRepository
+	// profiles son los perfiles de scoring del recomendador
+	profiles *ProfileStore
+	// brokerages da el peso de credibilidad de cada brokerage; nil usa los de fábrica
+	brokerages *BrokerageService
+	// ratings es el vocabulario con el que se validan los eventos; nil usa el de fábrica
+	ratings *RatingService
+	// leaderboard da los pesos medidos para ?weights=measured; nil los deshabilita
+	leaderboard *LeaderboardService
+	// clock es la hora del servicio: la frescura del recomendador, la
+	// validación de fechas futuras y el cierre de los checkpoints
+	clock stock.Clock
+	// syncing evita que dos sincronizaciones recorran el proveedor a la vez
+	syncing sync.Mutex
+}
+
+// SyncOptions configura una sincronización
+type SyncOptions struct {
+	// Provider es el nombre del proveedor; vacío usa el default
+	Provider string
+	Mode     SyncMode
+	// DryRun recorre el proveedor y compara contra stocks sin escribir nada:
+	// ni eventos, ni checkpoint, ni cuarentena, ni archivo de respuestas
+	DryRun bool
+	// OnPage se llama con el resultado acumulado después de cada página
+	OnPage func(result SyncResult)
 }
 
-func NewStockService(api *external.ExternalAPI) *StockService {
-	return &StockService{api: api}
+func NewStockService(providers *external.Registry, stocks repository.StockRepository, syncStates repository.SyncStateRepository, quarantine *repository.QuarantineRepository) *StockService {
+	return &StockService{providers: providers, stocks: stocks, syncStates: syncStates, quarantine: quarantine, profiles: NewBuiltinProfileStore(), clock: stock.SystemClock}
 }
 
-// Trae todas las páginas y guarda en Cockroach
-func (s *StockService) UpdateStocks() error {
-	nextPage := ""
+// SetProfiles reemplaza los perfiles de scoring de fábrica por los de un archivo
+func (s *StockService) SetProfiles(profiles *ProfileStore) {
+	s.profiles = profiles
+}
+
+// SetBrokerages hace que el recomendador use los pesos de la tabla brokerages
+func (s *StockService) SetBrokerages(brokerages *BrokerageService) {
+	s.brokerages = brokerages
+}
+
+// SetRatings hace que la validación use el vocabulario de ratings de la base
+func (s *StockService) SetRatings(ratings *RatingService) {
+	s.ratings = ratings
+}
+
+// RatingTaxonomy devuelve el vocabulario con el que se validan los eventos
+// y los filtros del listado
+func (s *StockService) RatingTaxonomy() *RatingTaxonomy {
+	return s.ratings.Taxonomy()
+}
+
+// SetLeaderboard permite recomendar con los pesos medidos del leaderboard
+func (s *StockService) SetLeaderboard(leaderboard *LeaderboardService) {
+	s.leaderboard = leaderboard
+}
+
+// SetClock reemplaza la hora del servicio; sirve para tests deterministas
+func (s *StockService) SetClock(clock stock.Clock) {
+	s.clock = clock
+}
+
+// brokerageWeights devuelve los pesos de credibilidad para stocks, o nil
+// para usar los de fábrica
+func (s *StockService) brokerageWeights(stocks []models.Stock) map[string]float64 {
+	if s.brokerages == nil {
+		return nil
+	}
+	return s.brokerages.Directory().Weights(stocks)
+}
+
+// Providers lista los proveedores registrados y sus capacidades
+func (s *StockService) Providers() []external.ProviderInfo {
+	return s.providers.List()
+}
+
+// UpdateStocks sincroniza de forma bloqueante el proveedor con el modo indicado
+func (s *StockService) UpdateStocks(ctx context.Context, provider string, mode SyncMode) (*SyncResult, error) {
+	return s.Sync(ctx, SyncOptions{Provider: provider, Mode: mode})
+}
+
+// Sync trae las páginas del proveedor y hace upsert en Cockroach por llave natural,
+// por lo que se puede re-ejecutar sin duplicar eventos. Después de cada página
+// se guarda el checkpoint para poder retomar un recorrido interrumpido.
+func (s *StockService) Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	mode := opts.Mode
+	result := &SyncResult{Mode: mode}
+
+	provider, err := s.resolveProvider(opts.Provider, mode)
+	if err != nil {
+		return result, err
+	}
+	result.Provider = provider.Name()
+
+	dryRun := opts.DryRun
+	if dryRun {
+		// Solo lectura: puede correr en paralelo con una sincronización real
+		result.DryRun = true
+		result.Diff = newSyncDiff()
+		ctx = external.WithoutArchive(ctx)
+	} else {
+		if !s.syncing.TryLock() {
+			return result, ErrSyncInProgress
+		}
+		defer s.syncing.Unlock()
+	}
+
+	// Cada proveedor tiene su propio checkpoint
+	state, err := s.syncStates.Get(provider.Name())
+	if err != nil {
+		return result, err
+	}
+	if !dryRun {
+		result.Checkpoint = state
+	}
+
+	// Si hay token pendiente, un recorrido anterior quedó a medias;
+	// el dry-run siempre empieza desde la primera página
+	nextPage := state.NextPage
+	if dryRun {
+		nextPage = ""
+	}
+	if nextPage != "" {
+		log.Printf("↩️ Retomando sincronización de %s desde next_page=%s", provider.Name(), nextPage)
+	}
+
 	for {
-		resp, err := s.api.FetchStocks(nextPage)
+		resp, err := provider.FetchPage(ctx, nextPage)
 		if err != nil {
-			return err
+			return result, err
 		}
+		result.Pages++
 
 		// Guardar items en DB
 		for _, item := range resp.Items {
-			stock := models.Stock{
-				Ticker:     item.Ticker,
-				Company:    item.Company,
-				Brokerage:  item.Brokerage,
-				Action:     item.Action,
-				RatingFrom: item.RatingFrom,
-				RatingTo:   item.RatingTo,
-				TargetFrom: item.TargetFrom,
-				TargetTo:   item.TargetTo,
-				Time:       item.Time,
+			stock := toStockModel(item, provider.Name())
+			if dryRun {
+				if !s.preview(stock, result) {
+					continue
+				}
+			} else if !s.ingest(stock, result) {
+				continue
+			}
+
+			if stock.Time.After(state.PendingLatestTime) {
+				state.PendingLatestTime = stock.Time
 			}
-			if err := db.DB.Create(&stock).Error; err != nil {
-				log.Printf("⚠️ Error guardando %s: %v", stock.Ticker, err)
+			if mode == SyncModeIncremental && isAlreadyIngested(state, stock) {
+				result.ReachedKnown = true
 			}
 		}
 
-		if resp.NextPage == "" {
-			break // ya no hay más páginas
+		if opts.OnPage != nil {
+			opts.OnPage(*result)
 		}
+
+		// ya no hay más páginas, o el incremental alcanzó eventos conocidos
+		if resp.NextPage == "" || result.ReachedKnown {
+			break
+		}
+
 		nextPage = resp.NextPage
+		if !dryRun {
+			state.NextPage = nextPage
+			state.LastMode = string(mode)
+			if err := s.syncStates.Save(state); err != nil {
+				return result, err
+			}
+		}
+
+		// Cancelado entre páginas: el checkpoint ya quedó guardado
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+	}
+
+	if dryRun {
+		return result, nil
 	}
 
+	// Con eventos sin guardar el checkpoint no avanza, así el próximo
+	// incremental los vuelve a traer
+	completeSyncState(state, mode, result.Failed == 0, s.clock())
+	if err := s.syncStates.Save(state); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// ingest valida el evento y lo guarda con upsert, o lo manda a cuarentena.
+// Devuelve false si el registro no era válido o no se pudo guardar.
+func (s *StockService) ingest(stock models.Stock, result *SyncResult) bool {
+	if issues := ValidateStock(stock, s.ratings.Taxonomy(), s.clock()); len(issues) > 0 {
+		if err := s.quarantineStock(stock, issues); err != nil {
+			result.Failed++
+			log.Printf("⚠️ Error poniendo en cuarentena %s: %v", stock.Ticker, err)
+			return false
+		}
+		result.Quarantined++
+		return false
+	}
+
+	outcome, err := s.stocks.Upsert(stock)
+	if err != nil {
+		result.Failed++
+		log.Printf("⚠️ Error guardando %s: %v", stock.Ticker, err)
+		return false
+	}
+	result.Record(outcome)
+	return true
+}
+
+// preview clasifica el evento contra lo guardado sin escribir nada.
+// Devuelve false si el registro no era válido.
+func (s *StockService) preview(stock models.Stock, result *SyncResult) bool {
+	if issues := ValidateStock(stock, s.ratings.Taxonomy(), s.clock()); len(issues) > 0 {
+		result.Diff.addInvalid(stock, issues)
+		return false
+	}
+
+	existing, err := s.stocks.FindByNaturalKey(stock)
+	if err != nil {
+		result.Failed++
+		log.Printf("⚠️ Error comparando %s: %v", stock.Ticker, err)
+		return true
+	}
+	result.Diff.add(stock, existing)
+	return true
+}
+
+// quarantineStock guarda el registro inválido con sus motivos; si ya estaba
+// en cuarentena (de una sincronización anterior) no se duplica
+func (s *StockService) quarantineStock(stock models.Stock, issues []ValidationIssue) error {
+	q := models.NewQuarantinedStock(stock, joinIssues(issues))
+	added, err := s.quarantine.Add(&q)
+	if err != nil {
+		return err
+	}
+	if added {
+		log.Printf("🚧 %s (%s) en cuarentena: %s", stock.Ticker, stock.Provider, q.Reasons)
+	}
 	return nil
 }
 
-// GetStocks devuelve una lista de stocks con paginación
-func (s *StockService) GetStocks(page, pageSize int, search string) ([]models.Stock, int64, error) {
-	var stocks []models.Stock
-	var total int64
+// CheckSync valida que el proveedor exista y soporte el modo pedido,
+// y devuelve su nombre (vacío se resuelve al default)
+func (s *StockService) CheckSync(provider string, mode SyncMode) (string, error) {
+	p, err := s.resolveProvider(provider, mode)
+	if err != nil {
+		return "", err
+	}
+	return p.Name(), nil
+}
+
+func (s *StockService) resolveProvider(name string, mode SyncMode) (external.RatingsProvider, error) {
+	provider, err := s.providers.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if mode == SyncModeIncremental && !provider.Capabilities().NewestFirst {
+		return nil, fmt.Errorf("%w: %s", ErrIncrementalUnsupported, provider.Name())
+	}
+	return provider, nil
+}
+
+// toStockModel convierte un item normalizado del proveedor al modelo
+func toStockModel(item dto.Stock, provider string) models.Stock {
+	return models.Stock{
+		Ticker:     item.Ticker,
+		Company:    item.Company,
+		Brokerage:  item.Brokerage,
+		Action:     item.Action,
+		RatingFrom: item.RatingFrom,
+		RatingTo:   item.RatingTo,
+		TargetFrom: item.TargetFrom,
+		TargetTo:   item.TargetTo,
+		Time:       item.Time,
+		RawTime:    item.RawTime,
+		ParseError: item.ParseError,
+		Provider:   provider,
+	}
+}
+
+// isAlreadyIngested indica si el evento es igual o anterior al último
+// recorrido completo (el proveedor entrega los eventos del más nuevo al más viejo)
+func isAlreadyIngested(state *models.SyncState, stock models.Stock) bool {
+	return !state.LatestTime.IsZero() && !stock.Time.After(state.LatestTime)
+}
+
+// completeSyncState consolida el checkpoint al terminar un recorrido en now;
+// advance en false conserva LatestTime
+func completeSyncState(state *models.SyncState, mode SyncMode, advance bool, now time.Time) {
+	if advance && state.PendingLatestTime.After(state.LatestTime) {
+		state.LatestTime = state.PendingLatestTime
+	}
+	state.NextPage = ""
+	state.PendingLatestTime = time.Time{}
+	state.LastMode = string(mode)
+	state.LastCompletedAt = &now
+}
+
+// GetStocks devuelve una lista de stocks filtrada y con paginación
+func (s *StockService) GetStocks(query repository.StockQuery) ([]models.Stock, int64, error) {
+	return s.stocks.Search(query)
+}
+
+// RecommendRequest configura una recomendación
+type RecommendRequest struct {
+	Limit int
+	Mode  stock.Mode
+	// Profile es el nombre del perfil de scoring; vacío usa el default
+	Profile string
+	// AsOf recomienda como si fuera ese momento: solo cuentan los eventos
+	// publicados hasta AsOf y la frescura se mide contra él. Cero es ahora.
+	AsOf time.Time
+	// Weights elige los pesos de credibilidad; vacío usa los configurados
+	Weights WeightSource
+}
+
+// ParseAsOf interpreta ?as_of= como RFC3339 o como fecha 2006-01-02, que
+// cuenta hasta el final de ese día en UTC; vacío es ahora (cero)
+func ParseAsOf(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if day, err := time.Parse("2006-01-02", s); err == nil {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: %q (usa 2006-01-02 o RFC3339)", ErrInvalidAsOf, s)
+}
 
-	query := db.DB.Model(&models.Stock{})
+// GetRecommend devuelve el top de recomendaciones con el modo y perfil pedidos
+func (s *StockService) GetRecommend(req RecommendRequest) ([]stock.StockRecommendation, error) {
+	profile, err := s.profiles.Get(req.Profile)
+	if err != nil {
+		return nil, err
+	}
 
-	// Si el usuario pasó un search, filtramos
-	if search != "" {
-		like := "%" + strings.ToLower(search) + "%"
-		query = query.Where(
-			db.DB.Where("LOWER(Ticker) LIKE ?", like).
-				Or("LOWER(Company) LIKE ?", like).
-				Or("LOWER(Brokerage) LIKE ?", like),
-		)
+	clock := s.clock
+	if !req.AsOf.IsZero() {
+		if req.AsOf.After(s.clock()) {
+			return nil, fmt.Errorf("%w: %s está en el futuro", ErrInvalidAsOf, req.AsOf.Format(time.RFC3339))
+		}
+		clock = stock.FixedClock(req.AsOf)
 	}
 
-	// contar el total de registros filtrados
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	stocks, err := s.stocks.ListForRecommendation()
+	if err != nil {
+		return nil, err
+	}
+	if !req.AsOf.IsZero() {
+		stocks = stock.PublishedBy(stocks, req.AsOf)
 	}
 
-	// aplicar paginación + ordenamiento
-	offset := (page - 1) * pageSize
-	if err := query.Limit(pageSize).Offset(offset).Order("time DESC").Find(&stocks).Error; err != nil {
-		return nil, 0, err
+	weights := s.brokerageWeights(stocks)
+	if req.Weights == WeightsMeasured {
+		if weights, err = s.measuredWeights(stocks, weights); err != nil {
+			return nil, err
+		}
 	}
 
-	return stocks, total, nil
+	return stock.Recommend(stocks, stock.Options{
+		Limit:            req.Limit,
+		Mode:             req.Mode,
+		Profile:          profile,
+		BrokerageWeights: weights,
+		Clock:            clock,
+	}), nil
 }
 
-func (s *StockService) GetRecommend(limit int) ([]stock.StockRecommendation, error) {
-	var stocks []models.Stock
-	if err := db.DB.Find(&stocks).Error; err != nil {
+// measuredWeights reemplaza los pesos configurados por los medidos en el
+// leaderboard donde los haya
+func (s *StockService) measuredWeights(stocks []models.Stock, configured map[string]float64) (map[string]float64, error) {
+	if s.leaderboard == nil {
+		return nil, fmt.Errorf("%w: falta LEADERBOARD_PRICES_DIR", ErrLeaderboardUnavailable)
+	}
+	measured, err := s.leaderboard.Weights(stocks)
+	if err != nil {
 		return nil, err
 	}
+	weights := make(map[string]float64, len(configured)+len(measured))
+	for name, w := range configured {
+		weights[name] = w
+	}
+	for name, w := range measured {
+		weights[name] = w
+	}
+	return weights, nil
+}
 
-	return stock.RecommendStocks(stocks, limit), nil
+// GetStats resume los eventos guardados: totales, rango de fechas y por proveedor
+func (s *StockService) GetStats() (*repository.StockStats, error) {
+	return s.stocks.Stats()
 }
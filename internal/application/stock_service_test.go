@@ -1,12 +1,21 @@
 package application
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/stock"
 	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
 	"github.com/juanF18/EquiSignal-Backend/internal/interface/dto"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/external"
 )
 
 func TestDTOToModelTransformation(t *testing.T) {
@@ -205,3 +214,305 @@ func TestPaginationCalculation(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncResultRecord(t *testing.T) {
+	result := &SyncResult{}
+
+	result.Record(repository.UpsertInserted)
+	result.Record(repository.UpsertInserted)
+	result.Record(repository.UpsertUpdated)
+	result.Record(repository.UpsertUnchanged)
+
+	if result.Inserted != 2 || result.Updated != 1 || result.Unchanged != 1 {
+		t.Errorf("Unexpected counts: %+v", result)
+	}
+
+	if result.Failed != 0 {
+		t.Errorf("Expected 0 failed, got %d", result.Failed)
+	}
+}
+
+func TestParseSyncMode(t *testing.T) {
+	testCases := []struct {
+		input     string
+		expected  SyncMode
+		expectErr bool
+	}{
+		{"", SyncModeFull, false},
+		{"full", SyncModeFull, false},
+		{"incremental", SyncModeIncremental, false},
+		{"partial", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			mode, err := ParseSyncMode(tc.input)
+
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tc.expectErr, err)
+			}
+			if mode != tc.expected {
+				t.Errorf("Expected mode %q, got %q", tc.expected, mode)
+			}
+		})
+	}
+}
+
+func TestSyncCheckpoint(t *testing.T) {
+	latest := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	state := &models.SyncState{Name: "default", LatestTime: latest}
+
+	// Eventos iguales o anteriores al último recorrido completo ya se ingirieron
+	if !isAlreadyIngested(state, models.Stock{Time: latest}) {
+		t.Error("Event at latest time should be considered ingested")
+	}
+	if isAlreadyIngested(state, models.Stock{Time: latest.Add(time.Minute)}) {
+		t.Error("Newer event should not be considered ingested")
+	}
+	if isAlreadyIngested(&models.SyncState{}, models.Stock{Time: latest}) {
+		t.Error("Without checkpoint nothing should be considered ingested")
+	}
+
+	// Al completar se consolida el tiempo pendiente y se limpia el token
+	state.NextPage = "page-3"
+	state.PendingLatestTime = latest.Add(time.Hour)
+	completed := latest.Add(2 * time.Hour)
+	completeSyncState(state, SyncModeIncremental, true, completed)
+
+	if state.NextPage != "" {
+		t.Errorf("Expected empty next page, got %q", state.NextPage)
+	}
+	if !state.LatestTime.Equal(latest.Add(time.Hour)) {
+		t.Errorf("Expected latest time to advance, got %v", state.LatestTime)
+	}
+	if !state.PendingLatestTime.IsZero() || state.LastCompletedAt == nil || !state.LastCompletedAt.Equal(completed) {
+		t.Error("Expected pending time cleared and completion time set")
+	}
+}
+
+func TestStockService_GetStocks(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := repository.NewMemoryStockRepository(
+		models.Stock{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "UBS", Time: base},
+		models.Stock{Ticker: "MSFT", Company: "Microsoft", Brokerage: "Citi", Time: base.Add(time.Hour)},
+		models.Stock{Ticker: "TSLA", Company: "Tesla", Brokerage: "UBS", Time: base.Add(2 * time.Hour)},
+	)
+	service := NewStockService(nil, repo, nil, nil)
+
+	stocks, total, err := service.GetStocks(repository.StockQuery{Page: 1, PageSize: 10, Search: "ubs"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if total != 2 || len(stocks) != 2 || stocks[0].Ticker != "TSLA" {
+		t.Errorf("Expected TSLA and AAPL, got %+v", stocks)
+	}
+}
+
+func TestStockService_GetRecommend(t *testing.T) {
+	now := time.Now()
+	repo := repository.NewMemoryStockRepository(
+		models.Stock{Ticker: "AAPL", Company: "Apple Inc.", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Strong Buy", TargetFrom: "$150.00", TargetTo: "$200.00", Time: now},
+		models.Stock{Ticker: "MSFT", Company: "Microsoft", Action: "downgraded by", RatingFrom: "Buy", RatingTo: "Sell", TargetFrom: "$400.00", TargetTo: "$300.00", Time: now},
+	)
+	service := NewStockService(nil, repo, nil, nil)
+
+	for _, mode := range []stock.Mode{stock.ModeEvent, stock.ModeTicker} {
+		recs, err := service.GetRecommend(RecommendRequest{Limit: 1, Mode: mode})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(recs) != 1 || recs[0].Ticker != "AAPL" {
+			t.Errorf("Expected AAPL as top %s recommendation, got %+v", mode, recs)
+		}
+	}
+}
+
+func TestStockService_GetRecommendAsOf(t *testing.T) {
+	jan := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+	repo := repository.NewMemoryStockRepository(
+		models.Stock{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "UBS", Action: "reiterated by", RatingFrom: "Buy", RatingTo: "Buy", Time: jan},
+		models.Stock{Ticker: "MSFT", Company: "Microsoft", Brokerage: "UBS", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Strong Buy", Time: jan.AddDate(0, 0, 10)},
+	)
+	service := NewStockService(nil, repo, nil, nil)
+	service.SetClock(stock.FixedClock(jan.AddDate(0, 3, 0)))
+
+	asOf, err := ParseAsOf("2025-01-31")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	recs, err := service.GetRecommend(RecommendRequest{Limit: 5, AsOf: asOf})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// MSFT se publicó después de as_of; AAPL tenía 12 horas en ese momento
+	if len(recs) != 1 || recs[0].Ticker != "AAPL" {
+		t.Fatalf("Expected only AAPL as of 2025-01-31, got %+v", recs)
+	}
+	if code := recs[0].Breakdown[2].Codes[0]; code != "freshness_breaking_news" {
+		t.Errorf("Expected freshness measured against as_of, got %s", code)
+	}
+
+	// Sin as_of se usa el reloj del servicio: los dos eventos y AAPL ya viejo
+	recs, _ = service.GetRecommend(RecommendRequest{Limit: 5})
+	if len(recs) != 2 || recs[0].Ticker != "MSFT" {
+		t.Errorf("Expected MSFT first with every event, got %+v", recs)
+	}
+
+	if _, err := service.GetRecommend(RecommendRequest{Limit: 5, AsOf: jan.AddDate(1, 0, 0)}); !errors.Is(err, ErrInvalidAsOf) {
+		t.Errorf("Expected ErrInvalidAsOf for a future as_of, got %v", err)
+	}
+	if _, err := ParseAsOf("31/01/2025"); !errors.Is(err, ErrInvalidAsOf) {
+		t.Errorf("Expected ErrInvalidAsOf for an unknown format, got %v", err)
+	}
+}
+
+func TestStockService_BadRowsAreQuarantinedWithoutLosingThePage(t *testing.T) {
+	dir := t.TempDir()
+	csv := "ticker,company,rating_to,time\n" +
+		"AAPL,Apple Inc.,Buy,2025-01-10\n" +
+		"MSFT,Microsoft,Buy,ayer\n" +
+		"NVDA,NVIDIA,Buy,2025-01-11\n" +
+		"AMD,\"AMD Inc\"x,Buy,2025-01-12\n"
+	if err := os.WriteFile(filepath.Join(dir, "ratings.csv"), []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := external.NewFileProvider("files", dir).FetchPage(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	service := NewStockService(nil, repository.NewMemoryStockRepository(), nil, nil)
+	result := &SyncResult{Diff: newSyncDiff()}
+	for _, item := range page.Items {
+		service.preview(toStockModel(item, "files"), result)
+	}
+
+	if result.Diff.New != 2 || result.Diff.Invalid != 2 {
+		t.Fatalf("Expected 2 new and 2 invalid, got new=%d invalid=%d", result.Diff.New, result.Diff.Invalid)
+	}
+	invalid := result.Diff.InvalidSamples[0]
+	if invalid.Stock.Ticker != "MSFT" || len(invalid.Issues) != 1 ||
+		invalid.Issues[0].Code != IssueInvalidTime || !strings.Contains(invalid.Issues[0].Message, `"ayer"`) {
+		t.Errorf("Unexpected invalid record: %+v", invalid)
+	}
+	// La fila rota también va a cuarentena, con el archivo y la línea
+	unreadable := result.Diff.InvalidSamples[1]
+	if len(unreadable.Issues) != 1 || unreadable.Issues[0].Code != IssueUnreadable ||
+		!strings.HasPrefix(unreadable.Issues[0].Message, "ratings.csv línea 5:") {
+		t.Errorf("Unexpected unreadable record: %+v", unreadable)
+	}
+}
+
+// fakeProvider entrega páginas fijas, del evento más nuevo al más viejo
+type fakeProvider struct {
+	pages [][]dto.Stock
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func (p *fakeProvider) Capabilities() external.Capabilities {
+	return external.Capabilities{Paginated: true, NewestFirst: true}
+}
+
+func (p *fakeProvider) FetchPage(ctx context.Context, pageToken string) (*external.Page, error) {
+	idx := 0
+	if pageToken != "" {
+		idx, _ = strconv.Atoi(pageToken)
+	}
+	page := &external.Page{Items: p.pages[idx]}
+	if idx+1 < len(p.pages) {
+		page.NextPage = strconv.Itoa(idx + 1)
+	}
+	return page, nil
+}
+
+// failingStockRepository falla al guardar los tickers marcados
+type failingStockRepository struct {
+	*repository.MemoryStockRepository
+	fail map[string]bool
+}
+
+func (r *failingStockRepository) Upsert(stock models.Stock) (repository.UpsertOutcome, error) {
+	if r.fail[stock.Ticker] {
+		return 0, errors.New("conexión perdida")
+	}
+	return r.MemoryStockRepository.Upsert(stock)
+}
+
+func newFakeSyncService(t *testing.T, provider external.RatingsProvider, stocks repository.StockRepository, states repository.SyncStateRepository) *StockService {
+	t.Helper()
+	registry := external.NewRegistry()
+	if err := registry.Register(provider); err != nil {
+		t.Fatal(err)
+	}
+	return NewStockService(registry, stocks, states, nil)
+}
+
+func TestStockService_SyncDoesNotAdvanceCheckpointPastFailedEvents(t *testing.T) {
+	known := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	provider := &fakeProvider{pages: [][]dto.Stock{{
+		{Ticker: "AAPL", Company: "Apple Inc.", RatingTo: "Buy", Time: known.Add(2 * time.Hour)},
+		{Ticker: "MSFT", Company: "Microsoft", RatingTo: "Buy", Time: known.Add(time.Hour)},
+		{Ticker: "OLD", Company: "Old Corp", RatingTo: "Buy", Time: known},
+	}}}
+	states := repository.NewMemorySyncStateRepository()
+	states.Save(&models.SyncState{Name: "fake", LatestTime: known})
+	stocks := &failingStockRepository{MemoryStockRepository: repository.NewMemoryStockRepository(), fail: map[string]bool{"MSFT": true}}
+	service := newFakeSyncService(t, provider, stocks, states)
+
+	result, err := service.Sync(context.Background(), SyncOptions{Mode: SyncModeIncremental})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Inserted != 2 || result.Failed != 1 {
+		t.Errorf("Expected 2 inserted and 1 failed, got %+v", result)
+	}
+	state, _ := states.Get("fake")
+	if !state.LatestTime.Equal(known) {
+		t.Errorf("Expected checkpoint to stay at %v, got %v", known, state.LatestTime)
+	}
+
+	// El siguiente incremental reintenta el evento que falló
+	stocks.fail = nil
+	result, err = service.Sync(context.Background(), SyncOptions{Mode: SyncModeIncremental})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Inserted != 1 || result.Failed != 0 {
+		t.Errorf("Expected the failed event to be inserted, got %+v", result)
+	}
+	state, _ = states.Get("fake")
+	if !state.LatestTime.Equal(known.Add(2 * time.Hour)) {
+		t.Errorf("Expected checkpoint to advance, got %v", state.LatestTime)
+	}
+}
+
+func TestStockService_SyncUsesClock(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	provider := &fakeProvider{pages: [][]dto.Stock{{
+		{Ticker: "AAPL", Company: "Apple Inc.", RatingTo: "Buy", Time: now.Add(-time.Hour)},
+		// Futuro para el reloj del servicio aunque sea pasado para el sistema
+		{Ticker: "MSFT", Company: "Microsoft", RatingTo: "Buy", Time: now.Add(72 * time.Hour)},
+	}}}
+	states := repository.NewMemorySyncStateRepository()
+	service := newFakeSyncService(t, provider, repository.NewMemoryStockRepository(), states)
+	service.SetClock(stock.FixedClock(now))
+
+	preview, err := service.Sync(context.Background(), SyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if preview.Diff.New != 1 || preview.Diff.Invalid != 1 || preview.Diff.InvalidSamples[0].Issues[0].Code != IssueInvalidTime {
+		t.Errorf("Expected the future event to be invalid, got %+v", preview.Diff)
+	}
+
+	provider.pages[0] = provider.pages[0][:1]
+	if _, err := service.Sync(context.Background(), SyncOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	state, _ := states.Get("fake")
+	if state.LastCompletedAt == nil || !state.LastCompletedAt.Equal(now) {
+		t.Errorf("Expected completion at %v, got %v", now, state.LastCompletedAt)
+	}
+}
@@ -0,0 +1,47 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+// ErrInvalidSort se devuelve cuando el parámetro sort pide un campo no permitido
+var ErrInvalidSort = errors.New("orden inválido")
+
+// ParseStockSort interpreta ?sort=-target_change,ticker: campos separados por
+// coma en orden de prioridad; el prefijo "-" es descendente y "+" (o nada)
+// ascendente. Vacío devuelve nil y el repositorio usa time DESC.
+func ParseStockSort(raw string) ([]repository.SortField, error) {
+	var fields []repository.SortField
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		desc := false
+		switch part[0] {
+		case '-':
+			desc, part = true, part[1:]
+		case '+':
+			part = part[1:]
+		}
+		field := strings.ToLower(part)
+
+		if !repository.IsSortable(field) {
+			return nil, fmt.Errorf("%w: campo %q no permitido (permitidos: %s)",
+				ErrInvalidSort, part, strings.Join(repository.SortableFields(), ", "))
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("%w: campo %q repetido", ErrInvalidSort, field)
+		}
+		seen[field] = true
+		fields = append(fields, repository.SortField{Field: field, Desc: desc})
+	}
+	return fields, nil
+}
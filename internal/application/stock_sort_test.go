@@ -0,0 +1,49 @@
+package application
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+func TestParseStockSort(t *testing.T) {
+	fields, err := ParseStockSort(" -target_change, Ticker ,+time")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []repository.SortField{
+		{Field: "target_change", Desc: true},
+		{Field: "ticker"},
+		{Field: "time"},
+	}
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Errorf("Expected %v at %d, got %v", expected[i], i, fields[i])
+		}
+	}
+
+	if fields, err := ParseStockSort(""); err != nil || fields != nil {
+		t.Errorf("Expected default sort for empty value, got %v %v", fields, err)
+	}
+}
+
+func TestParseStockSort_Invalid(t *testing.T) {
+	_, err := ParseStockSort("-price")
+	if !errors.Is(err, ErrInvalidSort) {
+		t.Fatalf("Expected ErrInvalidSort, got %v", err)
+	}
+	// El mensaje lista los campos permitidos
+	if !strings.Contains(err.Error(), "target_change") {
+		t.Errorf("Expected allowed fields in message, got %q", err.Error())
+	}
+
+	if _, err := ParseStockSort("ticker,-ticker"); !errors.Is(err, ErrInvalidSort) {
+		t.Errorf("Expected ErrInvalidSort for repeated field, got %v", err)
+	}
+}
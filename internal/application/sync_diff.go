@@ -0,0 +1,92 @@
+package application
+
+import (
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+// dryRunSampleSize es cuántos ejemplos de cada tipo guarda el diff
+const dryRunSampleSize = 10
+
+// StockChange es un evento que ya existe y que la sincronización modificaría
+type StockChange struct {
+	Before models.Stock `json:"before"`
+	After  models.Stock `json:"after"`
+	Fields []string     `json:"fields"`
+}
+
+// InvalidRecord es un evento que la sincronización pondría en cuarentena
+type InvalidRecord struct {
+	Stock  models.Stock      `json:"stock"`
+	Issues []ValidationIssue `json:"issues"`
+}
+
+// SyncDiff resume lo que haría una sincronización sin escribir nada:
+// cuántos eventos serían nuevos, cambiarían, quedarían igual o irían a cuarentena
+type SyncDiff struct {
+	New       int `json:"new"`
+	Changed   int `json:"changed"`
+	Unchanged int `json:"unchanged"`
+	Invalid   int `json:"invalid"`
+
+	NewSamples       []models.Stock  `json:"new_samples"`
+	ChangedSamples   []StockChange   `json:"changed_samples"`
+	UnchangedSamples []models.Stock  `json:"unchanged_samples"`
+	InvalidSamples   []InvalidRecord `json:"invalid_samples"`
+
+	// seen evita contar dos veces un evento repetido en el mismo recorrido;
+	// la sincronización real lo insertaría una vez y luego lo vería igual
+	seen map[string]bool
+}
+
+func newSyncDiff() *SyncDiff {
+	return &SyncDiff{
+		NewSamples:       []models.Stock{},
+		ChangedSamples:   []StockChange{},
+		UnchangedSamples: []models.Stock{},
+		InvalidSamples:   []InvalidRecord{},
+		seen:             make(map[string]bool),
+	}
+}
+
+// addInvalid registra un evento que no pasó la validación
+func (d *SyncDiff) addInvalid(stock models.Stock, issues []ValidationIssue) {
+	d.Invalid++
+	if len(d.InvalidSamples) < dryRunSampleSize {
+		d.InvalidSamples = append(d.InvalidSamples, InvalidRecord{Stock: stock, Issues: issues})
+	}
+}
+
+// add clasifica un evento válido contra lo guardado (existing nil = no existe)
+func (d *SyncDiff) add(stock models.Stock, existing *models.Stock) {
+	// Igual que el upsert, que compara con los precios ya interpretados
+	stock.ParseTargets()
+	key := stock.NaturalKey()
+	repeated := d.seen[key]
+	d.seen[key] = true
+
+	switch {
+	case repeated:
+		d.Unchanged++
+	case existing == nil:
+		d.New++
+		if len(d.NewSamples) < dryRunSampleSize {
+			d.NewSamples = append(d.NewSamples, stock)
+		}
+	case existing.SameAttributes(stock):
+		d.Unchanged++
+		if len(d.UnchangedSamples) < dryRunSampleSize {
+			d.UnchangedSamples = append(d.UnchangedSamples, *existing)
+		}
+	default:
+		d.Changed++
+		if len(d.ChangedSamples) < dryRunSampleSize {
+			after := *existing
+			after.CopyAttributes(stock)
+			d.ChangedSamples = append(d.ChangedSamples, StockChange{
+				Before: *existing,
+				After:  after,
+				Fields: existing.ChangedFields(stock),
+			})
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+func TestSyncDiff_Classify(t *testing.T) {
+	diff := newSyncDiff()
+	eventTime := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	fresh := models.Stock{Ticker: "NVDA", Company: "NVIDIA", Time: eventTime, Provider: "primary"}
+	stored := models.Stock{Ticker: "AAPL", Company: "Apple", RatingFrom: "Hold", Time: eventTime, Provider: "primary"}
+	same := stored
+	changed := stored
+	changed.Ticker = "MSFT"
+	storedChanged := changed
+	changed.RatingFrom = "Buy"
+
+	diff.add(fresh, nil)
+	diff.add(fresh, nil) // repetido en el mismo recorrido
+	diff.add(same, &stored)
+	diff.add(changed, &storedChanged)
+	diff.addInvalid(models.Stock{Ticker: "bad"}, []ValidationIssue{{Field: "ticker", Code: IssueInvalidTicker}})
+
+	if diff.New != 1 || diff.Unchanged != 2 || diff.Changed != 1 || diff.Invalid != 1 {
+		t.Errorf("Unexpected counts: new=%d unchanged=%d changed=%d invalid=%d", diff.New, diff.Unchanged, diff.Changed, diff.Invalid)
+	}
+	if len(diff.NewSamples) != 1 || diff.NewSamples[0].Ticker != "NVDA" {
+		t.Errorf("Unexpected new samples: %+v", diff.NewSamples)
+	}
+
+	if len(diff.ChangedSamples) != 1 {
+		t.Fatalf("Expected 1 changed sample, got %d", len(diff.ChangedSamples))
+	}
+	change := diff.ChangedSamples[0]
+	if change.Before.RatingFrom != "Hold" || change.After.RatingFrom != "Buy" {
+		t.Errorf("Expected rating_from Hold -> Buy, got %s -> %s", change.Before.RatingFrom, change.After.RatingFrom)
+	}
+	if len(change.Fields) != 1 || change.Fields[0] != "rating_from" {
+		t.Errorf("Expected changed fields [rating_from], got %v", change.Fields)
+	}
+}
+
+func TestSyncDiff_LimitsSamples(t *testing.T) {
+	diff := newSyncDiff()
+	for i := 0; i < dryRunSampleSize+5; i++ {
+		diff.add(models.Stock{Ticker: "AAPL", Time: time.Unix(int64(i), 0)}, nil)
+	}
+
+	if diff.New != dryRunSampleSize+5 {
+		t.Errorf("Expected %d new, got %d", dryRunSampleSize+5, diff.New)
+	}
+	if len(diff.NewSamples) != dryRunSampleSize {
+		t.Errorf("Expected %d samples, got %d", dryRunSampleSize, len(diff.NewSamples))
+	}
+}
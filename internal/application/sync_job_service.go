@@ -0,0 +1,204 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSyncJobNotFound   = errors.New("job de sincronización no encontrado")
+	ErrSyncJobNotRunning = errors.New("el job de sincronización ya terminó")
+)
+
+// SyncJobService ejecuta sincronizaciones en segundo plano y persiste su progreso
+type SyncJobService struct {
+	stocks *StockService
+	jobs   repository.SyncJobRepository
+
+	mu      sync.Mutex
+	running map[uuid.UUID]*runningSyncJob
+}
+
+// runningSyncJob permite cancelar un job en curso y esperar a que termine
+type runningSyncJob struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewSyncJobService(stocks *StockService, jobs repository.SyncJobRepository) *SyncJobService {
+	return &SyncJobService{
+		stocks:  stocks,
+		jobs:    jobs,
+		running: make(map[uuid.UUID]*runningSyncJob),
+	}
+}
+
+// RecoverInterrupted cierra los jobs que quedaron abiertos al reiniciar el proceso
+func (s *SyncJobService) RecoverInterrupted() error {
+	n, err := s.jobs.FailUnfinished("interrumpido por reinicio del servidor")
+	if n > 0 {
+		log.Printf("⚠️ %d jobs de sincronización quedaron interrumpidos", n)
+	}
+	return err
+}
+
+// Start crea el job y lanza la sincronización en segundo plano.
+// Devuelve ErrSyncInProgress si ya hay otro job corriendo.
+func (s *SyncJobService) Start(provider string, mode SyncMode) (*models.SyncJob, error) {
+	job, _, err := s.start(provider, mode)
+	return job, err
+}
+
+// RunAndWait inicia un job y espera a que termine; si ctx se cancela antes,
+// el job también se cancela. Lo usa el scheduler para no solapar ejecuciones.
+func (s *SyncJobService) RunAndWait(ctx context.Context, provider string, mode SyncMode) (*models.SyncJob, error) {
+	job, running, err := s.start(provider, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-running.done:
+	case <-ctx.Done():
+		running.cancel()
+		<-running.done
+	}
+	return s.Get(job.ID)
+}
+
+func (s *SyncJobService) start(provider string, mode SyncMode) (*models.SyncJob, *runningSyncJob, error) {
+	provider, err := s.stocks.CheckSync(provider, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.running) > 0 {
+		return nil, nil, ErrSyncInProgress
+	}
+
+	now := time.Now()
+	job := &models.SyncJob{
+		ID:        uuid.New(),
+		Provider:  provider,
+		Mode:      string(mode),
+		Status:    models.SyncJobRunning,
+		StartedAt: &now,
+	}
+	if err := s.jobs.Create(job); err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	running := &runningSyncJob{cancel: cancel, done: make(chan struct{})}
+	s.running[job.ID] = running
+
+	snapshot := *job
+	go s.run(ctx, job, running)
+
+	return &snapshot, running, nil
+}
+
+// run ejecuta la sincronización y guarda el progreso después de cada página
+func (s *SyncJobService) run(ctx context.Context, job *models.SyncJob, running *runningSyncJob) {
+	defer close(running.done)
+	defer running.cancel()
+
+	result, err := s.stocks.Sync(ctx, SyncOptions{
+		Provider: job.Provider,
+		Mode:     SyncMode(job.Mode),
+		OnPage: func(progress SyncResult) {
+			applySyncProgress(job, progress)
+			if err := s.jobs.Save(job); err != nil {
+				log.Printf("⚠️ Error guardando progreso del job %s: %v", job.ID, err)
+			}
+		},
+	})
+
+	// El estado final se decide con el job ya fuera de running y bajo el mismo
+	// lock que Cancel: un cancel que llega después ya no lo encuentra, y uno
+	// que llegó con la sincronización terminada bien no la marca cancelada
+	s.mu.Lock()
+	delete(s.running, job.ID)
+	applySyncProgress(job, *result)
+	finishSyncJob(job, err, ctx.Err() != nil)
+	s.mu.Unlock()
+
+	if err := s.jobs.Save(job); err != nil {
+		log.Printf("⚠️ Error guardando job %s: %v", job.ID, err)
+	}
+	log.Printf("🏁 Job de sincronización %s terminó con estado %s", job.ID, job.Status)
+}
+
+// Get devuelve el job con su progreso actual
+func (s *SyncJobService) Get(id uuid.UUID) (*models.SyncJob, error) {
+	job, err := s.jobs.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSyncJobNotFound
+	}
+	return job, err
+}
+
+// List devuelve el historial de jobs, los más recientes primero
+func (s *SyncJobService) List(limit int) ([]models.SyncJob, error) {
+	return s.jobs.List(limit)
+}
+
+// Cancel pide detener un job en curso; el job termina en estado cancelled
+// después de la página que esté procesando
+func (s *SyncJobService) Cancel(id uuid.UUID) (*models.SyncJob, error) {
+	s.mu.Lock()
+	running, ok := s.running[id]
+	if ok {
+		running.cancel()
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		job, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		return job, ErrSyncJobNotRunning
+	}
+	return s.Get(id)
+}
+
+// applySyncProgress copia los contadores de la sincronización al job
+func applySyncProgress(job *models.SyncJob, progress SyncResult) {
+	job.PagesFetched = progress.Pages
+	job.Inserted = progress.Inserted
+	job.Updated = progress.Updated
+	job.Unchanged = progress.Unchanged
+	job.ItemsStored = progress.Inserted + progress.Updated + progress.Unchanged
+	job.ItemsFailed = progress.Failed
+	job.ItemsQuarantined = progress.Quarantined
+}
+
+// finishSyncJob asigna el estado final según el error y si se canceló; una
+// sincronización que terminó bien cuenta como exitosa aunque la cancelación
+// haya llegado después
+func finishSyncJob(job *models.SyncJob, err error, cancelled bool) {
+	now := time.Now()
+	job.FinishedAt = &now
+
+	switch {
+	case err == nil:
+		job.Status = models.SyncJobSucceeded
+	case cancelled:
+		job.Status = models.SyncJobCancelled
+	default:
+		job.Status = models.SyncJobFailed
+		job.Error = err.Error()
+	}
+}
@@ -0,0 +1,225 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/dto"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/external"
+)
+
+func TestApplySyncProgress(t *testing.T) {
+	job := &models.SyncJob{}
+
+	applySyncProgress(job, SyncResult{Pages: 3, Inserted: 10, Updated: 2, Unchanged: 5, Failed: 1})
+
+	if job.PagesFetched != 3 {
+		t.Errorf("Expected 3 pages, got %d", job.PagesFetched)
+	}
+	if job.ItemsStored != 17 {
+		t.Errorf("Expected 17 items stored, got %d", job.ItemsStored)
+	}
+	if job.ItemsFailed != 1 {
+		t.Errorf("Expected 1 item failed, got %d", job.ItemsFailed)
+	}
+}
+
+func TestFinishSyncJob(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       error
+		cancelled bool
+		expected  models.SyncJobStatus
+	}{
+		{"Success", nil, false, models.SyncJobSucceeded},
+		{"Failure", errors.New("API externa respondió con código 500"), false, models.SyncJobFailed},
+		{"Cancelled", context.Canceled, true, models.SyncJobCancelled},
+		{"Cancelled after success", nil, true, models.SyncJobSucceeded},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &models.SyncJob{Status: models.SyncJobRunning}
+
+			finishSyncJob(job, tc.err, tc.cancelled)
+
+			if job.Status != tc.expected {
+				t.Errorf("Expected status %s, got %s", tc.expected, job.Status)
+			}
+			if job.FinishedAt == nil {
+				t.Error("Expected FinishedAt to be set")
+			}
+			if !job.Status.IsTerminal() {
+				t.Errorf("Status %s should be terminal", job.Status)
+			}
+		})
+	}
+}
+
+// blockingProvider entrega una sola página, pero no responde hasta que se
+// cierra release o se cancela el contexto
+type blockingProvider struct {
+	started chan struct{}
+	release chan struct{}
+	// onRelease corre justo antes de entregar la página
+	onRelease func()
+}
+
+func newBlockingProvider() *blockingProvider {
+	return &blockingProvider{started: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (p *blockingProvider) Name() string { return "fake" }
+
+func (p *blockingProvider) Capabilities() external.Capabilities {
+	return external.Capabilities{Paginated: true, NewestFirst: true}
+}
+
+func (p *blockingProvider) FetchPage(ctx context.Context, pageToken string) (*external.Page, error) {
+	select {
+	case p.started <- struct{}{}:
+	default:
+	}
+
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if p.onRelease != nil {
+		p.onRelease()
+	}
+	return &external.Page{Items: []dto.Stock{
+		{Ticker: "AAPL", Company: "Apple Inc.", RatingTo: "Buy", Time: time.Now().Add(-time.Hour)},
+	}}, nil
+}
+
+func newTestSyncJobService(t *testing.T, provider external.RatingsProvider) *SyncJobService {
+	t.Helper()
+	stocks := newFakeSyncService(t, provider, repository.NewMemoryStockRepository(), repository.NewMemorySyncStateRepository())
+	return NewSyncJobService(stocks, repository.NewMemorySyncJobRepository())
+}
+
+// startBlockedJob inicia un job y espera a que el proveedor esté bloqueado
+func startBlockedJob(t *testing.T, s *SyncJobService, provider *blockingProvider) *models.SyncJob {
+	t.Helper()
+	job, err := s.Start("fake", SyncModeFull)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	select {
+	case <-provider.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("el proveedor nunca recibió la petición")
+	}
+	return job
+}
+
+// waitSyncJob espera a que el job termine y devuelve su estado final
+func waitSyncJob(t *testing.T, s *SyncJobService, id uuid.UUID) *models.SyncJob {
+	t.Helper()
+	s.mu.Lock()
+	running, ok := s.running[id]
+	s.mu.Unlock()
+
+	if ok {
+		select {
+		case <-running.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("el job no terminó")
+		}
+	}
+
+	job, err := s.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	return job
+}
+
+func TestSyncJobService_RunsToCompletion(t *testing.T) {
+	provider := newBlockingProvider()
+	s := newTestSyncJobService(t, provider)
+
+	job := startBlockedJob(t, s, provider)
+	if job.Status != models.SyncJobRunning {
+		t.Errorf("Expected status %s, got %s", models.SyncJobRunning, job.Status)
+	}
+
+	// Solo un job a la vez
+	if _, err := s.Start("fake", SyncModeFull); !errors.Is(err, ErrSyncInProgress) {
+		t.Errorf("Expected ErrSyncInProgress for a second job, got %v", err)
+	}
+
+	close(provider.release)
+	final := waitSyncJob(t, s, job.ID)
+
+	if final.Status != models.SyncJobSucceeded {
+		t.Errorf("Expected status %s, got %s (%s)", models.SyncJobSucceeded, final.Status, final.Error)
+	}
+	if final.PagesFetched != 1 || final.Inserted != 1 {
+		t.Errorf("Expected 1 page and 1 insert, got %d pages and %d inserts", final.PagesFetched, final.Inserted)
+	}
+	if final.FinishedAt == nil {
+		t.Error("Expected FinishedAt to be set")
+	}
+
+	// Terminado el primero, se puede iniciar otro
+	provider.started = make(chan struct{}, 1)
+	next, err := s.Start("fake", SyncModeFull)
+	if err != nil {
+		t.Fatalf("Expected a new job after the first finished, got %v", err)
+	}
+	waitSyncJob(t, s, next.ID)
+}
+
+func TestSyncJobService_Cancel(t *testing.T) {
+	provider := newBlockingProvider()
+	s := newTestSyncJobService(t, provider)
+
+	job := startBlockedJob(t, s, provider)
+
+	if _, err := s.Cancel(job.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	final := waitSyncJob(t, s, job.ID)
+
+	if final.Status != models.SyncJobCancelled {
+		t.Errorf("Expected status %s, got %s", models.SyncJobCancelled, final.Status)
+	}
+	if final.Error != "" {
+		t.Errorf("Expected no error on a cancelled job, got %q", final.Error)
+	}
+
+	if _, err := s.Cancel(job.ID); !errors.Is(err, ErrSyncJobNotRunning) {
+		t.Errorf("Expected ErrSyncJobNotRunning for a finished job, got %v", err)
+	}
+	if _, err := s.Cancel(uuid.New()); !errors.Is(err, ErrSyncJobNotFound) {
+		t.Errorf("Expected ErrSyncJobNotFound for an unknown job, got %v", err)
+	}
+}
+
+func TestSyncJobService_CancelAfterLastPageKeepsSuccess(t *testing.T) {
+	provider := newBlockingProvider()
+	s := newTestSyncJobService(t, provider)
+
+	job := startBlockedJob(t, s, provider)
+	// La cancelación llega cuando el proveedor ya entregó la última página
+	provider.onRelease = func() {
+		if _, err := s.Cancel(job.ID); err != nil {
+			t.Errorf("Cancel: %v", err)
+		}
+	}
+	close(provider.release)
+
+	final := waitSyncJob(t, s, job.ID)
+	if final.Status != models.SyncJobSucceeded {
+		t.Errorf("Expected status %s, got %s", models.SyncJobSucceeded, final.Status)
+	}
+}
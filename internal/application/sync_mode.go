@@ -0,0 +1,25 @@
+package application
+
+import "fmt"
+
+// SyncMode indica cómo se recorre el proveedor externo
+type SyncMode string
+
+const (
+	// SyncModeFull recorre todas las páginas (retomando un recorrido interrumpido)
+	SyncModeFull SyncMode = "full"
+	// SyncModeIncremental se detiene al llegar a eventos ya ingeridos
+	SyncModeIncremental SyncMode = "incremental"
+)
+
+// ParseSyncMode valida el modo recibido; vacío equivale a full
+func ParseSyncMode(s string) (SyncMode, error) {
+	switch SyncMode(s) {
+	case "", SyncModeFull:
+		return SyncModeFull, nil
+	case SyncModeIncremental:
+		return SyncModeIncremental, nil
+	default:
+		return "", fmt.Errorf("modo de sincronización inválido %q (valores permitidos: %s, %s)", s, SyncModeFull, SyncModeIncremental)
+	}
+}
@@ -0,0 +1,37 @@
+package application
+
+import (
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+)
+
+// SyncResult resume cuántos registros se insertaron, actualizaron o quedaron igual
+type SyncResult struct {
+	Provider  string   `json:"provider,omitempty"`
+	Mode      SyncMode `json:"mode,omitempty"`
+	Pages     int      `json:"pages"`
+	Inserted  int      `json:"inserted"`
+	Updated   int      `json:"updated"`
+	Unchanged int      `json:"unchanged"`
+	Failed    int      `json:"failed"`
+	// Quarantined son los registros que no pasaron la validación
+	Quarantined int `json:"quarantined"`
+	// ReachedKnown indica que un sync incremental se detuvo en eventos ya ingeridos
+	ReachedKnown bool              `json:"reached_known"`
+	Checkpoint   *models.SyncState `json:"checkpoint,omitempty"`
+	// Diff solo se llena en modo dry-run, donde no se escribe nada
+	DryRun bool      `json:"dry_run,omitempty"`
+	Diff   *SyncDiff `json:"diff,omitempty"`
+}
+
+// Record suma el resultado de un upsert al resumen
+func (r *SyncResult) Record(outcome repository.UpsertOutcome) {
+	switch outcome {
+	case repository.UpsertInserted:
+		r.Inserted++
+	case repository.UpsertUpdated:
+		r.Updated++
+	case repository.UpsertUnchanged:
+		r.Unchanged++
+	}
+}
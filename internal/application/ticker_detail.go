@@ -0,0 +1,118 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/stock"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+var (
+	// ErrInvalidTicker se devuelve cuando el símbolo no tiene formato de ticker
+	ErrInvalidTicker = errors.New("ticker inválido")
+	// ErrTickerNotFound se devuelve cuando no hay eventos para el ticker
+	ErrTickerNotFound = errors.New("no hay eventos para el ticker")
+)
+
+// TimelineEntry es un cambio de rating o precio objetivo de un brokerage
+type TimelineEntry struct {
+	Time       time.Time `json:"time"`
+	Brokerage  string    `json:"brokerage"`
+	Action     string    `json:"action"`
+	RatingFrom string    `json:"rating_from"`
+	RatingTo   string    `json:"rating_to"`
+	// CanonicalRatingTo es RatingTo en la escala canónica (vacío si no está en el vocabulario)
+	CanonicalRatingTo string `json:"canonical_rating_to"`
+	TargetFrom        string `json:"target_from"`
+	TargetTo          string `json:"target_to"`
+	// TargetFromValue y TargetToValue son los precios objetivo exactos en
+	// TargetCurrency (nil si no se pudieron interpretar)
+	TargetFromValue *models.Decimal `json:"target_from_value"`
+	TargetToValue   *models.Decimal `json:"target_to_value"`
+	TargetCurrency  string          `json:"target_currency"`
+	TargetChangePct *float64        `json:"target_change_pct"`
+	Provider        string          `json:"provider"`
+}
+
+// TickerDetail reúne todo lo que se sabe de un símbolo
+type TickerDetail struct {
+	Ticker string `json:"ticker"`
+	// Company es el nombre del evento más reciente
+	Company    string    `json:"company"`
+	Events     int       `json:"events"`
+	Brokerages []string  `json:"brokerages"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	// Timeline va del evento más viejo al más nuevo
+	Timeline  []TimelineEntry `json:"timeline"`
+	Consensus stock.Consensus `json:"consensus"`
+	// Score es el del evento más reciente con el perfil default, con su
+	// detalle por criterio
+	Score stock.ScoreBreakdown `json:"score"`
+}
+
+// GetTickerDetail arma el historial, el consenso y el último score de un ticker
+func (s *StockService) GetTickerDetail(ticker string) (*TickerDetail, error) {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	if !tickerPattern.MatchString(ticker) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTicker, ticker)
+	}
+
+	profile, err := s.profiles.Get("")
+	if err != nil {
+		return nil, err
+	}
+
+	// History devuelve del más nuevo al más viejo
+	history, err := s.stocks.History(ticker)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrTickerNotFound, ticker)
+	}
+	latest := history[0]
+
+	detail := &TickerDetail{
+		Ticker:    ticker,
+		Company:   latest.Company,
+		Events:    len(history),
+		FirstSeen: history[len(history)-1].Time,
+		LastSeen:  latest.Time,
+		Timeline:  make([]TimelineEntry, 0, len(history)),
+		Consensus: stock.CalculateConsensus(history),
+		Score:     stock.ExplainScore(latest, history, s.clock(), profile, s.brokerageWeights(history)),
+	}
+
+	seen := make(map[string]bool)
+	for i := len(history) - 1; i >= 0; i-- {
+		e := history[i]
+		if !seen[e.Brokerage] {
+			seen[e.Brokerage] = true
+			detail.Brokerages = append(detail.Brokerages, e.Brokerage)
+		}
+		detail.Timeline = append(detail.Timeline, newTimelineEntry(e))
+	}
+	return detail, nil
+}
+
+func newTimelineEntry(e models.Stock) TimelineEntry {
+	return TimelineEntry{
+		Time:              e.Time,
+		Brokerage:         e.Brokerage,
+		Action:            e.Action,
+		RatingFrom:        e.RatingFrom,
+		RatingTo:          e.RatingTo,
+		CanonicalRatingTo: e.CanonicalRatingTo,
+		TargetFrom:        e.TargetFrom,
+		TargetTo:          e.TargetTo,
+		TargetFromValue:   e.TargetFromValue,
+		TargetToValue:     e.TargetToValue,
+		TargetCurrency:    e.TargetCurrency,
+		TargetChangePct:   e.TargetChangePct,
+		Provider:          e.Provider,
+	}
+}
@@ -0,0 +1,109 @@
+package application
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+// Códigos de validación de registros de proveedores
+const (
+	IssueInvalidTicker  = "invalid_ticker"
+	IssueMissingCompany = "missing_company"
+	IssueUnknownRating  = "unknown_rating"
+	IssueInvalidPrice   = "invalid_price"
+	IssueInvalidTime    = "invalid_time"
+	IssueUnreadable     = "unreadable_record"
+)
+
+// ValidationIssue describe por qué un registro no se puede guardar
+type ValidationIssue struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Code, i.Message)
+}
+
+// tickerPattern acepta símbolos como AAPL, BRK.A o BF-B
+var tickerPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,5}([.\-][A-Z0-9]{1,3})?$`)
+
+// minEventTime es la fecha más antigua aceptada para un evento de rating
+var minEventTime = time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// maxEventSkew es cuánto puede estar un evento en el futuro (diferencias de zona horaria)
+const maxEventSkew = 24 * time.Hour
+
+// ValidateStock revisa formato de ticker, que los ratings estén en el
+// vocabulario ratings, precios objetivo y que la fecha sea razonable.
+// Devuelve nil si el registro es válido.
+func ValidateStock(stock models.Stock, ratings *RatingTaxonomy, now time.Time) []ValidationIssue {
+	// Un registro que el proveedor no pudo leer no tiene campos que revisar
+	if stock.ParseError != "" {
+		return []ValidationIssue{{"record", IssueUnreadable, stock.ParseError}}
+	}
+
+	var issues []ValidationIssue
+
+	if !tickerPattern.MatchString(stock.Ticker) {
+		issues = append(issues, ValidationIssue{"ticker", IssueInvalidTicker, fmt.Sprintf("ticker %q con formato inválido", stock.Ticker)})
+	}
+	if strings.TrimSpace(stock.Company) == "" {
+		issues = append(issues, ValidationIssue{"company", IssueMissingCompany, "company vacío"})
+	}
+
+	for _, r := range []struct{ field, value string }{
+		{"rating_from", stock.RatingFrom},
+		{"rating_to", stock.RatingTo},
+	} {
+		if r.value != "" && ratings.Resolve(r.value) == "" {
+			issues = append(issues, ValidationIssue{r.field, IssueUnknownRating, fmt.Sprintf("rating %q desconocido", r.value)})
+		}
+	}
+
+	currencies := make(map[string]bool)
+	for _, p := range []struct{ field, value string }{
+		{"target_from", stock.TargetFrom},
+		{"target_to", stock.TargetTo},
+	} {
+		if p.value == "" {
+			continue
+		}
+		price, err := models.ParseTargetPrice(p.value)
+		if err != nil {
+			issues = append(issues, ValidationIssue{p.field, IssueInvalidPrice, err.Error()})
+			continue
+		}
+		currencies[price.Currency] = true
+	}
+	if len(currencies) > 1 {
+		issues = append(issues, ValidationIssue{"target_to", IssueInvalidPrice, fmt.Sprintf("precios objetivo en monedas distintas (%q y %q)", stock.TargetFrom, stock.TargetTo)})
+	}
+
+	switch {
+	case stock.Time.IsZero() && stock.RawTime != "":
+		issues = append(issues, ValidationIssue{"time", IssueInvalidTime, fmt.Sprintf("time %q ilegible", stock.RawTime)})
+	case stock.Time.IsZero():
+		issues = append(issues, ValidationIssue{"time", IssueInvalidTime, "time vacío"})
+	case stock.Time.Before(minEventTime):
+		issues = append(issues, ValidationIssue{"time", IssueInvalidTime, fmt.Sprintf("time %s anterior a %s", stock.Time.Format(time.RFC3339), minEventTime.Format("2006-01-02"))})
+	case stock.Time.After(now.Add(maxEventSkew)):
+		issues = append(issues, ValidationIssue{"time", IssueInvalidTime, fmt.Sprintf("time %s está en el futuro", stock.Time.Format(time.RFC3339))})
+	}
+
+	return issues
+}
+
+// joinIssues arma el texto de motivos que se guarda en cuarentena
+func joinIssues(issues []ValidationIssue) string {
+	parts := make([]string, len(issues))
+	for i, issue := range issues {
+		parts[i] = issue.String()
+	}
+	return strings.Join(parts, "; ")
+}
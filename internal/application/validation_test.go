@@ -0,0 +1,106 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/importer"
+)
+
+func validStock() models.Stock {
+	return models.Stock{
+		Ticker:     "AAPL",
+		Company:    "Apple Inc.",
+		Brokerage:  "Goldman Sachs",
+		Action:     "upgraded by",
+		RatingFrom: "Neutral",
+		RatingTo:   "Buy",
+		TargetFrom: "$150.00",
+		TargetTo:   "$1,250.50",
+		Time:       time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestValidateStock(t *testing.T) {
+	now := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		modify   func(s *models.Stock)
+		expected []string
+	}{
+		{"Valid", func(s *models.Stock) {}, nil},
+		{"Class share ticker", func(s *models.Stock) { s.Ticker = "BRK.B" }, nil},
+		{"Empty ratings and targets", func(s *models.Stock) { s.RatingFrom, s.TargetFrom = "", "" }, nil},
+		{"Lowercase ticker", func(s *models.Stock) { s.Ticker = "aapl" }, []string{IssueInvalidTicker}},
+		{"Ticker too long", func(s *models.Stock) { s.Ticker = "ABCDEFGH" }, []string{IssueInvalidTicker}},
+		{"Missing company", func(s *models.Stock) { s.Company = " " }, []string{IssueMissingCompany}},
+		{"Unknown rating", func(s *models.Stock) { s.RatingTo = "Moon" }, []string{IssueUnknownRating}},
+		{"Rating is case insensitive", func(s *models.Stock) { s.RatingTo = "MARKET PERFORM" }, nil},
+		{"Unparseable price", func(s *models.Stock) { s.TargetTo = "N/A" }, []string{IssueInvalidPrice}},
+		{"Negative price", func(s *models.Stock) { s.TargetFrom = "$-5.00" }, []string{IssueInvalidPrice}},
+		{"Euro price", func(s *models.Stock) { s.TargetFrom, s.TargetTo = "€1.200,00", "€1.250,50" }, nil},
+		{"Mixed currencies", func(s *models.Stock) { s.TargetTo = "€1.250,50" }, []string{IssueInvalidPrice}},
+		{"Zero time", func(s *models.Stock) { s.Time = time.Time{} }, []string{IssueInvalidTime}},
+		{"Ancient time", func(s *models.Stock) { s.Time = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC) }, []string{IssueInvalidTime}},
+		{"Future time", func(s *models.Stock) { s.Time = now.Add(72 * time.Hour) }, []string{IssueInvalidTime}},
+		{"Several issues", func(s *models.Stock) { s.Ticker = ""; s.TargetTo = "abc" }, []string{IssueInvalidTicker, IssueInvalidPrice}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stock := validStock()
+			tc.modify(&stock)
+
+			issues := ValidateStock(stock, DefaultRatingTaxonomy(), now)
+
+			if len(issues) != len(tc.expected) {
+				t.Fatalf("Expected %d issues, got %d: %v", len(tc.expected), len(issues), issues)
+			}
+			for i, code := range tc.expected {
+				if issues[i].Code != code {
+					t.Errorf("Expected issue %d to be %s, got %s", i, code, issues[i].Code)
+				}
+			}
+		})
+	}
+}
+
+func TestValidationFieldErrors_SkipsParsedFields(t *testing.T) {
+	parseErrors := []importer.FieldError{{Field: "time", Message: "formato de fecha no reconocido"}}
+	issues := []ValidationIssue{
+		{Field: "time", Code: IssueInvalidTime, Message: "time vacío"},
+		{Field: "ticker", Code: IssueInvalidTicker, Message: "ticker inválido"},
+	}
+
+	errs := validationFieldErrors(parseErrors, issues)
+
+	if len(errs) != 1 || errs[0].Field != "ticker" {
+		t.Errorf("Expected only the ticker error, got %v", errs)
+	}
+}
+
+func TestQuarantineFixAndToStock(t *testing.T) {
+	stock := validStock()
+	stock.TargetTo = "N/A"
+	stock.Provider = "primary"
+	q := models.NewQuarantinedStock(stock, "invalid_price")
+
+	fixed := " $200.00 "
+	applyQuarantineFix(&q, QuarantineFix{TargetTo: &fixed})
+
+	readmitted := q.ToStock()
+	if readmitted.TargetTo != "$200.00" {
+		t.Errorf("Expected fixed target $200.00, got %q", readmitted.TargetTo)
+	}
+	if readmitted.Provider != "primary" || readmitted.Ticker != "AAPL" {
+		t.Errorf("Expected original fields to be kept, got %+v", readmitted)
+	}
+	if q.Status != models.QuarantinePending {
+		t.Errorf("Expected status pending, got %s", q.Status)
+	}
+	if issues := ValidateStock(readmitted, DefaultRatingTaxonomy(), stock.Time); len(issues) != 0 {
+		t.Errorf("Expected fixed record to be valid, got %v", issues)
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -15,6 +17,44 @@ type Config struct {
 	ExternalAPIToken string
 	ExternalAPIURL   string
 	FrontEndURL      string
+
+	// DBAutoMigrate aplica las migraciones pendientes al arrancar; en false el
+	// servidor no arranca si falta alguna
+	DBAutoMigrate bool
+
+	// Resiliencia del cliente de la API externa
+	ExternalAPITimeout     time.Duration // timeout por request
+	ExternalAPIMaxRetries  int           // reintentos ante 429/5xx o errores de red
+	ExternalAPIBackoffBase time.Duration // espera inicial del backoff exponencial
+	ExternalAPIBackoffMax  time.Duration // espera máxima entre reintentos, también para Retry-After (0 = sin límite)
+	ExternalAPIRateLimit   float64       // requests por segundo (0 = sin límite)
+	ExternalAPIRateBurst   int           // ráfaga máxima del token bucket
+
+	// Proveedores adicionales (vacío = deshabilitado)
+	FileProviderName    string
+	FileProviderDir     string
+	SecondaryAPIName    string
+	SecondaryAPIURL     string
+	SecondaryAPIToken   string
+	SecondaryAPIMapping string // ruta al JSON con el mapeo de campos
+
+	// Sincronizaciones programadas (expresiones cron; vacío = deshabilitado)
+	SyncScheduleFull        string
+	SyncScheduleIncremental string
+	SyncScheduleJitter      time.Duration
+
+	// ArchiveRawPayloads guarda cada respuesta cruda de los proveedores HTTP
+	ArchiveRawPayloads bool
+
+	// Perfiles de scoring del recomendador (vacío = solo el perfil de fábrica)
+	ScoringProfilesFile   string
+	ScoringProfilesReload time.Duration // cada cuánto se revisa el archivo (0 = sin recarga)
+
+	// Leaderboard de brokerages: cotizaciones históricas en el formato del
+	// backtest (vacío = deshabilitado), su moneda y cada cuándo se recalcula
+	LeaderboardPricesDir      string
+	LeaderboardPricesCurrency string
+	LeaderboardSchedule       string
 }
 
 func LoadConfig() *Config {
@@ -28,6 +68,34 @@ func LoadConfig() *Config {
 		ExternalAPIToken: getEnv("EXTERNAL_API_TOKEN", ""),
 		ExternalAPIURL:   getEnv("EXTERNAL_API_URL", ""),
 		FrontEndURL:      getEnv("FRONT_END_URL", ""),
+		DBAutoMigrate:    getEnvBool("DB_AUTO_MIGRATE", true),
+
+		ExternalAPITimeout:     getEnvDuration("EXTERNAL_API_TIMEOUT", 15*time.Second),
+		ExternalAPIMaxRetries:  getEnvInt("EXTERNAL_API_MAX_RETRIES", 5),
+		ExternalAPIBackoffBase: getEnvDuration("EXTERNAL_API_BACKOFF_BASE", 500*time.Millisecond),
+		ExternalAPIBackoffMax:  getEnvDuration("EXTERNAL_API_BACKOFF_MAX", 30*time.Second),
+		ExternalAPIRateLimit:   getEnvFloat("EXTERNAL_API_RATE_LIMIT", 5),
+		ExternalAPIRateBurst:   getEnvInt("EXTERNAL_API_RATE_BURST", 1),
+
+		FileProviderName:    getEnv("FILE_PROVIDER_NAME", "files"),
+		FileProviderDir:     getEnv("FILE_PROVIDER_DIR", ""),
+		SecondaryAPIName:    getEnv("SECONDARY_API_NAME", "secondary"),
+		SecondaryAPIURL:     getEnv("SECONDARY_API_URL", ""),
+		SecondaryAPIToken:   getEnv("SECONDARY_API_TOKEN", ""),
+		SecondaryAPIMapping: getEnv("SECONDARY_API_MAPPING", ""),
+
+		SyncScheduleFull:        getEnv("SYNC_SCHEDULE_FULL", ""),
+		SyncScheduleIncremental: getEnv("SYNC_SCHEDULE_INCREMENTAL", ""),
+		SyncScheduleJitter:      getEnvDuration("SYNC_SCHEDULE_JITTER", 0),
+
+		ArchiveRawPayloads: getEnvBool("ARCHIVE_RAW_PAYLOADS", true),
+
+		ScoringProfilesFile:   getEnv("SCORING_PROFILES_FILE", ""),
+		ScoringProfilesReload: getEnvDuration("SCORING_PROFILES_RELOAD", 30*time.Second),
+
+		LeaderboardPricesDir:      getEnv("LEADERBOARD_PRICES_DIR", ""),
+		LeaderboardPricesCurrency: getEnv("LEADERBOARD_PRICES_CURRENCY", "USD"),
+		LeaderboardSchedule:       getEnv("LEADERBOARD_SCHEDULE", "0 4 * * *"),
 	}
 
 	if cfg.DBUser == "" || cfg.DBPassword == "" {
@@ -43,3 +111,57 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("⚠️ %s=%q no es un entero válido, usando %d", key, value, fallback)
+		return fallback
+	}
+	return n
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("⚠️ %s=%q no es un número válido, usando %v", key, value, fallback)
+		return fallback
+	}
+	return f
+}
+
+// getEnvBool acepta los formatos de strconv.ParseBool ("true", "0", "f")
+func getEnvBool(key string, fallback bool) bool {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("⚠️ %s=%q no es un booleano válido, usando %t", key, value, fallback)
+		return fallback
+	}
+	return b
+}
+
+// getEnvDuration acepta formatos de time.ParseDuration ("500ms", "15s", "1m")
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("⚠️ %s=%q no es una duración válida, usando %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
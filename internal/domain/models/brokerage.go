@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Brokerage es la firma canónica detrás de los nombres crudos que mandan los
+// proveedores ("JP Morgan", "J.P. Morgan", "JPMorgan Chase & Co."). El peso
+// de credibilidad lo usa el recomendador.
+type Brokerage struct {
+	ID   uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name string    `gorm:"column:name;uniqueIndex" json:"name"`
+	// Tier agrupa las firmas por credibilidad: 1 premium, 2 sólidas, 3 otras
+	Tier int `gorm:"column:tier" json:"tier"`
+	// Weight multiplica el puntaje de credibilidad; entre 0 y 1
+	Weight    float64          `gorm:"column:weight" json:"weight"`
+	Aliases   []BrokerageAlias `gorm:"foreignKey:BrokerageID" json:"aliases"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// BrokerageAlias es otro nombre con el que llega un brokerage. Normalized es
+// la llave de búsqueda (ver NormalizeBrokerageName) y no se puede repetir.
+type BrokerageAlias struct {
+	Normalized  string    `gorm:"column:normalized;primaryKey" json:"normalized"`
+	Alias       string    `gorm:"column:alias" json:"alias"`
+	BrokerageID uuid.UUID `gorm:"column:brokerage_id;type:uuid;index" json:"-"`
+}
+
+// brokerageSuffixes son palabras que se ignoran al final del nombre
+var brokerageSuffixes = map[string]bool{
+	"and": true, "ag": true, "co": true, "companies": true, "company": true,
+	"corp": true, "corporation": true, "group": true, "holdings": true,
+	"inc": true, "incorporated": true, "limited": true, "llc": true, "lp": true,
+	"ltd": true, "plc": true, "sa": true, "securities": true,
+}
+
+// NormalizeBrokerageName reduce un nombre de brokerage a su llave de búsqueda:
+// minúsculas, sin puntuación ni espacios y sin sufijos societarios al final,
+// así "J.P. Morgan" y "JPMorgan" dan "jpmorgan" y "Goldman Sachs Group, Inc."
+// da "goldmansachs".
+func NormalizeBrokerageName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	for len(words) > 1 && brokerageSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, "")
+}
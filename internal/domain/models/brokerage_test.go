@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestNormalizeBrokerageName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"JPMorgan", "jpmorgan"},
+		{"J.P. Morgan", "jpmorgan"},
+		{"JP Morgan Securities LLC", "jpmorgan"},
+		{"Goldman Sachs Group, Inc.", "goldmansachs"},
+		{"The Goldman Sachs Group", "goldmansachs"},
+		{"Cowen and Company", "cowen"},
+		{"Cowen & Co.", "cowen"},
+		{"Bank of America", "bankofamerica"},
+		{"Capital One Securities", "capitalone"},
+		{"  ", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NormalizeBrokerageName(tc.name); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
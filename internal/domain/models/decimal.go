@@ -0,0 +1,117 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Decimal es un importe exacto con cuatro decimales, el DECIMAL(18,4) de la
+// base, guardado como diezmilésimos para no arrastrar errores de float
+type Decimal int64
+
+const (
+	decimalPlaces = 4
+	decimalScale  = 10000
+)
+
+// ParseDecimal interpreta un número sin separadores de miles ("1250.5",
+// "150"); rechaza más de cuatro decimales en vez de redondear
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || !isDigits(whole) || (frac != "" && !isDigits(frac)) {
+		return 0, fmt.Errorf("%q no es un decimal válido", s)
+	}
+	// Ceros a la derecha de la cuarta posición no cambian el valor
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > decimalPlaces {
+		return 0, fmt.Errorf("%q tiene más de %d decimales", s, decimalPlaces)
+	}
+
+	units, err := strconv.ParseInt(whole+frac+strings.Repeat("0", decimalPlaces-len(frac)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q fuera de rango", s)
+	}
+	if negative {
+		units = -units
+	}
+	return Decimal(units), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String devuelve el importe con al menos dos decimales ("1250.50", "0.1234")
+func (d Decimal) String() string {
+	sign := ""
+	units := int64(d)
+	if units < 0 {
+		sign, units = "-", -units
+	}
+	frac := fmt.Sprintf("%04d", units%decimalScale)
+	frac = strings.TrimRight(frac, "0")
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	return fmt.Sprintf("%s%d.%s", sign, units/decimalScale, frac)
+}
+
+// Float64 es para cálculos de puntaje, no para guardar
+func (d Decimal) Float64() float64 {
+	return float64(d) / decimalScale
+}
+
+// Value guarda el importe como texto para que la base lo convierta sin pasar por float
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d *Decimal) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*d = 0
+		return nil
+	case string:
+		return d.parse(v)
+	case []byte:
+		return d.parse(string(v))
+	case int64:
+		*d = Decimal(v * decimalScale)
+		return nil
+	case float64:
+		*d = Decimal(math.Round(v * decimalScale))
+		return nil
+	}
+	return fmt.Errorf("no se puede leer %T como Decimal", src)
+}
+
+func (d *Decimal) parse(s string) error {
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON escribe el importe como número JSON exacto
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON acepta el número o el número entre comillas
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	return d.parse(strings.Trim(string(data), `"`))
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Decimal
+		valid    bool
+	}{
+		{"150", 1500000, true},
+		{"1250.5", 12505000, true},
+		{"0.1234", 1234, true},
+		{"12.50000", 125000, true},
+		{"-3.5", -35000, true},
+		{"1.23456", 0, false},
+		{"1,250", 0, false},
+		{".5", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDecimal(tt.input)
+			if (err == nil) != tt.valid || got != tt.expected {
+				t.Errorf("ParseDecimal(%q) = %d, %v; expected %d (valid=%v)", tt.input, got, err, tt.expected, tt.valid)
+			}
+		})
+	}
+}
+
+func TestDecimal_String(t *testing.T) {
+	for units, expected := range map[Decimal]string{
+		12505000: "1250.50",
+		1500000:  "150.00",
+		1234:     "0.1234",
+		-35000:   "-3.50",
+	} {
+		if got := units.String(); got != expected {
+			t.Errorf("Decimal(%d).String() = %q; expected %q", int64(units), got, expected)
+		}
+	}
+}
+
+func TestDecimal_Scan(t *testing.T) {
+	var d Decimal
+	for _, src := range []any{"1250.5000", []byte("1250.5"), 1250.5} {
+		if err := d.Scan(src); err != nil || d != 12505000 {
+			t.Errorf("Scan(%v) = %d, %v", src, d, err)
+		}
+	}
+	if err := d.Scan(int64(7)); err != nil || d != 70000 {
+		t.Errorf("Scan(int64) = %d, %v", d, err)
+	}
+	if err := d.Scan(true); err == nil {
+		t.Error("Expected error scanning bool")
+	}
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	value := Decimal(12505000)
+	data, err := json.Marshal(struct {
+		Price *Decimal `json:"price"`
+		None  *Decimal `json:"none"`
+	}{Price: &value})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"price":1250.50,"none":null}` {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+
+	var decoded struct{ Price Decimal }
+	if err := json.Unmarshal([]byte(`{"Price":"99.9"}`), &decoded); err != nil || decoded.Price != 999000 {
+		t.Errorf("Unexpected decode: %d, %v", decoded.Price, err)
+	}
+}
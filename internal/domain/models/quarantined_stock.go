@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuarantineStatus es el estado de un registro en cuarentena
+type QuarantineStatus string
+
+const (
+	QuarantinePending    QuarantineStatus = "pending"
+	QuarantineReadmitted QuarantineStatus = "readmitted"
+	QuarantineDiscarded  QuarantineStatus = "discarded"
+)
+
+// QuarantinedStock guarda un registro de proveedor que no pasó la validación,
+// con los motivos, para que un admin lo corrija y re-admita o lo descarte.
+// RawTime conserva la fecha tal como llegó cuando no se pudo interpretar.
+type QuarantinedStock struct {
+	ID uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	// NaturalKey es la llave natural del registro original; evita volver a
+	// poner en cuarentena el mismo registro en cada sincronización
+	NaturalKey string           `gorm:"column:natural_key;uniqueIndex" json:"natural_key"`
+	Provider   string           `gorm:"column:provider" json:"provider"`
+	Ticker     string           `gorm:"column:ticker" json:"ticker"`
+	Company    string           `gorm:"column:company" json:"company"`
+	Brokerage  string           `gorm:"column:brokerage" json:"brokerage"`
+	Action     string           `gorm:"column:action" json:"action"`
+	RatingFrom string           `gorm:"column:rating_from" json:"rating_from"`
+	RatingTo   string           `gorm:"column:rating_to" json:"rating_to"`
+	TargetFrom string           `gorm:"column:target_from" json:"target_from"`
+	TargetTo   string           `gorm:"column:target_to" json:"target_to"`
+	Time       time.Time        `gorm:"column:time" json:"time"`
+	RawTime    string           `gorm:"column:raw_time" json:"raw_time,omitempty"`
+	Reasons    string           `gorm:"column:reasons" json:"reasons"`
+	Status     QuarantineStatus `gorm:"column:status;index" json:"status"`
+	ResolvedAt *time.Time       `gorm:"column:resolved_at" json:"resolved_at"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// NewQuarantinedStock pone en cuarentena un evento con los motivos dados
+func NewQuarantinedStock(stock Stock, reasons string) QuarantinedStock {
+	return QuarantinedStock{
+		NaturalKey: quarantineKey(stock),
+		Provider:   stock.Provider,
+		Ticker:     stock.Ticker,
+		Company:    stock.Company,
+		Brokerage:  stock.Brokerage,
+		Action:     stock.Action,
+		RatingFrom: stock.RatingFrom,
+		RatingTo:   stock.RatingTo,
+		TargetFrom: stock.TargetFrom,
+		TargetTo:   stock.TargetTo,
+		Time:       stock.Time,
+		RawTime:    stock.RawTime,
+		Reasons:    reasons,
+		Status:     QuarantinePending,
+	}
+}
+
+// quarantineKey es la llave natural del evento. Sin fecha, la llave lleva el
+// texto original de la fecha, y los registros ilegibles el motivo (archivo y
+// línea), para que dos registros distintos no choquen entre sí
+func quarantineKey(stock Stock) string {
+	key := stock.NaturalKey()
+	if stock.Time.IsZero() && stock.RawTime != "" {
+		key += "|" + stock.RawTime
+	}
+	if stock.ParseError != "" {
+		key += "|" + stock.ParseError
+	}
+	return key
+}
+
+// ToStock reconstruye el evento (posiblemente corregido) para re-admitirlo
+func (q QuarantinedStock) ToStock() Stock {
+	return Stock{
+		Ticker:     q.Ticker,
+		Company:    q.Company,
+		Brokerage:  q.Brokerage,
+		Action:     q.Action,
+		RatingFrom: q.RatingFrom,
+		RatingTo:   q.RatingTo,
+		TargetFrom: q.TargetFrom,
+		TargetTo:   q.TargetTo,
+		Time:       q.Time,
+		RawTime:    q.RawTime,
+		Provider:   q.Provider,
+	}
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Escala canónica de ratings. Cada texto que mandan los proveedores ("Sector
+// Outperform", "Equal Weight", "Peer Perform") se traduce a uno de estos
+// niveles, que es lo único que mira el recomendador.
+const (
+	RatingStrongBuy    = "strong_buy"
+	RatingBuy          = "buy"
+	RatingHold         = "hold"
+	RatingUnderperform = "underperform"
+	RatingSell         = "sell"
+)
+
+// CanonicalRatings lista los niveles de la escala, del mejor al peor
+var CanonicalRatings = []string{RatingStrongBuy, RatingBuy, RatingHold, RatingUnderperform, RatingSell}
+
+// IsCanonicalRating indica si rating es un nivel de la escala
+func IsCanonicalRating(rating string) bool {
+	for _, r := range CanonicalRatings {
+		if r == rating {
+			return true
+		}
+	}
+	return false
+}
+
+// RatingMapping traduce un texto de rating a su nivel canónico. Normalized
+// es la llave de búsqueda (ver NormalizeRatingLabel) y Label el texto tal
+// como lo cargó el admin o como llegó del proveedor.
+type RatingMapping struct {
+	Normalized string    `gorm:"column:normalized;primaryKey" json:"normalized"`
+	Label      string    `gorm:"column:label" json:"label"`
+	Rating     string    `gorm:"column:rating;index" json:"rating"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NormalizeRatingLabel reduce un texto de rating a su llave de búsqueda:
+// minúsculas y palabras separadas por un solo espacio, así "Equal-Weight",
+// "equal weight" y " EQUAL_WEIGHT" dan "equal weight".
+func NormalizeRatingLabel(label string) string {
+	words := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// DefaultRatingMappings es el vocabulario de fábrica por texto normalizado;
+// la migración 0006_rating_taxonomy siembra la misma tabla
+var DefaultRatingMappings = map[string]string{
+	"strong buy":          RatingStrongBuy,
+	"top pick":            RatingStrongBuy,
+	"conviction buy":      RatingStrongBuy,
+	"buy":                 RatingBuy,
+	"outperform":          RatingBuy,
+	"overweight":          RatingBuy,
+	"positive":            RatingBuy,
+	"market outperform":   RatingBuy,
+	"sector outperform":   RatingBuy,
+	"speculative buy":     RatingBuy,
+	"moderate buy":        RatingBuy,
+	"accumulate":          RatingBuy,
+	"hold":                RatingHold,
+	"neutral":             RatingHold,
+	"market perform":      RatingHold,
+	"sector perform":      RatingHold,
+	"peer perform":        RatingHold,
+	"equal weight":        RatingHold,
+	"sector weight":       RatingHold,
+	"in line":             RatingHold,
+	"inline":              RatingHold,
+	"underweight":         RatingUnderperform,
+	"underperform":        RatingUnderperform,
+	"market underperform": RatingUnderperform,
+	"sector underperform": RatingUnderperform,
+	"negative":            RatingUnderperform,
+	"reduce":              RatingUnderperform,
+	"moderate sell":       RatingUnderperform,
+	"sell":                RatingSell,
+	"strong sell":         RatingSell,
+}
+
+// DefaultCanonicalRating traduce label con el vocabulario de fábrica; vacío
+// si no lo conoce
+func DefaultCanonicalRating(label string) string {
+	return DefaultRatingMappings[NormalizeRatingLabel(label)]
+}
@@ -0,0 +1,47 @@
+package models
+
+import "testing"
+
+func TestNormalizeRatingLabel(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"Equal-Weight", "equal weight"},
+		{" EQUAL_WEIGHT ", "equal weight"},
+		{"Sector  Outperform", "sector outperform"},
+		{"In-Line", "in line"},
+		{"Buy", "buy"},
+		{"  ", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NormalizeRatingLabel(tc.name); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDefaultRatingMappings(t *testing.T) {
+	for label, rating := range DefaultRatingMappings {
+		if NormalizeRatingLabel(label) != label {
+			t.Errorf("Default label %q is not normalized", label)
+		}
+		if !IsCanonicalRating(rating) {
+			t.Errorf("Default label %q maps to unknown rating %q", label, rating)
+		}
+	}
+
+	// Outperform es un buy en todas partes, no un strong buy
+	if got := DefaultCanonicalRating("Outperform"); got != RatingBuy {
+		t.Errorf("Expected Outperform as buy, got %q", got)
+	}
+	if got := DefaultCanonicalRating("Peer-Perform"); got != RatingHold {
+		t.Errorf("Expected Peer Perform as hold, got %q", got)
+	}
+	if got := DefaultCanonicalRating("Conviction List"); got != "" {
+		t.Errorf("Expected unknown label to be unmapped, got %q", got)
+	}
+}
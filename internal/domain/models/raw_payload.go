@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RawPayload es una página tal cual la respondió un proveedor HTTP,
+// comprimida con gzip, para auditar cambios del proveedor y re-procesarla
+type RawPayload struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Provider   string    `gorm:"column:provider;index:idx_raw_payloads_provider_fetched" json:"provider"`
+	PageToken  string    `gorm:"column:page_token" json:"page_token"`
+	URL        string    `gorm:"column:url" json:"url"`
+	StatusCode int       `gorm:"column:status_code" json:"status_code"`
+	// Body va comprimido con gzip; Size es el tamaño sin comprimir
+	Body      []byte    `gorm:"column:body" json:"-"`
+	Size      int       `gorm:"column:size" json:"size"`
+	FetchedAt time.Time `gorm:"column:fetched_at;index:idx_raw_payloads_provider_fetched" json:"fetched_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,22 +1,207 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Stock representa un evento de rating publicado por un brokerage.
+// La llave natural (ticker + brokerage + time + action + rating_to + target_to)
+// identifica el evento y evita duplicados al re-sincronizar.
 type Stock struct {
 	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	Ticker     string    `gorm:"column:ticker"`
+	Ticker     string    `gorm:"column:ticker;uniqueIndex:idx_stocks_natural_key"`
 	Company    string    `gorm:"column:company"`
-	Brokerage  string    `gorm:"column:brokerage"`
-	Action     string    `gorm:"column:action"`
+	Brokerage  string    `gorm:"column:brokerage;uniqueIndex:idx_stocks_natural_key"`
+	Action     string    `gorm:"column:action;uniqueIndex:idx_stocks_natural_key"`
 	RatingFrom string    `gorm:"column:rating_from"`
-	RatingTo   string    `gorm:"column:rating_to"`
+	RatingTo   string    `gorm:"column:rating_to;uniqueIndex:idx_stocks_natural_key"`
 	TargetFrom string    `gorm:"column:target_from"`
-	TargetTo   string    `gorm:"column:target_to"`
-	Time       time.Time `gorm:"column:time"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	TargetTo   string    `gorm:"column:target_to;uniqueIndex:idx_stocks_natural_key"`
+	Time       time.Time `gorm:"column:time;uniqueIndex:idx_stocks_natural_key"`
+	// RawTime es la fecha ilegible que mandó el proveedor (Time queda en
+	// cero); no se guarda, solo sirve para el motivo de la cuarentena
+	RawTime string `gorm:"-" json:"-"`
+	// ParseError es el motivo por el que el proveedor no pudo leer el
+	// registro; tampoco se guarda
+	ParseError string `gorm:"-" json:"-"`
+	// Provider es el proveedor del que llegó el evento por primera vez
+	Provider string `gorm:"column:provider;index"`
+	// BrokerageID es el brokerage canónico al que se resolvió Brokerage
+	// (nil si ningún nombre ni alias coincide)
+	BrokerageID *uuid.UUID `gorm:"column:brokerage_id;type:uuid;index"`
+	// CanonicalRatingFrom y CanonicalRatingTo son RatingFrom y RatingTo en la
+	// escala canónica (RatingStrongBuy...RatingSell); vacío si el texto no
+	// está en la tabla rating_mappings
+	CanonicalRatingFrom string `gorm:"column:canonical_rating_from"`
+	CanonicalRatingTo   string `gorm:"column:canonical_rating_to;index"`
+	// TargetFromValue y TargetToValue son los precios objetivo exactos en
+	// TargetCurrency (nil si el texto no se pudo interpretar); se completan al
+	// guardar y se usan para filtrar, ordenar y puntuar
+	TargetFromValue *Decimal `gorm:"column:target_from_value;type:decimal(18,4)"`
+	TargetToValue   *Decimal `gorm:"column:target_to_value;type:decimal(18,4)"`
+	// TargetCurrency es el código ISO 4217 de los precios objetivo (vacío si
+	// no hay ninguno interpretado)
+	TargetCurrency string `gorm:"column:target_currency"`
+	// TargetUnparsed marca los eventos con un precio objetivo que no se pudo
+	// interpretar o con dos precios en monedas distintas
+	TargetUnparsed bool `gorm:"column:target_unparsed"`
+	// TargetChangePct es una columna calculada en la base: variación
+	// porcentual entre TargetFromValue y TargetToValue
+	TargetChangePct *float64 `gorm:"column:target_change_pct;->"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// ParseTargets completa TargetFromValue, TargetToValue y TargetCurrency a
+// partir del texto. Si los dos precios vienen en monedas distintas no se
+// guarda ninguno, porque la variación no tendría sentido.
+func (s *Stock) ParseTargets() {
+	s.TargetFromValue, s.TargetToValue, s.TargetCurrency = nil, nil, ""
+	from, fromErr := ParseTargetPrice(s.TargetFrom)
+	to, toErr := ParseTargetPrice(s.TargetTo)
+	s.TargetUnparsed = (strings.TrimSpace(s.TargetFrom) != "" && fromErr != nil) ||
+		(strings.TrimSpace(s.TargetTo) != "" && toErr != nil)
+
+	if fromErr == nil && toErr == nil && from.Currency != to.Currency {
+		s.TargetUnparsed = true
+		return
+	}
+	if fromErr == nil {
+		s.TargetFromValue, s.TargetCurrency = &from.Amount, from.Currency
+	}
+	if toErr == nil {
+		s.TargetToValue, s.TargetCurrency = &to.Amount, to.Currency
+	}
+}
+
+// TargetChange calcula la variación porcentual del precio objetivo, igual
+// que la columna calculada target_change_pct
+func (s Stock) TargetChange() *float64 {
+	if s.TargetFromValue == nil || s.TargetToValue == nil || *s.TargetFromValue <= 0 {
+		return nil
+	}
+	from, to := s.TargetFromValue.Float64(), s.TargetToValue.Float64()
+	pct := (to - from) / from * 100
+	return &pct
+}
+
+// NaturalKey devuelve la llave natural del evento en forma de string,
+// útil para deduplicar en memoria.
+func (s Stock) NaturalKey() string {
+	return strings.Join([]string{
+		s.Ticker,
+		s.Brokerage,
+		s.Time.UTC().Format(time.RFC3339Nano),
+		s.Action,
+		s.RatingTo,
+		s.TargetTo,
+	}, "|")
+}
+
+// SameAttributes indica si los campos que no forman parte de la llave
+// natural coinciden, es decir, si un upsert no cambiaría nada.
+// El proveedor solo cuenta como cambio si el registro aún no tiene uno, y el
+// brokerage y los ratings canónicos solo si el evento entrante trae uno distinto.
+// Los precios interpretados cuentan aunque el texto no cambie, para que una
+// re-sincronización corrija lo que la migración 0007 no supo interpretar.
+func (s Stock) SameAttributes(other Stock) bool {
+	return s.Company == other.Company &&
+		s.RatingFrom == other.RatingFrom &&
+		s.TargetFrom == other.TargetFrom &&
+		!s.targetsChanged(other) &&
+		(s.Provider != "" || other.Provider == "") &&
+		!s.brokerageChanged(other) &&
+		!canonicalChanged(s.CanonicalRatingFrom, other.CanonicalRatingFrom) &&
+		!canonicalChanged(s.CanonicalRatingTo, other.CanonicalRatingTo)
+}
+
+// ChangedFields lista las columnas mutables que un upsert con other modificaría
+func (s Stock) ChangedFields(other Stock) []string {
+	var fields []string
+	if s.Company != other.Company {
+		fields = append(fields, "company")
+	}
+	if s.RatingFrom != other.RatingFrom {
+		fields = append(fields, "rating_from")
+	}
+	if s.TargetFrom != other.TargetFrom {
+		fields = append(fields, "target_from")
+	}
+	if !sameDecimal(s.TargetFromValue, other.TargetFromValue) {
+		fields = append(fields, "target_from_value")
+	}
+	if !sameDecimal(s.TargetToValue, other.TargetToValue) {
+		fields = append(fields, "target_to_value")
+	}
+	if s.TargetCurrency != other.TargetCurrency {
+		fields = append(fields, "target_currency")
+	}
+	if s.TargetUnparsed != other.TargetUnparsed {
+		fields = append(fields, "target_unparsed")
+	}
+	if s.Provider == "" && other.Provider != "" {
+		fields = append(fields, "provider")
+	}
+	if s.brokerageChanged(other) {
+		fields = append(fields, "brokerage_id")
+	}
+	if canonicalChanged(s.CanonicalRatingFrom, other.CanonicalRatingFrom) {
+		fields = append(fields, "canonical_rating_from")
+	}
+	if canonicalChanged(s.CanonicalRatingTo, other.CanonicalRatingTo) {
+		fields = append(fields, "canonical_rating_to")
+	}
+	return fields
+}
+
+// targetsChanged compara los precios interpretados y la marca de ilegible
+func (s Stock) targetsChanged(other Stock) bool {
+	return !sameDecimal(s.TargetFromValue, other.TargetFromValue) ||
+		!sameDecimal(s.TargetToValue, other.TargetToValue) ||
+		s.TargetCurrency != other.TargetCurrency ||
+		s.TargetUnparsed != other.TargetUnparsed
+}
+
+func sameDecimal(a, b *Decimal) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (s Stock) brokerageChanged(other Stock) bool {
+	if other.BrokerageID == nil {
+		return false
+	}
+	return s.BrokerageID == nil || *s.BrokerageID != *other.BrokerageID
+}
+
+func canonicalChanged(current, incoming string) bool {
+	return incoming != "" && incoming != current
+}
+
+// CopyAttributes copia los campos mutables (fuera de la llave natural) desde other.
+func (s *Stock) CopyAttributes(other Stock) {
+	s.Company = other.Company
+	s.RatingFrom = other.RatingFrom
+	s.TargetFrom = other.TargetFrom
+	s.TargetFromValue = other.TargetFromValue
+	s.TargetToValue = other.TargetToValue
+	s.TargetCurrency = other.TargetCurrency
+	s.TargetUnparsed = other.TargetUnparsed
+	if s.Provider == "" {
+		s.Provider = other.Provider
+	}
+	if other.BrokerageID != nil {
+		s.BrokerageID = other.BrokerageID
+	}
+	if other.CanonicalRatingFrom != "" {
+		s.CanonicalRatingFrom = other.CanonicalRatingFrom
+	}
+	if other.CanonicalRatingTo != "" {
+		s.CanonicalRatingTo = other.CanonicalRatingTo
+	}
 }
@@ -304,3 +304,258 @@ func validateStockModel(stock Stock) bool {
 	}
 	return true
 }
+
+func TestStock_NaturalKey(t *testing.T) {
+	now := time.Now()
+	base := Stock{
+		Ticker:     "AAPL",
+		Company:    "Apple Inc.",
+		Brokerage:  "Goldman Sachs",
+		Action:     "upgraded by",
+		RatingFrom: "Hold",
+		RatingTo:   "Buy",
+		TargetFrom: "$150.00",
+		TargetTo:   "$180.00",
+		Time:       now,
+	}
+
+	// Mismo evento con otro ID y en otra zona horaria => misma llave
+	same := base
+	same.ID = uuid.New()
+	same.Time = now.In(time.FixedZone("COT", -5*3600))
+	same.Company = "Apple"
+	if base.NaturalKey() != same.NaturalKey() {
+		t.Errorf("Expected same natural key, got %q and %q", base.NaturalKey(), same.NaturalKey())
+	}
+
+	// Cambiar un campo de la llave produce otra llave
+	other := base
+	other.TargetTo = "$190.00"
+	if base.NaturalKey() == other.NaturalKey() {
+		t.Error("Different target_to should produce a different natural key")
+	}
+}
+
+func TestStock_SameAttributes(t *testing.T) {
+	a := Stock{Ticker: "AAPL", Company: "Apple Inc.", RatingFrom: "Hold", TargetFrom: "$150.00"}
+	b := a
+
+	if !a.SameAttributes(b) {
+		t.Error("Identical stocks should have same attributes")
+	}
+
+	b.RatingFrom = "Sell"
+	if a.SameAttributes(b) {
+		t.Error("Different rating_from should be detected as a change")
+	}
+
+	a.CopyAttributes(b)
+	if a.RatingFrom != "Sell" || !a.SameAttributes(b) {
+		t.Error("CopyAttributes should copy mutable fields")
+	}
+}
+
+func TestStock_ChangedFields(t *testing.T) {
+	a := Stock{Ticker: "AAPL", Company: "Apple Inc.", RatingFrom: "Hold", TargetFrom: "$150.00"}
+	b := a
+	b.Company = "Apple"
+	b.TargetFrom = "$160.00"
+	b.Provider = "primary"
+
+	fields := a.ChangedFields(b)
+
+	expected := []string{"company", "target_from", "provider"}
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, fields)
+		}
+	}
+	if len(a.ChangedFields(a)) != 0 {
+		t.Error("Identical stocks should have no changed fields")
+	}
+}
+
+func TestStock_ProviderBackfill(t *testing.T) {
+	legacy := Stock{Ticker: "AAPL", Company: "Apple Inc."}
+	incoming := Stock{Ticker: "AAPL", Company: "Apple Inc.", Provider: "primary"}
+
+	// Un registro sin proveedor se completa con el del evento entrante
+	if legacy.SameAttributes(incoming) {
+		t.Error("Missing provider should be considered a change")
+	}
+	legacy.CopyAttributes(incoming)
+	if legacy.Provider != "primary" {
+		t.Errorf("Expected provider to be backfilled, got %q", legacy.Provider)
+	}
+
+	// Pero el proveedor original no se sobrescribe
+	other := Stock{Ticker: "AAPL", Company: "Apple Inc.", Provider: "files"}
+	if !legacy.SameAttributes(other) {
+		t.Error("Different provider should not be considered a change")
+	}
+	legacy.CopyAttributes(other)
+	if legacy.Provider != "primary" {
+		t.Errorf("Expected original provider to be kept, got %q", legacy.Provider)
+	}
+}
+
+func TestStock_BrokerageResolution(t *testing.T) {
+	id := uuid.New()
+	stored := Stock{Ticker: "AAPL", Company: "Apple Inc.", Provider: "primary"}
+	incoming := stored
+	incoming.BrokerageID = &id
+
+	// Un evento resuelto completa el brokerage del registro guardado
+	if stored.SameAttributes(incoming) {
+		t.Error("New brokerage_id should be considered a change")
+	}
+	if fields := stored.ChangedFields(incoming); len(fields) != 1 || fields[0] != "brokerage_id" {
+		t.Errorf("Expected [brokerage_id], got %v", fields)
+	}
+	stored.CopyAttributes(incoming)
+	if stored.BrokerageID == nil || *stored.BrokerageID != id {
+		t.Errorf("Expected brokerage_id to be copied, got %v", stored.BrokerageID)
+	}
+
+	// Un evento sin resolver no borra el brokerage guardado
+	unresolved := Stock{Ticker: "AAPL", Company: "Apple Inc.", Provider: "primary"}
+	if !stored.SameAttributes(unresolved) {
+		t.Error("Missing brokerage_id should not be considered a change")
+	}
+	stored.CopyAttributes(unresolved)
+	if stored.BrokerageID == nil {
+		t.Error("Expected brokerage_id to be kept")
+	}
+}
+
+func TestStock_CanonicalRatings(t *testing.T) {
+	stored := Stock{Ticker: "AAPL", Company: "Apple Inc.", Provider: "primary", RatingFrom: "Hold", RatingTo: "Sector Outperform"}
+	incoming := stored
+	incoming.CanonicalRatingFrom, incoming.CanonicalRatingTo = RatingHold, RatingBuy
+
+	if stored.SameAttributes(incoming) {
+		t.Error("New canonical ratings should be considered a change")
+	}
+	fields := stored.ChangedFields(incoming)
+	if len(fields) != 2 || fields[0] != "canonical_rating_from" || fields[1] != "canonical_rating_to" {
+		t.Errorf("Expected canonical rating columns, got %v", fields)
+	}
+	stored.CopyAttributes(incoming)
+	if stored.CanonicalRatingFrom != RatingHold || stored.CanonicalRatingTo != RatingBuy {
+		t.Errorf("Expected canonical ratings to be copied, got %+v", stored)
+	}
+
+	// Un evento sin resolver no borra los ratings canónicos guardados
+	unresolved := Stock{Ticker: "AAPL", Company: "Apple Inc.", Provider: "primary", RatingFrom: "Hold", RatingTo: "Sector Outperform"}
+	if !stored.SameAttributes(unresolved) {
+		t.Error("Missing canonical ratings should not be considered a change")
+	}
+}
+
+func TestStock_TargetChange(t *testing.T) {
+	stock := Stock{TargetFrom: "$100.00", TargetTo: "$125.00"}
+	stock.ParseTargets()
+
+	change := stock.TargetChange()
+	if change == nil || *change != 25 {
+		t.Errorf("Expected 25%% change, got %v", change)
+	}
+
+	stock.TargetFrom = ""
+	stock.ParseTargets()
+	if stock.TargetChange() != nil {
+		t.Error("Expected nil change without target_from")
+	}
+}
+
+func TestStock_ParseTargets(t *testing.T) {
+	stock := Stock{TargetFrom: "€1.200,00", TargetTo: "€1.250,50"}
+	stock.ParseTargets()
+	if stock.TargetFromValue == nil || *stock.TargetFromValue != 12000000 ||
+		stock.TargetToValue == nil || *stock.TargetToValue != 12505000 {
+		t.Errorf("Unexpected values: %v %v", stock.TargetFromValue, stock.TargetToValue)
+	}
+	if stock.TargetCurrency != "EUR" || stock.TargetUnparsed {
+		t.Errorf("Expected EUR without flag, got %q unparsed=%v", stock.TargetCurrency, stock.TargetUnparsed)
+	}
+
+	// Un precio ilegible se marca y el otro se conserva
+	stock = Stock{TargetFrom: "N/A", TargetTo: "$125.00"}
+	stock.ParseTargets()
+	if stock.TargetFromValue != nil || stock.TargetToValue == nil || stock.TargetCurrency != "USD" || !stock.TargetUnparsed {
+		t.Errorf("Unexpected parse: %+v", stock)
+	}
+
+	// Monedas distintas no se pueden comparar
+	stock = Stock{TargetFrom: "$100.00", TargetTo: "€125,00"}
+	stock.ParseTargets()
+	if stock.TargetFromValue != nil || stock.TargetToValue != nil || stock.TargetCurrency != "" || !stock.TargetUnparsed {
+		t.Errorf("Expected mixed currencies to be flagged, got %+v", stock)
+	}
+
+	// Sin precios no hay nada que marcar
+	stock = Stock{}
+	stock.ParseTargets()
+	if stock.TargetUnparsed || stock.TargetCurrency != "" {
+		t.Errorf("Expected empty targets to be clean, got %+v", stock)
+	}
+}
+
+func TestStock_ReparsedTargets(t *testing.T) {
+	// Un registro que la migración dejó marcado aunque el parser lo entiende
+	stored := Stock{Ticker: "VOD", Company: "Vodafone", Provider: "primary", TargetFrom: "£120.00", TargetTo: "£130.00", TargetUnparsed: true}
+	incoming := stored
+	incoming.ParseTargets()
+
+	if stored.SameAttributes(incoming) {
+		t.Error("Re-parsed targets should be considered a change")
+	}
+	expected := []string{"target_from_value", "target_to_value", "target_currency", "target_unparsed"}
+	fields := stored.ChangedFields(incoming)
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, fields)
+		}
+	}
+
+	stored.CopyAttributes(incoming)
+	if stored.TargetUnparsed || stored.TargetCurrency != "GBP" || !stored.SameAttributes(incoming) {
+		t.Errorf("Expected targets to be repaired, got %+v", stored)
+	}
+}
+
+func TestNewQuarantinedStock_UnreadableKey(t *testing.T) {
+	first := NewQuarantinedStock(Stock{ParseError: "a.csv línea 2: bare quote"}, "unreadable_record")
+	second := NewQuarantinedStock(Stock{ParseError: "a.csv línea 7: bare quote"}, "unreadable_record")
+	if first.NaturalKey == second.NaturalKey {
+		t.Errorf("Expected distinct keys for unreadable records, got %q", first.NaturalKey)
+	}
+}
+
+func TestNewQuarantinedStock_KeepsRawTime(t *testing.T) {
+	first := NewQuarantinedStock(Stock{Ticker: "AAPL", Brokerage: "Goldman Sachs", RawTime: "ayer"}, "invalid_time")
+	second := NewQuarantinedStock(Stock{Ticker: "AAPL", Brokerage: "Goldman Sachs", RawTime: "31/02/2024"}, "invalid_time")
+
+	if first.RawTime != "ayer" {
+		t.Errorf("Expected raw time to be stored, got %q", first.RawTime)
+	}
+	if first.NaturalKey == second.NaturalKey {
+		t.Errorf("Expected distinct keys for different unreadable times, got %q", first.NaturalKey)
+	}
+	if got := first.ToStock().RawTime; got != "ayer" {
+		t.Errorf("Expected ToStock to keep the raw time, got %q", got)
+	}
+
+	// Con una fecha válida la llave no depende del texto original
+	when := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	dated := Stock{Ticker: "AAPL", Time: when, RawTime: "2024-03-01"}
+	if got := NewQuarantinedStock(dated, "").NaturalKey; got != dated.NaturalKey() {
+		t.Errorf("Expected key %q, got %q", dated.NaturalKey(), got)
+	}
+}
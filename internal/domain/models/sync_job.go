@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncJobStatus es el estado de un job de sincronización
+type SyncJobStatus string
+
+const (
+	SyncJobPending   SyncJobStatus = "pending"
+	SyncJobRunning   SyncJobStatus = "running"
+	SyncJobSucceeded SyncJobStatus = "succeeded"
+	SyncJobFailed    SyncJobStatus = "failed"
+	SyncJobCancelled SyncJobStatus = "cancelled"
+)
+
+// IsTerminal indica si el job ya no va a cambiar de estado
+func (s SyncJobStatus) IsTerminal() bool {
+	return s == SyncJobSucceeded || s == SyncJobFailed || s == SyncJobCancelled
+}
+
+// SyncJob registra una sincronización ejecutada en segundo plano y su progreso
+type SyncJob struct {
+	ID           uuid.UUID     `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Provider     string        `gorm:"column:provider" json:"provider"`
+	Mode         string        `gorm:"column:mode" json:"mode"`
+	Status       SyncJobStatus `gorm:"column:status;index" json:"status"`
+	PagesFetched int           `gorm:"column:pages_fetched" json:"pages_fetched"`
+	ItemsStored  int           `gorm:"column:items_stored" json:"items_stored"`
+	ItemsFailed  int           `gorm:"column:items_failed" json:"items_failed"`
+	// ItemsQuarantined son los registros que no pasaron la validación
+	ItemsQuarantined int        `gorm:"column:items_quarantined" json:"items_quarantined"`
+	Inserted         int        `gorm:"column:inserted" json:"inserted"`
+	Updated          int        `gorm:"column:updated" json:"updated"`
+	Unchanged        int        `gorm:"column:unchanged" json:"unchanged"`
+	Error            string     `gorm:"column:error" json:"error,omitempty"`
+	StartedAt        *time.Time `gorm:"column:started_at" json:"started_at"`
+	FinishedAt       *time.Time `gorm:"column:finished_at" json:"finished_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// SyncState guarda el checkpoint de la sincronización con un proveedor:
+// el token de la siguiente página por recorrer y el evento más reciente ingerido.
+type SyncState struct {
+	Name string `gorm:"column:name;primaryKey" json:"name"`
+	// NextPage es el token pendiente de un recorrido interrumpido ("" si terminó)
+	NextPage string `gorm:"column:next_page" json:"next_page"`
+	// LatestTime es el Time más reciente de un recorrido completo
+	LatestTime time.Time `gorm:"column:latest_time" json:"latest_time"`
+	// PendingLatestTime es el Time más reciente visto en el recorrido en curso;
+	// solo se consolida en LatestTime cuando el recorrido termina
+	PendingLatestTime time.Time  `gorm:"column:pending_latest_time" json:"pending_latest_time"`
+	LastMode          string     `gorm:"column:last_mode" json:"last_mode"`
+	LastCompletedAt   *time.Time `gorm:"column:last_completed_at" json:"last_completed_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
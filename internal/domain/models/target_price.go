@@ -0,0 +1,159 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultTargetCurrency es la moneda de los precios objetivo sin símbolo ni código
+const DefaultTargetCurrency = "USD"
+
+// TargetPrice es un precio objetivo interpretado
+type TargetPrice struct {
+	Amount   Decimal
+	Currency string
+}
+
+// currencySymbols traduce símbolos al código ISO 4217; los más largos van
+// primero para que "US$" no se lea como "$"
+var currencySymbols = []struct{ symbol, code string }{
+	{"US$", "USD"}, {"CA$", "CAD"}, {"C$", "CAD"}, {"A$", "AUD"}, {"R$", "BRL"},
+	{"$", "USD"}, {"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"},
+}
+
+// currencyCodes son los códigos que se aceptan escritos junto al número
+var currencyCodes = map[string]bool{
+	"USD": true, "EUR": true, "GBP": true, "JPY": true, "CAD": true,
+	"AUD": true, "CHF": true, "BRL": true,
+}
+
+// commaDecimalCurrencies escriben los importes con coma decimal y punto de
+// miles ("€1.250,50"); se usa solo cuando el texto es ambiguo
+var commaDecimalCurrencies = map[string]bool{"EUR": true, "BRL": true}
+
+// ParseTargetPrice interpreta un precio objetivo con su moneda: "$1,250.50",
+// "€1.250,50", "1 250,50 EUR", "CHF 1'250.50". El separador decimal es el
+// último que aparece; si solo hay uno seguido de tres dígitos ("1,250" o
+// "1.250") decide la convención de la moneda.
+func ParseTargetPrice(s string) (TargetPrice, error) {
+	number, currency := splitCurrency(strings.TrimSpace(s))
+	if currency == "" {
+		currency = DefaultTargetCurrency
+	}
+
+	plain, err := normalizeAmount(number, commaDecimalCurrencies[currency])
+	if err != nil {
+		return TargetPrice{}, fmt.Errorf("%q no es un precio válido: %w", s, err)
+	}
+	amount, err := ParseDecimal(plain)
+	if err != nil || amount <= 0 {
+		return TargetPrice{}, fmt.Errorf("%q no es un precio válido", s)
+	}
+	return TargetPrice{Amount: amount, Currency: currency}, nil
+}
+
+// splitCurrency separa el símbolo o código de moneda, antes o después del número
+func splitCurrency(s string) (string, string) {
+	if len(s) > 3 {
+		if code := strings.ToUpper(s[:3]); currencyCodes[code] && !unicode.IsLetter(rune(s[3])) {
+			return strings.TrimSpace(s[3:]), code
+		}
+		if code := strings.ToUpper(s[len(s)-3:]); currencyCodes[code] && !unicode.IsLetter(rune(s[len(s)-4])) {
+			return strings.TrimSpace(s[:len(s)-3]), code
+		}
+	}
+	for _, c := range currencySymbols {
+		if strings.HasPrefix(s, c.symbol) {
+			return strings.TrimSpace(strings.TrimPrefix(s, c.symbol)), c.code
+		}
+		if strings.HasSuffix(s, c.symbol) {
+			return strings.TrimSpace(strings.TrimSuffix(s, c.symbol)), c.code
+		}
+	}
+	return s, ""
+}
+
+// normalizeAmount quita los separadores de miles y deja el punto como
+// separador decimal
+func normalizeAmount(s string, commaDecimal bool) (string, error) {
+	// Espacios (incluidos los no separables) y apóstrofos solo agrupan miles
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '\'' {
+			return -1
+		}
+		return r
+	}, s)
+	if s == "" {
+		return "", fmt.Errorf("vacío")
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && r != '.' && r != ',' {
+			return "", fmt.Errorf("carácter %q inesperado", r)
+		}
+	}
+
+	lastDot, lastComma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	var decimalSep byte
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		decimalSep = s[max(lastDot, lastComma)]
+	case lastDot >= 0 || lastComma >= 0:
+		sep := byte('.')
+		if lastComma >= 0 {
+			sep = ','
+		}
+		count := strings.Count(s, string(sep))
+		digitsAfter := len(s) - strings.LastIndexByte(s, sep) - 1
+		groupSep := byte(',')
+		if commaDecimal {
+			groupSep = '.'
+		}
+		// Un solo separador con tres dígitos detrás es de miles si es el de la moneda
+		if count == 1 && (digitsAfter != 3 || sep != groupSep) {
+			decimalSep = sep
+		}
+	}
+
+	whole, frac := s, ""
+	if decimalSep != 0 {
+		i := strings.LastIndexByte(s, decimalSep)
+		whole, frac = s[:i], s[i+1:]
+		if frac == "" || strings.ContainsAny(frac, ".,") {
+			return "", fmt.Errorf("decimales inválidos")
+		}
+	}
+	digits, err := ungroup(whole)
+	if err != nil {
+		return "", err
+	}
+	if frac == "" {
+		return digits, nil
+	}
+	return digits + "." + frac, nil
+}
+
+// ungroup valida que los miles vengan en grupos de tres y los quita
+func ungroup(whole string) (string, error) {
+	if strings.Contains(whole, ".") && strings.Contains(whole, ",") {
+		return "", fmt.Errorf("separadores de miles mezclados")
+	}
+	groups := strings.FieldsFunc(whole, func(r rune) bool { return r == '.' || r == ',' })
+	if len(groups) == 0 {
+		return "", fmt.Errorf("sin dígitos")
+	}
+	if strings.Count(whole, ".")+strings.Count(whole, ",") != len(groups)-1 {
+		return "", fmt.Errorf("separador de miles mal ubicado")
+	}
+	if len(groups) > 1 {
+		if len(groups[0]) > 3 {
+			return "", fmt.Errorf("separador de miles mal ubicado")
+		}
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return "", fmt.Errorf("separador de miles mal ubicado")
+			}
+		}
+	}
+	return strings.Join(groups, ""), nil
+}
@@ -0,0 +1,55 @@
+package models
+
+import "testing"
+
+func TestParseTargetPrice(t *testing.T) {
+	tests := []struct {
+		input    string
+		amount   string
+		currency string
+	}{
+		{"$1,250.50", "1250.50", "USD"},
+		{"$1,250.00", "1250.00", "USD"},
+		{"$1,250", "1250.00", "USD"},
+		{"1,250", "1250.00", "USD"},
+		{"$150", "150.00", "USD"},
+		{"150.5", "150.50", "USD"},
+		{"150,50", "150.50", "USD"},
+		{"$1,234,567.89", "1234567.89", "USD"},
+		{"US$ 99.99", "99.99", "USD"},
+		{"USD 1,250.50", "1250.50", "USD"},
+		{"€1.250,50", "1250.50", "EUR"},
+		{"€1.250", "1250.00", "EUR"},
+		{"1.250,50 €", "1250.50", "EUR"},
+		{"1 250,50 EUR", "1250.50", "EUR"},
+		{"€ 12,5", "12.50", "EUR"},
+		{"£1,250.50", "1250.50", "GBP"},
+		{"CHF 1'250.50", "1250.50", "CHF"},
+		{"C$45.00", "45.00", "CAD"},
+		{"R$ 1.250,00", "1250.00", "BRL"},
+		{"¥12,000", "12000.00", "JPY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			price, err := ParseTargetPrice(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if price.Amount.String() != tt.amount || price.Currency != tt.currency {
+				t.Errorf("ParseTargetPrice(%q) = %s %s; expected %s %s", tt.input, price.Amount, price.Currency, tt.amount, tt.currency)
+			}
+		})
+	}
+}
+
+func TestParseTargetPrice_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"", "N/A", "$", "$0.00", "-$5", "$12,50,000", "1,250.50.00",
+		"$1.250,50.00", ",250", "$1.23456", "12 apples",
+	} {
+		if price, err := ParseTargetPrice(input); err == nil {
+			t.Errorf("ParseTargetPrice(%q) = %s %s; expected error", input, price.Amount, price.Currency)
+		}
+	}
+}
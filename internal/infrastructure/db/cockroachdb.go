@@ -5,14 +5,14 @@ import (
 	"log"
 
 	"github.com/juanF18/EquiSignal-Backend/internal/config"
-	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
-// Connect abre la conexión a CockroachDB con GORM
+// Connect abre la conexión a CockroachDB con GORM. El esquema se maneja
+// con las migraciones versionadas (ver EnsureSchema y `app migrate`).
 func ConnectCockroachDB(cfg *config.Config) {
 	dsn := fmt.Sprintf(
 		"postgresql://%s:%s@%s:%s/%s?sslmode=verify-full",
@@ -28,11 +28,6 @@ func ConnectCockroachDB(cfg *config.Config) {
 		log.Fatal("❌ Error conectando a CockroachDB: ", err)
 	}
 
-	err = db.AutoMigrate(&models.Stock{})
-	if err != nil {
-		log.Fatal("❌ Error al migrar la base de datos: ", err)
-	}
-
 	DB = db
 	log.Println("✅ Conectado a CockroachDB con éxito")
 }
@@ -0,0 +1,382 @@
+package db
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration es un cambio de esquema numerado con su reversa
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// MigrationStatus indica si una migración ya se aplicó
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// migrationFilePattern reconoce archivos como 0003_add_indexes.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+const (
+	// migrationLockTTL libera el lock de una instancia que murió migrando
+	migrationLockTTL = 10 * time.Minute
+	// migrationLockWait es cuánto espera una instancia a que otra termine de migrar
+	migrationLockWait = 2 * time.Minute
+)
+
+// ErrPendingMigrations se devuelve al arrancar con migraciones sin aplicar
+var ErrPendingMigrations = errors.New("hay migraciones pendientes")
+
+// LoadMigrations lee los pares up/down del directorio y los ordena por versión
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		m := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("nombre de migración inválido: %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("la versión %d tiene dos nombres: %s y %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("la migración %s necesita archivos up y down", m)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator aplica las migraciones embebidas y las registra en schema_migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator usa las migraciones embebidas en el binario
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	sub, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := LoadMigrations(sub)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+type schemaMigration struct {
+	Version   int       `gorm:"column:version;primaryKey"`
+	Name      string    `gorm:"column:name"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+func (m *Migrator) ensureTables() error {
+	return m.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+		CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+			id INT NOT NULL PRIMARY KEY,
+			owner TEXT NOT NULL,
+			locked_at TIMESTAMPTZ NOT NULL
+		);`).Error
+}
+
+func (m *Migrator) applied() (map[int]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := m.db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// Status lista todas las migraciones y si ya se aplicaron
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	if err := m.ensureTables(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	return migrationStatuses(m.migrations, applied), nil
+}
+
+// Pending devuelve las migraciones sin aplicar, en orden
+func (m *Migrator) Pending() ([]Migration, error) {
+	if err := m.ensureTables(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	return pendingMigrations(m.migrations, applied), nil
+}
+
+// Up aplica todas las migraciones pendientes. Toma un lock para que dos
+// instancias arrancando a la vez no migren en paralelo.
+func (m *Migrator) Up() ([]Migration, error) {
+	if err := m.ensureTables(); err != nil {
+		return nil, err
+	}
+	release, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Se recalcula con el lock tomado: otra instancia pudo haber migrado
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range pendingMigrations(m.migrations, applied) {
+		log.Printf("⬆️ Aplicando migración %s", migration)
+		if err := m.exec(migration.Up); err != nil {
+			return done, fmt.Errorf("migración %s: %w", migration, err)
+		}
+		row := schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+		if err := m.db.Create(&row).Error; err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down revierte las últimas steps migraciones aplicadas
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	if err := m.ensureTables(); err != nil {
+		return nil, err
+	}
+	release, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		log.Printf("⬇️ Revirtiendo migración %s", migration)
+		if err := m.exec(migration.Down); err != nil {
+			return done, fmt.Errorf("migración %s: %w", migration, err)
+		}
+		if err := m.db.Delete(&schemaMigration{}, "version = ?", migration.Version).Error; err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// exec corre cada sentencia por separado: CockroachDB no permite escribir
+// en una columna nueva dentro de la misma transacción que la crea. Por eso
+// las sentencias deben ser idempotentes (IF NOT EXISTS) para poder
+// re-ejecutar una migración que falló a la mitad.
+func (m *Migrator) exec(script string) error {
+	for _, stmt := range splitStatements(script) {
+		if err := m.db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements separa un script por ';', ignorando los que están dentro
+// de strings o comentarios
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	inString, inComment := false, false
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case inComment:
+			if c == '\n' {
+				inComment = false
+				current.WriteByte(c)
+			}
+			continue
+		case inString:
+			if c == '\'' {
+				inString = false
+			}
+		case c == '\'':
+			inString = true
+		case c == '-' && i+1 < len(script) && script[i+1] == '-':
+			inComment = true
+			continue
+		case c == ';':
+			if stmt := strings.TrimSpace(current.String()); stmt != "" {
+				statements = append(statements, stmt)
+			}
+			current.Reset()
+			continue
+		}
+		current.WriteByte(c)
+	}
+
+	if stmt := strings.TrimSpace(current.String()); stmt != "" {
+		statements = append(statements, stmt)
+	}
+	return statements
+}
+
+// lock toma el lock de migraciones, esperando si otra instancia lo tiene.
+// Un lock más viejo que migrationLockTTL se considera abandonado.
+func (m *Migrator) lock() (func(), error) {
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano())
+	deadline := time.Now().Add(migrationLockWait)
+
+	for {
+		if err := m.db.Exec("DELETE FROM schema_migrations_lock WHERE locked_at < ?", time.Now().Add(-migrationLockTTL)).Error; err != nil {
+			return nil, err
+		}
+		res := m.db.Exec("INSERT INTO schema_migrations_lock (id, owner, locked_at) VALUES (1, ?, ?) ON CONFLICT (id) DO NOTHING", owner, time.Now())
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("otra instancia tiene el lock de migraciones")
+		}
+		log.Println("⏳ Esperando a que otra instancia termine de migrar")
+		time.Sleep(2 * time.Second)
+	}
+
+	return func() {
+		if err := m.db.Exec("DELETE FROM schema_migrations_lock WHERE owner = ?", owner).Error; err != nil {
+			log.Println("⚠️ Error liberando el lock de migraciones:", err)
+		}
+	}, nil
+}
+
+func pendingMigrations(migrations []Migration, applied map[int]schemaMigration) []Migration {
+	var pending []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+func migrationStatuses(migrations []Migration, applied map[int]schemaMigration) []MigrationStatus {
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if row, ok := applied[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// EnsureSchema aplica las migraciones pendientes al arrancar, o con
+// autoMigrate=false devuelve ErrPendingMigrations si falta alguna
+func EnsureSchema(db *gorm.DB, autoMigrate bool) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	if autoMigrate {
+		applied, err := migrator.Up()
+		if len(applied) > 0 {
+			log.Printf("✅ %d migraciones aplicadas", len(applied))
+		}
+		return err
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %v (ejecuta `app migrate up`)", ErrPendingMigrations, pending)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package db
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_index.up.sql":   {Data: []byte("CREATE INDEX a ON t (x);")},
+		"0002_add_index.down.sql": {Data: []byte("DROP INDEX a;")},
+		"0001_init.up.sql":        {Data: []byte("CREATE TABLE t (x INT);")},
+		"0001_init.down.sql":      {Data: []byte("DROP TABLE t;")},
+	}
+
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("Expected 2 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != 1 || migrations[1].Version != 2 {
+		t.Errorf("Expected migrations sorted by version, got %v", migrations)
+	}
+	if migrations[1].String() != "0002_add_index" || !strings.Contains(migrations[1].Down, "DROP INDEX") {
+		t.Errorf("Unexpected migration: %+v", migrations[1])
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"Missing down", fstest.MapFS{"0001_init.up.sql": {Data: []byte("SELECT 1;")}}},
+		{"Bad file name", fstest.MapFS{"init.sql": {Data: []byte("SELECT 1;")}}},
+		{"Two names for one version", fstest.MapFS{
+			"0001_init.up.sql":    {Data: []byte("SELECT 1;")},
+			"0001_other.down.sql": {Data: []byte("SELECT 1;")},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadMigrations(tc.fsys); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrator, err := NewMigrator(nil)
+	if err != nil {
+		t.Fatalf("Embedded migrations should load: %v", err)
+	}
+	for i, m := range migrator.migrations {
+		if m.Version != i+1 {
+			t.Errorf("Expected consecutive versions, got %s at position %d", m, i)
+		}
+	}
+}
+
+func TestPendingMigrationsAndStatus(t *testing.T) {
+	migrations := []Migration{{Version: 1, Name: "init"}, {Version: 2, Name: "index"}, {Version: 3, Name: "backfill"}}
+	appliedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	applied := map[int]schemaMigration{1: {Version: 1, Name: "init", AppliedAt: appliedAt}}
+
+	pending := pendingMigrations(migrations, applied)
+	if len(pending) != 2 || pending[0].Version != 2 {
+		t.Errorf("Expected migrations 2 and 3 pending, got %v", pending)
+	}
+
+	statuses := migrationStatuses(migrations, applied)
+	if !statuses[0].Applied || !statuses[0].AppliedAt.Equal(appliedAt) {
+		t.Errorf("Expected migration 1 applied, got %+v", statuses[0])
+	}
+	if statuses[2].Applied {
+		t.Errorf("Expected migration 3 pending, got %+v", statuses[2])
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- comentario; con punto y coma
+ALTER TABLE t ADD COLUMN x INT;
+UPDATE t SET y = 'a;b' WHERE x ~ '^\s*$'; -- fin
+CREATE INDEX i ON t (x)`
+
+	statements := splitStatements(script)
+
+	if len(statements) != 3 {
+		t.Fatalf("Expected 3 statements, got %d: %q", len(statements), statements)
+	}
+	if statements[1] != `UPDATE t SET y = 'a;b' WHERE x ~ '^\s*$'` {
+		t.Errorf("Unexpected statement: %q", statements[1])
+	}
+	if statements[2] != "CREATE INDEX i ON t (x)" {
+		t.Errorf("Unexpected statement: %q", statements[2])
+	}
+}
+
+func TestEmbeddedMigrations_Split(t *testing.T) {
+	migrator, _ := NewMigrator(nil)
+	for _, m := range migrator.migrations {
+		for _, stmt := range splitStatements(m.Up) {
+			if strings.HasPrefix(stmt, "--") {
+				t.Errorf("%s: comment left in statement %q", m, stmt)
+			}
+		}
+	}
+}
+
+func TestBrokerageAliasSeed_Normalized(t *testing.T) {
+	migrator, _ := NewMigrator(nil)
+	var seed string
+	for _, m := range migrator.migrations {
+		if m.Name == "brokerages" {
+			seed = m.Up
+		}
+	}
+	if seed == "" {
+		t.Fatal("Expected brokerages migration")
+	}
+
+	// Filas ('normalized', 'alias', 'brokerage') del seed de alias
+	rows := regexp.MustCompile(`\('([a-z0-9]+)', '([^']+)', '[^']+'\)`).FindAllStringSubmatch(seed, -1)
+	if len(rows) == 0 {
+		t.Fatal("Expected alias seed rows")
+	}
+	for _, row := range rows {
+		if got := models.NormalizeBrokerageName(row[2]); got != row[1] {
+			t.Errorf("Alias %q normalizes to %q, seed has %q", row[2], got, row[1])
+		}
+	}
+}
+
+func TestRatingMappingSeed_MatchesDefaults(t *testing.T) {
+	migrator, _ := NewMigrator(nil)
+	var seed string
+	for _, m := range migrator.migrations {
+		if m.Name == "rating_taxonomy" {
+			seed = m.Up
+		}
+	}
+	if seed == "" {
+		t.Fatal("Expected rating_taxonomy migration")
+	}
+
+	// Filas ('normalized', 'label', 'rating', now(), now()) del seed
+	rows := regexp.MustCompile(`\('([a-z0-9 ]+)', '([^']+)', '([a-z_]+)', now\(\), now\(\)\)`).FindAllStringSubmatch(seed, -1)
+	if len(rows) != len(models.DefaultRatingMappings) {
+		t.Errorf("Expected %d seed rows, got %d", len(models.DefaultRatingMappings), len(rows))
+	}
+	for _, row := range rows {
+		if got := models.NormalizeRatingLabel(row[2]); got != row[1] {
+			t.Errorf("Label %q normalizes to %q, seed has %q", row[2], got, row[1])
+		}
+		if expected := models.DefaultRatingMappings[row[1]]; expected != row[3] {
+			t.Errorf("Seed maps %q to %q, defaults have %q", row[1], row[3], expected)
+		}
+	}
+}
+
+func TestTargetBackfill_MatchesParser(t *testing.T) {
+	migrator, _ := NewMigrator(nil)
+	var script string
+	for _, m := range migrator.migrations {
+		if m.Name == "target_decimals" {
+			script = m.Up
+		}
+	}
+
+	// Patrones del backfill: primero el de dólares y luego el de euros
+	patterns := regexp.MustCompile(`target_from ~ '(\^[^']+)'`).FindAllStringSubmatch(script, -1)
+	if len(patterns) != 2 {
+		t.Fatalf("Expected dollar and euro patterns, got %d", len(patterns))
+	}
+	dollars, euros := regexp.MustCompile(patterns[0][1]), regexp.MustCompile(patterns[1][1])
+	dollarDigits, euroDigits := regexp.MustCompile(`[^0-9.]`), regexp.MustCompile(`[^0-9,]`)
+
+	samples := []string{
+		"$150", "$150.00", "$1,250.50", "1,250", "US$ 99.99", "$1,234,567.8912",
+		"€1.250,50", "€ 1.250", "1.250,50 €", "€12,5",
+		"150,50", "$12,50,000", "N/A", "EUR 10", "£100",
+	}
+	for _, s := range samples {
+		var expected, currency string
+		switch {
+		case dollars.MatchString(s):
+			expected, currency = dollarDigits.ReplaceAllString(s, ""), "USD"
+		case euros.MatchString(s):
+			expected, currency = strings.ReplaceAll(euroDigits.ReplaceAllString(s, ""), ",", "."), "EUR"
+		default:
+			// Lo que el backfill no reconoce queda marcado; no hace falta que coincida
+			continue
+		}
+		want, err := models.ParseDecimal(expected)
+		if err != nil {
+			t.Fatalf("Backfill of %q produces %q: %v", s, expected, err)
+		}
+		price, err := models.ParseTargetPrice(s)
+		if err != nil || price.Amount != want || price.Currency != currency {
+			t.Errorf("Backfill reads %q as %s %s, parser as %s %s (%v)", s, want, currency, price.Amount, price.Currency, err)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+-- Revierte el esquema base: elimina todas las tablas y sus datos
+DROP TABLE IF EXISTS raw_payloads;
+DROP TABLE IF EXISTS quarantined_stocks;
+DROP TABLE IF EXISTS sync_jobs;
+DROP TABLE IF EXISTS sync_states;
+DROP TABLE IF EXISTS stocks;
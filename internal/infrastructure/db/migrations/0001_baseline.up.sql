@@ -0,0 +1,106 @@
+-- Esquema base: equivale a lo que creaba AutoMigrate. Usa IF NOT EXISTS para
+-- que las bases creadas con AutoMigrate queden registradas sin cambios.
+
+CREATE TABLE IF NOT EXISTS stocks (
+    id UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    ticker TEXT,
+    company TEXT,
+    brokerage TEXT,
+    action TEXT,
+    rating_from TEXT,
+    rating_to TEXT,
+    target_from TEXT,
+    target_to TEXT,
+    time TIMESTAMPTZ,
+    provider TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+-- Bases anteriores a la columna provider
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS provider TEXT;
+
+-- Antes de crear el índice único se eliminan duplicados históricos,
+-- conservando el más antiguo
+DELETE FROM stocks WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY ticker, brokerage, time, action, rating_to, target_to
+            ORDER BY created_at, id
+        ) AS rn
+        FROM stocks
+    ) ranked
+    WHERE rn > 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stocks_natural_key ON stocks (ticker, brokerage, action, rating_to, target_to, time);
+CREATE INDEX IF NOT EXISTS idx_stocks_provider ON stocks (provider);
+
+CREATE TABLE IF NOT EXISTS sync_states (
+    name TEXT NOT NULL PRIMARY KEY,
+    next_page TEXT,
+    latest_time TIMESTAMPTZ,
+    pending_latest_time TIMESTAMPTZ,
+    last_mode TEXT,
+    last_completed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS sync_jobs (
+    id UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    provider TEXT,
+    mode TEXT,
+    status TEXT,
+    pages_fetched BIGINT,
+    items_stored BIGINT,
+    items_failed BIGINT,
+    items_quarantined BIGINT,
+    inserted BIGINT,
+    updated BIGINT,
+    unchanged BIGINT,
+    error TEXT,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+ALTER TABLE sync_jobs ADD COLUMN IF NOT EXISTS items_quarantined BIGINT;
+CREATE INDEX IF NOT EXISTS idx_sync_jobs_status ON sync_jobs (status);
+
+CREATE TABLE IF NOT EXISTS quarantined_stocks (
+    id UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    natural_key TEXT,
+    provider TEXT,
+    ticker TEXT,
+    company TEXT,
+    brokerage TEXT,
+    action TEXT,
+    rating_from TEXT,
+    rating_to TEXT,
+    target_from TEXT,
+    target_to TEXT,
+    time TIMESTAMPTZ,
+    reasons TEXT,
+    status TEXT,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quarantined_stocks_natural_key ON quarantined_stocks (natural_key);
+CREATE INDEX IF NOT EXISTS idx_quarantined_stocks_status ON quarantined_stocks (status);
+
+CREATE TABLE IF NOT EXISTS raw_payloads (
+    id UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    provider TEXT,
+    page_token TEXT,
+    url TEXT,
+    status_code BIGINT,
+    body BYTEA,
+    size BIGINT,
+    fetched_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_raw_payloads_provider_fetched ON raw_payloads (provider, fetched_at);
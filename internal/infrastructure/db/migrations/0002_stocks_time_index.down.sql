@@ -0,0 +1 @@
+DROP INDEX IF EXISTS stocks@idx_stocks_time;
@@ -0,0 +1,2 @@
+-- El listado de stocks ordena por time DESC
+CREATE INDEX IF NOT EXISTS idx_stocks_time ON stocks (time DESC);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS stocks@idx_stocks_target_change_pct;
+DROP INDEX IF EXISTS stocks@idx_stocks_action_lower;
+DROP INDEX IF EXISTS stocks@idx_stocks_rating_from_lower;
+DROP INDEX IF EXISTS stocks@idx_stocks_rating_to_lower;
+DROP INDEX IF EXISTS stocks@idx_stocks_brokerage_lower;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_change_pct;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_to_value;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_from_value;
@@ -0,0 +1,21 @@
+-- Precios objetivo numéricos para filtrar por variación y ordenar.
+-- Los textos que no se pueden interpretar quedan en NULL.
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_from_value FLOAT8;
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_to_value FLOAT8;
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_change_pct FLOAT8 AS (
+    CASE WHEN target_from_value > 0 THEN (target_to_value - target_from_value) / target_from_value * 100 END
+) STORED;
+
+UPDATE stocks SET
+    target_from_value = CASE WHEN target_from ~ '^\s*\$?[0-9][0-9,]*(\.[0-9]+)?\s*$'
+        THEN NULLIF(CAST(regexp_replace(target_from, '[$,\s]', '', 'g') AS FLOAT8), 0) END,
+    target_to_value = CASE WHEN target_to ~ '^\s*\$?[0-9][0-9,]*(\.[0-9]+)?\s*$'
+        THEN NULLIF(CAST(regexp_replace(target_to, '[$,\s]', '', 'g') AS FLOAT8), 0) END
+WHERE target_from_value IS NULL AND target_to_value IS NULL;
+
+-- Índices para los filtros de GET /api/stocks (comparan en minúsculas)
+CREATE INDEX IF NOT EXISTS idx_stocks_brokerage_lower ON stocks (lower(brokerage), time DESC);
+CREATE INDEX IF NOT EXISTS idx_stocks_rating_to_lower ON stocks (lower(rating_to), time DESC);
+CREATE INDEX IF NOT EXISTS idx_stocks_rating_from_lower ON stocks (lower(rating_from), time DESC);
+CREATE INDEX IF NOT EXISTS idx_stocks_action_lower ON stocks (lower(action), time DESC);
+CREATE INDEX IF NOT EXISTS idx_stocks_target_change_pct ON stocks (target_change_pct);
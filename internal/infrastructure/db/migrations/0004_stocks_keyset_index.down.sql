@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_stocks_time ON stocks (time DESC);
+DROP INDEX IF EXISTS stocks@idx_stocks_time_id;
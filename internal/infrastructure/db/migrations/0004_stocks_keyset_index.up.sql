@@ -0,0 +1,4 @@
+-- La paginación por cursor recorre (time, id); este índice reemplaza a
+-- idx_stocks_time, que queda cubierto por su prefijo
+CREATE INDEX IF NOT EXISTS idx_stocks_time_id ON stocks (time DESC, id DESC);
+DROP INDEX IF EXISTS stocks@idx_stocks_time;
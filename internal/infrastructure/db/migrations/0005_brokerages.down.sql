@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS stocks@idx_stocks_brokerage_id;
+ALTER TABLE stocks DROP COLUMN IF EXISTS brokerage_id;
+DROP TABLE IF EXISTS brokerage_aliases;
+DROP TABLE IF EXISTS brokerages;
@@ -0,0 +1,63 @@
+-- Brokerages canónicos con su peso de credibilidad y los alias con los que
+-- llegan de los proveedores. normalized es el nombre pasado por
+-- models.NormalizeBrokerageName.
+CREATE TABLE IF NOT EXISTS brokerages (
+    id UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    name TEXT NOT NULL,
+    tier INT8 NOT NULL DEFAULT 3,
+    weight FLOAT8 NOT NULL DEFAULT 0.7,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT check_brokerages_tier CHECK (tier BETWEEN 1 AND 3),
+    CONSTRAINT check_brokerages_weight CHECK (weight > 0 AND weight <= 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_brokerages_name ON brokerages (name);
+
+CREATE TABLE IF NOT EXISTS brokerage_aliases (
+    normalized TEXT NOT NULL PRIMARY KEY,
+    alias TEXT NOT NULL,
+    brokerage_id UUID NOT NULL REFERENCES brokerages (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_brokerage_aliases_brokerage_id ON brokerage_aliases (brokerage_id);
+
+-- Brokerage canónico de cada evento; se completa al arrancar el servidor
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS brokerage_id UUID REFERENCES brokerages (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_stocks_brokerage_id ON stocks (brokerage_id);
+
+-- Los mismos pesos que tenía el mapa fijo del recomendador
+INSERT INTO brokerages (name, tier, weight, created_at, updated_at) VALUES
+    ('Goldman Sachs', 1, 1.0, now(), now()),
+    ('Morgan Stanley', 1, 1.0, now(), now()),
+    ('JPMorgan', 1, 1.0, now(), now()),
+    ('Bank of America', 1, 0.95, now(), now()),
+    ('Citigroup', 1, 0.95, now(), now()),
+    ('Wells Fargo', 1, 0.9, now(), now()),
+    ('Barclays', 1, 0.9, now(), now()),
+    ('Deutsche Bank', 2, 0.85, now(), now()),
+    ('Credit Suisse', 2, 0.85, now(), now()),
+    ('UBS', 2, 0.85, now(), now()),
+    ('Jefferies', 2, 0.8, now(), now()),
+    ('Cowen', 2, 0.8, now(), now()),
+    ('Piper Sandler', 2, 0.75, now(), now())
+ON CONFLICT (name) DO NOTHING;
+
+-- Nombres que no se reducen al del brokerage al normalizar
+INSERT INTO brokerage_aliases (normalized, alias, brokerage_id)
+SELECT a.normalized, a.alias, b.id
+FROM (VALUES
+    ('goldman', 'Goldman', 'Goldman Sachs'),
+    ('jpmorganchase', 'JPMorgan Chase', 'JPMorgan'),
+    ('bofa', 'BofA Securities', 'Bank of America'),
+    ('merrilllynch', 'Merrill Lynch', 'Bank of America'),
+    ('bofamerrilllynch', 'BofA Merrill Lynch', 'Bank of America'),
+    ('citi', 'Citi', 'Citigroup'),
+    ('citigroupglobalmarkets', 'Citigroup Global Markets', 'Citigroup'),
+    ('barclayscapital', 'Barclays Capital', 'Barclays'),
+    ('jefferiesfinancial', 'Jefferies Financial Group', 'Jefferies'),
+    ('tdcowen', 'TD Cowen', 'Cowen'),
+    ('piperjaffray', 'Piper Jaffray', 'Piper Sandler')
+) AS a (normalized, alias, brokerage)
+JOIN brokerages b ON b.name = a.brokerage
+ON CONFLICT (normalized) DO NOTHING;
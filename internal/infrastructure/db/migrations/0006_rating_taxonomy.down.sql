@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS stocks@idx_stocks_canonical_rating_to;
+ALTER TABLE stocks DROP COLUMN IF EXISTS canonical_rating_to;
+ALTER TABLE stocks DROP COLUMN IF EXISTS canonical_rating_from;
+DROP TABLE IF EXISTS rating_mappings;
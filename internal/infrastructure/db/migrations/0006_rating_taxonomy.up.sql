@@ -0,0 +1,53 @@
+-- Vocabulario de ratings: cada texto normalizado (ver
+-- models.NormalizeRatingLabel) se traduce a un nivel de la escala canónica.
+-- Los admins lo extienden con /api/admin/ratings.
+CREATE TABLE IF NOT EXISTS rating_mappings (
+    normalized TEXT NOT NULL PRIMARY KEY,
+    label TEXT NOT NULL,
+    rating TEXT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT check_rating_mappings_rating CHECK (rating IN ('strong_buy', 'buy', 'hold', 'underperform', 'sell'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_rating_mappings_rating ON rating_mappings (rating);
+
+-- Ratings canónicos de cada evento; se completan al arrancar el servidor
+-- (vacío si el texto no está en rating_mappings)
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS canonical_rating_from TEXT NOT NULL DEFAULT '';
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS canonical_rating_to TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_stocks_canonical_rating_to ON stocks (canonical_rating_to);
+
+-- El mismo vocabulario que models.DefaultRatingMappings
+INSERT INTO rating_mappings (normalized, label, rating, created_at, updated_at) VALUES
+    ('strong buy', 'Strong Buy', 'strong_buy', now(), now()),
+    ('top pick', 'Top Pick', 'strong_buy', now(), now()),
+    ('conviction buy', 'Conviction Buy', 'strong_buy', now(), now()),
+    ('buy', 'Buy', 'buy', now(), now()),
+    ('outperform', 'Outperform', 'buy', now(), now()),
+    ('overweight', 'Overweight', 'buy', now(), now()),
+    ('positive', 'Positive', 'buy', now(), now()),
+    ('market outperform', 'Market Outperform', 'buy', now(), now()),
+    ('sector outperform', 'Sector Outperform', 'buy', now(), now()),
+    ('speculative buy', 'Speculative Buy', 'buy', now(), now()),
+    ('moderate buy', 'Moderate Buy', 'buy', now(), now()),
+    ('accumulate', 'Accumulate', 'buy', now(), now()),
+    ('hold', 'Hold', 'hold', now(), now()),
+    ('neutral', 'Neutral', 'hold', now(), now()),
+    ('market perform', 'Market Perform', 'hold', now(), now()),
+    ('sector perform', 'Sector Perform', 'hold', now(), now()),
+    ('peer perform', 'Peer Perform', 'hold', now(), now()),
+    ('equal weight', 'Equal Weight', 'hold', now(), now()),
+    ('sector weight', 'Sector Weight', 'hold', now(), now()),
+    ('in line', 'In-Line', 'hold', now(), now()),
+    ('inline', 'Inline', 'hold', now(), now()),
+    ('underweight', 'Underweight', 'underperform', now(), now()),
+    ('underperform', 'Underperform', 'underperform', now(), now()),
+    ('market underperform', 'Market Underperform', 'underperform', now(), now()),
+    ('sector underperform', 'Sector Underperform', 'underperform', now(), now()),
+    ('negative', 'Negative', 'underperform', now(), now()),
+    ('reduce', 'Reduce', 'underperform', now(), now()),
+    ('moderate sell', 'Moderate Sell', 'underperform', now(), now()),
+    ('sell', 'Sell', 'sell', now(), now()),
+    ('strong sell', 'Strong Sell', 'sell', now(), now())
+ON CONFLICT (normalized) DO NOTHING;
@@ -0,0 +1,22 @@
+DROP INDEX IF EXISTS stocks@idx_stocks_target_unparsed;
+DROP INDEX IF EXISTS stocks@idx_stocks_target_change_pct;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_change_pct;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_unparsed;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_currency;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_from_value;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_to_value;
+
+-- Vuelve a las columnas FLOAT8 de 0003_stock_filters
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_from_value FLOAT8;
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_to_value FLOAT8;
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_change_pct FLOAT8 AS (
+    CASE WHEN target_from_value > 0 THEN (target_to_value - target_from_value) / target_from_value * 100 END
+) STORED;
+
+UPDATE stocks SET
+    target_from_value = CASE WHEN target_from ~ '^\s*\$?[0-9][0-9,]*(\.[0-9]+)?\s*$'
+        THEN NULLIF(CAST(regexp_replace(target_from, '[$,\s]', '', 'g') AS FLOAT8), 0) END,
+    target_to_value = CASE WHEN target_to ~ '^\s*\$?[0-9][0-9,]*(\.[0-9]+)?\s*$'
+        THEN NULLIF(CAST(regexp_replace(target_to, '[$,\s]', '', 'g') AS FLOAT8), 0) END;
+
+CREATE INDEX IF NOT EXISTS idx_stocks_target_change_pct ON stocks (target_change_pct);
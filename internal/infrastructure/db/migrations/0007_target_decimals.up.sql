@@ -0,0 +1,51 @@
+-- Precios objetivo exactos con su moneda. Las columnas FLOAT8 de la
+-- migración 0003 se reemplazan por DECIMAL y se recalculan desde el texto.
+-- El SQL solo reconoce dólares ("$1,250.50", "US$1,250.50") y euros con coma
+-- decimal ("€1.250,50", "1.250,50 €"); el resto (otras monedas, códigos ISO,
+-- "€1,250.50") queda en target_unparsed y lo corrige la siguiente
+-- sincronización completa, que vuelve a interpretarlo con
+-- models.ParseTargetPrice.
+DROP INDEX IF EXISTS stocks@idx_stocks_target_change_pct;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_change_pct;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_from_value;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_to_value;
+
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_from_value DECIMAL(18,4);
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_to_value DECIMAL(18,4);
+-- Código ISO 4217 de los dos precios objetivo (vacío si no hay ninguno)
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_currency TEXT NOT NULL DEFAULT '';
+-- Marca los eventos con un precio objetivo que no se pudo interpretar
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_unparsed BOOL NOT NULL DEFAULT false;
+
+-- Formato en dólares: coma de miles y punto decimal
+UPDATE stocks SET
+    target_from_value = CASE WHEN target_from ~ '^\s*(US)?\$?\s*[0-9]{1,3}(,?[0-9]{3})*(\.[0-9]{1,4})?\s*$'
+        THEN NULLIF(CAST(regexp_replace(target_from, '[^0-9.]', '', 'g') AS DECIMAL(18,4)), 0) END,
+    target_to_value = CASE WHEN target_to ~ '^\s*(US)?\$?\s*[0-9]{1,3}(,?[0-9]{3})*(\.[0-9]{1,4})?\s*$'
+        THEN NULLIF(CAST(regexp_replace(target_to, '[^0-9.]', '', 'g') AS DECIMAL(18,4)), 0) END,
+    target_currency = 'USD';
+
+-- Formato en euros: punto de miles y coma decimal, con el símbolo antes o después
+UPDATE stocks SET
+    target_from_value = NULLIF(CAST(replace(regexp_replace(target_from, '[^0-9,]', '', 'g'), ',', '.') AS DECIMAL(18,4)), 0)
+WHERE target_from ~ '^\s*(€\s*[0-9]{1,3}(\.?[0-9]{3})*(,[0-9]{1,4})?|[0-9]{1,3}(\.?[0-9]{3})*(,[0-9]{1,4})?\s*€)\s*$';
+UPDATE stocks SET
+    target_to_value = NULLIF(CAST(replace(regexp_replace(target_to, '[^0-9,]', '', 'g'), ',', '.') AS DECIMAL(18,4)), 0)
+WHERE target_to ~ '^\s*(€\s*[0-9]{1,3}(\.?[0-9]{3})*(,[0-9]{1,4})?|[0-9]{1,3}(\.?[0-9]{3})*(,[0-9]{1,4})?\s*€)\s*$';
+UPDATE stocks SET target_currency = 'EUR' WHERE target_from ~ '€' OR target_to ~ '€';
+
+-- Dos monedas distintas en el mismo evento no se pueden comparar
+UPDATE stocks SET target_from_value = NULL, target_to_value = NULL
+WHERE (target_from ~ '€') <> (target_to ~ '€') AND target_from <> '' AND target_to <> '';
+UPDATE stocks SET target_currency = '' WHERE target_from_value IS NULL AND target_to_value IS NULL;
+
+-- Lo que ninguna regla reconoce queda marcado para revisarlo a mano
+UPDATE stocks SET target_unparsed = true
+WHERE (trim(target_from) <> '' AND target_from_value IS NULL)
+   OR (trim(target_to) <> '' AND target_to_value IS NULL);
+
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_change_pct FLOAT8 AS (
+    CASE WHEN target_from_value > 0 THEN CAST((target_to_value - target_from_value) / target_from_value * 100 AS FLOAT8) END
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_stocks_target_change_pct ON stocks (target_change_pct);
+CREATE INDEX IF NOT EXISTS idx_stocks_target_unparsed ON stocks (target_unparsed) WHERE target_unparsed;
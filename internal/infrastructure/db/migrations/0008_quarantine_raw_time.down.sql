@@ -0,0 +1 @@
+ALTER TABLE quarantined_stocks DROP COLUMN IF EXISTS raw_time;
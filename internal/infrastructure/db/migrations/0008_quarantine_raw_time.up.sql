@@ -0,0 +1,2 @@
+-- Fecha original de los registros en cuarentena que no se pudo interpretar
+ALTER TABLE quarantined_stocks ADD COLUMN IF NOT EXISTS raw_time TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+// BrokerageResolver traduce el nombre crudo de un brokerage al ID del
+// brokerage canónico; nil si no coincide con ninguno
+type BrokerageResolver interface {
+	ResolveBrokerage(raw string) *uuid.UUID
+}
+
+// resolvingStockRepository completa BrokerageID antes de cada upsert, así la
+// sincronización, la importación y la cuarentena resuelven igual
+type resolvingStockRepository struct {
+	StockRepository
+	resolver BrokerageResolver
+}
+
+// WithBrokerageResolution envuelve stocks para que cada upsert guarde el
+// brokerage canónico del evento
+func WithBrokerageResolution(stocks StockRepository, resolver BrokerageResolver) StockRepository {
+	return &resolvingStockRepository{StockRepository: stocks, resolver: resolver}
+}
+
+func (r *resolvingStockRepository) Upsert(stock models.Stock) (UpsertOutcome, error) {
+	stock.BrokerageID = r.resolver.ResolveBrokerage(stock.Brokerage)
+	return r.StockRepository.Upsert(stock)
+}
+
+type BrokerageRepository struct {
+	db *gorm.DB
+}
+
+func NewBrokerageRepository(db *gorm.DB) *BrokerageRepository {
+	return &BrokerageRepository{db: db}
+}
+
+// List devuelve todos los brokerages con sus alias, ordenados por nombre
+func (r *BrokerageRepository) List() ([]models.Brokerage, error) {
+	var brokerages []models.Brokerage
+	err := r.db.Preload("Aliases", func(db *gorm.DB) *gorm.DB {
+		return db.Order("alias")
+	}).Order("name").Find(&brokerages).Error
+	return brokerages, err
+}
+
+// FindByID devuelve gorm.ErrRecordNotFound si no existe
+func (r *BrokerageRepository) FindByID(id uuid.UUID) (*models.Brokerage, error) {
+	var b models.Brokerage
+	err := r.db.Preload("Aliases", func(db *gorm.DB) *gorm.DB {
+		return db.Order("alias")
+	}).Where("id = ?", id).Take(&b).Error
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Create guarda el brokerage y sus alias en una transacción
+func (r *BrokerageRepository) Create(b *models.Brokerage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Aliases").Create(b).Error; err != nil {
+			return err
+		}
+		return createAliases(tx, b)
+	})
+}
+
+// Save actualiza el brokerage y reemplaza todos sus alias
+func (r *BrokerageRepository) Save(b *models.Brokerage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Aliases").Save(b).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("brokerage_id = ?", b.ID).Delete(&models.BrokerageAlias{}).Error; err != nil {
+			return err
+		}
+		return createAliases(tx, b)
+	})
+}
+
+// Delete borra el brokerage; los alias se borran en cascada y los eventos
+// quedan con brokerage_id en NULL. Devuelve false si no existía.
+func (r *BrokerageRepository) Delete(id uuid.UUID) (bool, error) {
+	res := r.db.Where("id = ?", id).Delete(&models.Brokerage{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// ResolveStocks recalcula brokerage_id de los eventos guardados con resolver.
+// Se resuelve una vez por nombre distinto y solo se tocan las filas que
+// cambian; devuelve cuántas se actualizaron.
+func (r *BrokerageRepository) ResolveStocks(resolver BrokerageResolver) (int64, error) {
+	var names []string
+	if err := r.db.Model(&models.Stock{}).Distinct("brokerage").Pluck("brokerage", &names).Error; err != nil {
+		return 0, err
+	}
+
+	var updated int64
+	for _, name := range names {
+		id := resolver.ResolveBrokerage(name)
+		query := r.db.Model(&models.Stock{}).Where("brokerage = ?", name)
+		if id == nil {
+			query = query.Where("brokerage_id IS NOT NULL")
+		} else {
+			query = query.Where("brokerage_id IS DISTINCT FROM ?", *id)
+		}
+		res := query.Update("brokerage_id", id)
+		if res.Error != nil {
+			return updated, res.Error
+		}
+		updated += res.RowsAffected
+	}
+	return updated, nil
+}
+
+func createAliases(tx *gorm.DB, b *models.Brokerage) error {
+	if len(b.Aliases) == 0 {
+		return nil
+	}
+	for i := range b.Aliases {
+		b.Aliases[i].BrokerageID = b.ID
+	}
+	return tx.Create(&b.Aliases).Error
+}
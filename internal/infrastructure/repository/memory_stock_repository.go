@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"cmp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+// MemoryStockRepository guarda los eventos en memoria con la misma semántica
+// que la implementación GORM. Se usa en pruebas.
+type MemoryStockRepository struct {
+	mu     sync.RWMutex
+	stocks []models.Stock
+	// byKey indexa stocks por llave natural
+	byKey map[string]int
+}
+
+func NewMemoryStockRepository(stocks ...models.Stock) *MemoryStockRepository {
+	r := &MemoryStockRepository{byKey: make(map[string]int)}
+	for _, s := range stocks {
+		r.Upsert(s)
+	}
+	return r
+}
+
+func (r *MemoryStockRepository) Upsert(stock models.Stock) (UpsertOutcome, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Igual que la columna calculada target_change_pct de la base
+	stock.ParseTargets()
+	stock.TargetChangePct = stock.TargetChange()
+
+	now := time.Now()
+	key := stock.NaturalKey()
+	i, ok := r.byKey[key]
+	if !ok {
+		if stock.ID == uuid.Nil {
+			stock.ID = uuid.New()
+		}
+		stock.CreatedAt, stock.UpdatedAt = now, now
+		r.byKey[key] = len(r.stocks)
+		r.stocks = append(r.stocks, stock)
+		return UpsertInserted, nil
+	}
+
+	existing := &r.stocks[i]
+	if existing.SameAttributes(stock) {
+		return UpsertUnchanged, nil
+	}
+	existing.CopyAttributes(stock)
+	existing.TargetChangePct = existing.TargetChange()
+	existing.UpdatedAt = now
+	return UpsertUpdated, nil
+}
+
+func (r *MemoryStockRepository) FindByNaturalKey(stock models.Stock) (*models.Stock, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.byKey[stock.NaturalKey()]
+	if !ok {
+		return nil, nil
+	}
+	existing := r.stocks[i]
+	return &existing, nil
+}
+
+func (r *MemoryStockRepository) Search(q StockQuery) ([]models.Stock, int64, error) {
+	matches := r.filter(matchesSearch(q.Search, q.Filter))
+	sortStocks(matches, q.Sort)
+
+	total := int64(len(matches))
+	offset := (q.Page - 1) * q.PageSize
+	if offset >= len(matches) {
+		return []models.Stock{}, total, nil
+	}
+	end := offset + q.PageSize
+	if end > len(matches) {
+		end = len(matches)
+	}
+	return matches[offset:end], total, nil
+}
+
+func (r *MemoryStockRepository) SearchKeyset(q StockKeysetQuery) (*StockKeysetPage, error) {
+	matches := r.filter(matchesSearch(q.Search, q.Filter))
+
+	var total *int64
+	if q.WithTotal {
+		count := int64(len(matches))
+		total = &count
+	}
+
+	desc := q.scanDesc()
+	sort.Slice(matches, func(i, j int) bool {
+		if desc {
+			return compareKeyset(matches[i], matches[j]) > 0
+		}
+		return compareKeyset(matches[i], matches[j]) < 0
+	})
+
+	cursor := q.After
+	if q.Before != nil {
+		cursor = q.Before
+	}
+	stocks := make([]models.Stock, 0, q.Limit+1)
+	for _, s := range matches {
+		if len(stocks) > q.Limit {
+			break
+		}
+		if cursor != nil {
+			c := compareKeyset(s, models.Stock{Time: cursor.Time, ID: cursor.ID})
+			if (desc && c >= 0) || (!desc && c <= 0) {
+				continue
+			}
+		}
+		stocks = append(stocks, s)
+	}
+
+	page := keysetPage(stocks, q)
+	page.Total = total
+	return page, nil
+}
+
+func (r *MemoryStockRepository) ListForRecommendation() ([]models.Stock, error) {
+	return r.filter(func(models.Stock) bool { return true }), nil
+}
+
+func (r *MemoryStockRepository) History(ticker string) ([]models.Stock, error) {
+	history := r.filter(func(s models.Stock) bool { return s.Ticker == ticker })
+	sortNewestFirst(history)
+	return history, nil
+}
+
+func (r *MemoryStockRepository) Stats() (*StockStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := &StockStats{Total: int64(len(r.stocks)), ByProvider: make(map[string]int64)}
+	tickers := make(map[string]bool)
+	brokerages := make(map[string]bool)
+	for _, s := range r.stocks {
+		tickers[s.Ticker] = true
+		brokerages[s.Brokerage] = true
+		stats.ByProvider[s.Provider]++
+		if stats.Earliest == nil || s.Time.Before(*stats.Earliest) {
+			t := s.Time
+			stats.Earliest = &t
+		}
+		if stats.Latest == nil || s.Time.After(*stats.Latest) {
+			t := s.Time
+			stats.Latest = &t
+		}
+	}
+	stats.Tickers = int64(len(tickers))
+	stats.Brokerages = int64(len(brokerages))
+	return stats, nil
+}
+
+// matchesSearch replica filteredQuery
+func matchesSearch(search string, f StockFilter) func(models.Stock) bool {
+	search = strings.ToLower(search)
+	return func(s models.Stock) bool {
+		if !matchesFilter(s, f) {
+			return false
+		}
+		if search == "" {
+			return true
+		}
+		return strings.Contains(strings.ToLower(s.Ticker), search) ||
+			strings.Contains(strings.ToLower(s.Company), search) ||
+			strings.Contains(strings.ToLower(s.Brokerage), search)
+	}
+}
+
+// compareKeyset compara dos eventos por (time, id) como la base
+func compareKeyset(a, b models.Stock) int {
+	if c := a.Time.Compare(b.Time); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID.String(), b.ID.String())
+}
+
+// matchesFilter replica applyStockFilter
+func matchesFilter(s models.Stock, f StockFilter) bool {
+	if !containsFold(f.Brokerages, s.Brokerage) || !containsFold(f.RatingsTo, s.RatingTo) ||
+		!containsFold(f.RatingsFrom, s.RatingFrom) || !containsFold(f.Actions, s.Action) {
+		return false
+	}
+	if f.From != nil && s.Time.Before(*f.From) {
+		return false
+	}
+	if f.To != nil && s.Time.After(*f.To) {
+		return false
+	}
+	action := strings.ToLower(s.Action)
+	switch f.Direction {
+	case DirectionUpgrade:
+		if !strings.HasPrefix(action, "upgraded") {
+			return false
+		}
+	case DirectionDowngrade:
+		if !strings.HasPrefix(action, "downgraded") {
+			return false
+		}
+	}
+	if f.MinTargetChangePct != nil && (s.TargetChangePct == nil || *s.TargetChangePct < *f.MinTargetChangePct) {
+		return false
+	}
+	return true
+}
+
+// containsFold indica si value está en values; una lista vacía no filtra
+func containsFold(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter devuelve una copia de los eventos que cumplen match
+func (r *MemoryStockRepository) filter(match func(models.Stock) bool) []models.Stock {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]models.Stock, 0, len(r.stocks))
+	for _, s := range r.stocks {
+		if match(s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// sortStocks replica applyStockSort: nulls al final y desempate por id
+func sortStocks(stocks []models.Stock, fields []SortField) {
+	if len(fields) == 0 {
+		fields = defaultSort
+	}
+	sort.SliceStable(stocks, func(i, j int) bool {
+		for _, f := range fields {
+			c, decided := compareStockField(stocks[i], stocks[j], f.Field)
+			if decided {
+				// Los nulls van al final sin importar la dirección
+				return c < 0
+			}
+			if c != 0 {
+				if f.Desc {
+					return c > 0
+				}
+				return c < 0
+			}
+		}
+		return stocks[i].ID.String() < stocks[j].ID.String()
+	})
+}
+
+// compareStockField devuelve -1, 0 o 1; decided indica que uno de los dos
+// es null y el orden ya no depende de la dirección
+func compareStockField(a, b models.Stock, field string) (c int, decided bool) {
+	switch field {
+	case "time":
+		return a.Time.Compare(b.Time), false
+	case "ticker":
+		return strings.Compare(a.Ticker, b.Ticker), false
+	case "company":
+		return strings.Compare(a.Company, b.Company), false
+	case "brokerage":
+		return strings.Compare(a.Brokerage, b.Brokerage), false
+	case "target_to":
+		return compareNullable(a.TargetToValue, b.TargetToValue)
+	case "target_change":
+		return compareNullable(a.TargetChangePct, b.TargetChangePct)
+	}
+	return 0, false
+}
+
+func compareNullable[T cmp.Ordered](a, b *T) (int, bool) {
+	switch {
+	case a == nil && b == nil:
+		return 0, false
+	case a == nil:
+		return 1, true
+	case b == nil:
+		return -1, true
+	case *a < *b:
+		return -1, false
+	case *a > *b:
+		return 1, false
+	}
+	return 0, false
+}
+
+func sortNewestFirst(stocks []models.Stock) {
+	sort.SliceStable(stocks, func(i, j int) bool { return stocks[i].Time.After(stocks[j].Time) })
+}
@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+func day(d int) time.Time {
+	return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestMemoryStockRepository_Upsert(t *testing.T) {
+	repo := NewMemoryStockRepository()
+	stock := models.Stock{Ticker: "AAPL", Company: "Apple", Brokerage: "UBS", RatingFrom: "Hold", RatingTo: "Buy", Time: day(1), Provider: "primary"}
+
+	outcome, _ := repo.Upsert(stock)
+	if outcome != UpsertInserted {
+		t.Errorf("Expected insert, got %v", outcome)
+	}
+	outcome, _ = repo.Upsert(stock)
+	if outcome != UpsertUnchanged {
+		t.Errorf("Expected unchanged, got %v", outcome)
+	}
+
+	stock.RatingFrom = "Sell"
+	outcome, _ = repo.Upsert(stock)
+	if outcome != UpsertUpdated {
+		t.Errorf("Expected update, got %v", outcome)
+	}
+
+	existing, err := repo.FindByNaturalKey(stock)
+	if err != nil || existing == nil {
+		t.Fatalf("Expected stored stock, got %v, %v", existing, err)
+	}
+	if existing.RatingFrom != "Sell" || existing.ID.String() == "" {
+		t.Errorf("Unexpected stored stock: %+v", existing)
+	}
+
+	missing, _ := repo.FindByNaturalKey(models.Stock{Ticker: "MSFT", Time: day(1)})
+	if missing != nil {
+		t.Errorf("Expected nil for missing stock, got %+v", missing)
+	}
+}
+
+func TestMemoryStockRepository_UpsertTargetCurrency(t *testing.T) {
+	repo := NewMemoryStockRepository()
+	stock := models.Stock{Ticker: "SAP", Company: "SAP", Brokerage: "UBS", RatingTo: "Buy", TargetFrom: "$100.00", TargetTo: "€125,00", Time: day(1)}
+	repo.Upsert(stock)
+
+	existing, _ := repo.FindByNaturalKey(stock)
+	if existing.TargetFromValue != nil || existing.TargetToValue != nil || !existing.TargetUnparsed {
+		t.Fatalf("Expected mixed currencies to be cleared, got %+v", existing)
+	}
+
+	// El proveedor corrige target_from: los dos valores se completan
+	stock.TargetFrom = "€100,00"
+	if outcome, _ := repo.Upsert(stock); outcome != UpsertUpdated {
+		t.Fatalf("Expected update, got %v", outcome)
+	}
+	existing, _ = repo.FindByNaturalKey(stock)
+	if existing.TargetToValue == nil || *existing.TargetToValue != 1250000 || existing.TargetCurrency != "EUR" || existing.TargetUnparsed {
+		t.Errorf("Expected EUR pair after fix, got %+v", existing)
+	}
+	if existing.TargetChangePct == nil || *existing.TargetChangePct != 25 {
+		t.Errorf("Expected 25%% change, got %v", existing.TargetChangePct)
+	}
+
+	// Y al volver a mezclar monedas no queda un target_to_value viejo
+	stock.TargetFrom = "$100.00"
+	repo.Upsert(stock)
+	existing, _ = repo.FindByNaturalKey(stock)
+	if existing.TargetToValue != nil || existing.TargetCurrency != "" || existing.TargetChangePct != nil {
+		t.Errorf("Expected values to be cleared, got %+v", existing)
+	}
+}
+
+func TestMemoryStockRepository_SearchAndHistory(t *testing.T) {
+	repo := NewMemoryStockRepository(
+		models.Stock{Ticker: "AAPL", Company: "Apple", Brokerage: "UBS", Time: day(1)},
+		models.Stock{Ticker: "AAPL", Company: "Apple", Brokerage: "Citi", Time: day(3)},
+		models.Stock{Ticker: "MSFT", Company: "Microsoft", Brokerage: "UBS", Time: day(2)},
+	)
+
+	stocks, total, _ := repo.Search(StockQuery{Page: 1, PageSize: 2})
+	if total != 3 || len(stocks) != 2 {
+		t.Fatalf("Expected 2 of 3 stocks, got %d of %d", len(stocks), total)
+	}
+	if !stocks[0].Time.Equal(day(3)) || !stocks[1].Time.Equal(day(2)) {
+		t.Error("Expected stocks ordered newest first")
+	}
+
+	stocks, total, _ = repo.Search(StockQuery{Page: 1, PageSize: 10, Search: "ubs"})
+	if total != 2 || len(stocks) != 2 {
+		t.Errorf("Expected 2 stocks for brokerage search, got %d", total)
+	}
+
+	stocks, total, _ = repo.Search(StockQuery{Page: 5, PageSize: 10})
+	if total != 3 || len(stocks) != 0 {
+		t.Errorf("Expected empty page past the end, got %d stocks", len(stocks))
+	}
+
+	history, _ := repo.History("AAPL")
+	if len(history) != 2 || history[0].Brokerage != "Citi" {
+		t.Errorf("Unexpected history: %+v", history)
+	}
+}
+
+func TestMemoryStockRepository_Stats(t *testing.T) {
+	repo := NewMemoryStockRepository(
+		models.Stock{Ticker: "AAPL", Brokerage: "UBS", Time: day(1), Provider: "primary"},
+		models.Stock{Ticker: "AAPL", Brokerage: "Citi", Time: day(3), Provider: "primary"},
+		models.Stock{Ticker: "MSFT", Brokerage: "UBS", Time: day(2), Provider: "import"},
+	)
+
+	stats, err := repo.Stats()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.Total != 3 || stats.Tickers != 2 || stats.Brokerages != 2 {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+	if !stats.Earliest.Equal(day(1)) || !stats.Latest.Equal(day(3)) {
+		t.Errorf("Unexpected range: %v - %v", stats.Earliest, stats.Latest)
+	}
+	if stats.ByProvider["primary"] != 2 || stats.ByProvider["import"] != 1 {
+		t.Errorf("Unexpected provider counts: %v", stats.ByProvider)
+	}
+}
+
+func TestMemoryStockRepository_Filter(t *testing.T) {
+	repo := NewMemoryStockRepository(
+		models.Stock{Ticker: "AAPL", Brokerage: "UBS", Action: "upgraded by", RatingFrom: "Hold", RatingTo: "Buy", TargetFrom: "$100.00", TargetTo: "$130.00", Time: day(1)},
+		models.Stock{Ticker: "MSFT", Brokerage: "Citi", Action: "downgraded by", RatingFrom: "Buy", RatingTo: "Hold", TargetFrom: "$400.00", TargetTo: "$380.00", Time: day(2)},
+		models.Stock{Ticker: "TSLA", Brokerage: "ubs", Action: "target raised by", RatingFrom: "Buy", RatingTo: "Buy", TargetFrom: "$200.00", TargetTo: "$210.00", Time: day(3)},
+	)
+	minChange := 10.0
+	from, to := day(2), day(3)
+
+	testCases := []struct {
+		name     string
+		filter   StockFilter
+		expected []string
+	}{
+		{"Brokerage is case insensitive", StockFilter{Brokerages: []string{"UBS"}}, []string{"TSLA", "AAPL"}},
+		{"Several brokerages", StockFilter{Brokerages: []string{"ubs", "citi"}}, []string{"TSLA", "MSFT", "AAPL"}},
+		{"Rating to", StockFilter{RatingsTo: []string{"hold"}}, []string{"MSFT"}},
+		{"Rating from", StockFilter{RatingsFrom: []string{"Buy"}}, []string{"TSLA", "MSFT"}},
+		{"Action", StockFilter{Actions: []string{"Target Raised By"}}, []string{"TSLA"}},
+		{"Date range", StockFilter{From: &from, To: &to}, []string{"TSLA", "MSFT"}},
+		{"Upgrades only", StockFilter{Direction: DirectionUpgrade}, []string{"AAPL"}},
+		{"Downgrades only", StockFilter{Direction: DirectionDowngrade}, []string{"MSFT"}},
+		{"Min target change", StockFilter{MinTargetChangePct: &minChange}, []string{"AAPL"}},
+		{"Combined", StockFilter{Brokerages: []string{"UBS"}, RatingsFrom: []string{"Buy"}}, []string{"TSLA"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stocks, total, _ := repo.Search(StockQuery{Page: 1, PageSize: 10, Filter: tc.filter})
+			if int(total) != len(tc.expected) || len(stocks) != len(tc.expected) {
+				t.Fatalf("Expected %v, got %d stocks", tc.expected, total)
+			}
+			for i, ticker := range tc.expected {
+				if stocks[i].Ticker != ticker {
+					t.Errorf("Expected %v, got %s at %d", tc.expected, stocks[i].Ticker, i)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStockRepository_Sort(t *testing.T) {
+	repo := NewMemoryStockRepository(
+		models.Stock{Ticker: "AAPL", Company: "Apple", TargetFrom: "$100.00", TargetTo: "$130.00", Time: day(1)},
+		// Mismo time: el id fijo hace determinista el desempate
+		models.Stock{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), Ticker: "MSFT", Company: "Microsoft", TargetFrom: "$400.00", TargetTo: "$380.00", Time: day(2)},
+		models.Stock{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), Ticker: "TSLA", Company: "Tesla", TargetTo: "N/A", Time: day(2)},
+	)
+
+	testCases := []struct {
+		name     string
+		sort     []SortField
+		expected []string
+	}{
+		{"Default is newest first", nil, []string{"MSFT", "TSLA", "AAPL"}},
+		{"Ticker ascending", []SortField{{Field: "ticker"}}, []string{"AAPL", "MSFT", "TSLA"}},
+		{"Multi key", []SortField{{Field: "time", Desc: true}, {Field: "company", Desc: true}}, []string{"TSLA", "MSFT", "AAPL"}},
+		{"Numeric target, nulls last", []SortField{{Field: "target_to"}}, []string{"AAPL", "MSFT", "TSLA"}},
+		{"Numeric target desc, nulls last", []SortField{{Field: "target_to", Desc: true}}, []string{"MSFT", "AAPL", "TSLA"}},
+		{"Target change desc", []SortField{{Field: "target_change", Desc: true}}, []string{"AAPL", "MSFT", "TSLA"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stocks, _, _ := repo.Search(StockQuery{Page: 1, PageSize: 10, Sort: tc.sort})
+			for i, ticker := range tc.expected {
+				if stocks[i].Ticker != ticker {
+					t.Errorf("Expected %v, got %s at %d", tc.expected, stocks[i].Ticker, i)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStockRepository_SearchKeyset(t *testing.T) {
+	// Dos eventos con el mismo time: el id desempata
+	repo := NewMemoryStockRepository(
+		models.Stock{Ticker: "AAPL", Brokerage: "UBS", Time: day(1)},
+		models.Stock{Ticker: "MSFT", Brokerage: "UBS", Time: day(2)},
+		models.Stock{Ticker: "TSLA", Brokerage: "Citi", Time: day(2)},
+		models.Stock{Ticker: "NVDA", Brokerage: "UBS", Time: day(3)},
+	)
+
+	all, _ := repo.SearchKeyset(StockKeysetQuery{Limit: 10})
+	if len(all.Stocks) != 4 || all.HasMore || all.Total != nil {
+		t.Fatalf("Unexpected full page: %+v", all)
+	}
+
+	var seen []string
+	var after *StockCursor
+	for {
+		page, err := repo.SearchKeyset(StockKeysetQuery{Limit: 1, After: after})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		seen = append(seen, page.Stocks[0].Ticker)
+		if !page.HasMore {
+			break
+		}
+		last := page.Stocks[0]
+		after = &StockCursor{Time: last.Time, ID: last.ID}
+	}
+	for i, s := range all.Stocks {
+		if seen[i] != s.Ticker {
+			t.Errorf("Expected cursor walk %v to match full order at %d", seen, i)
+		}
+	}
+
+	// Before devuelve los anteriores en el mismo orden
+	last := all.Stocks[3]
+	before, _ := repo.SearchKeyset(StockKeysetQuery{Limit: 2, Before: &StockCursor{Time: last.Time, ID: last.ID}})
+	if len(before.Stocks) != 2 || before.Stocks[0].ID != all.Stocks[1].ID || before.Stocks[1].ID != all.Stocks[2].ID || !before.HasMore {
+		t.Errorf("Unexpected page before the last event: %+v", before)
+	}
+
+	filtered, _ := repo.SearchKeyset(StockKeysetQuery{Limit: 10, Asc: true, WithTotal: true, Filter: StockFilter{Brokerages: []string{"ubs"}}})
+	if filtered.Total == nil || *filtered.Total != 3 || filtered.Stocks[0].Ticker != "AAPL" {
+		t.Errorf("Unexpected filtered ascending page: %+v", filtered)
+	}
+}
+
+type staticResolver map[string]uuid.UUID
+
+func (r staticResolver) ResolveBrokerage(raw string) *uuid.UUID {
+	id, ok := r[raw]
+	if !ok {
+		return nil
+	}
+	return &id
+}
+
+func TestWithBrokerageResolution(t *testing.T) {
+	jpm := uuid.New()
+	memory := NewMemoryStockRepository()
+	repo := WithBrokerageResolution(memory, staticResolver{"J.P. Morgan": jpm})
+
+	repo.Upsert(models.Stock{Ticker: "AAPL", Brokerage: "J.P. Morgan", Time: day(1)})
+	repo.Upsert(models.Stock{Ticker: "MSFT", Brokerage: "Needham", Time: day(2)})
+
+	stocks, _ := memory.ListForRecommendation()
+	for _, st := range stocks {
+		switch st.Ticker {
+		case "AAPL":
+			if st.BrokerageID == nil || *st.BrokerageID != jpm {
+				t.Errorf("Expected AAPL resolved to %s, got %v", jpm, st.BrokerageID)
+			}
+		case "MSFT":
+			if st.BrokerageID != nil {
+				t.Errorf("Expected MSFT unresolved, got %v", st.BrokerageID)
+			}
+		}
+	}
+}
+
+type staticRatingResolver map[string]string
+
+func (r staticRatingResolver) ResolveRating(label string) string {
+	return r[label]
+}
+
+func TestWithRatingResolution(t *testing.T) {
+	memory := NewMemoryStockRepository()
+	repo := WithRatingResolution(memory, staticRatingResolver{"Peer Perform": models.RatingHold, "Sector Outperform": models.RatingBuy})
+
+	repo.Upsert(models.Stock{Ticker: "AAPL", RatingFrom: "Peer Perform", RatingTo: "Sector Outperform", Time: day(1)})
+
+	stocks, _ := memory.ListForRecommendation()
+	if len(stocks) != 1 || stocks[0].CanonicalRatingFrom != models.RatingHold || stocks[0].CanonicalRatingTo != models.RatingBuy {
+		t.Errorf("Expected canonical ratings to be stored, got %+v", stocks)
+	}
+}
+
+func TestSortUnmapped(t *testing.T) {
+	sorted := sortUnmapped(map[string]*UnmappedRating{
+		"b": {Normalized: "b", Events: 1},
+		"a": {Normalized: "a", Quarantined: 1},
+		"c": {Normalized: "c", Events: 2, Quarantined: 3},
+	})
+	if len(sorted) != 3 || sorted[0].Normalized != "c" || sorted[1].Normalized != "a" || sorted[2].Normalized != "b" {
+		t.Errorf("Expected most used first and then by label, got %+v", sorted)
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+// MemorySyncJobRepository guarda los jobs en memoria con la misma semántica
+// que la implementación GORM. Se usa en pruebas.
+type MemorySyncJobRepository struct {
+	mu   sync.Mutex
+	jobs map[uuid.UUID]models.SyncJob
+}
+
+func NewMemorySyncJobRepository() *MemorySyncJobRepository {
+	return &MemorySyncJobRepository{jobs: make(map[uuid.UUID]models.SyncJob)}
+}
+
+func (r *MemorySyncJobRepository) Create(job *models.SyncJob) error {
+	now := time.Now()
+	job.CreatedAt, job.UpdatedAt = now, now
+	return r.Save(job)
+}
+
+func (r *MemorySyncJobRepository) Save(job *models.SyncJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	job.UpdatedAt = time.Now()
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *MemorySyncJobRepository) FindByID(id uuid.UUID) (*models.SyncJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &job, nil
+}
+
+func (r *MemorySyncJobRepository) List(limit int) ([]models.SyncJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobs := make([]models.SyncJob, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+func (r *MemorySyncJobRepository) FailUnfinished(reason string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	now := time.Now()
+	for id, job := range r.jobs {
+		if job.Status == models.SyncJobPending || job.Status == models.SyncJobRunning {
+			job.Status, job.Error, job.FinishedAt = models.SyncJobFailed, reason, &now
+			r.jobs[id] = job
+			n++
+		}
+	}
+	return n, nil
+}
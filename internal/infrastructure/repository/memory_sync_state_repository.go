@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+// MemorySyncStateRepository guarda los checkpoints en memoria. Se usa en pruebas.
+type MemorySyncStateRepository struct {
+	mu     sync.Mutex
+	states map[string]models.SyncState
+}
+
+func NewMemorySyncStateRepository() *MemorySyncStateRepository {
+	return &MemorySyncStateRepository{states: make(map[string]models.SyncState)}
+}
+
+func (r *MemorySyncStateRepository) Get(name string) (*models.SyncState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, ok := r.states[name]
+	if !ok {
+		return &models.SyncState{Name: name}, nil
+	}
+	return &state, nil
+}
+
+func (r *MemorySyncStateRepository) Save(state *models.SyncState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[state.Name] = *state
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type QuarantineRepository struct {
+	db *gorm.DB
+}
+
+func NewQuarantineRepository(db *gorm.DB) *QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+// Add guarda el registro salvo que ya exista uno con la misma llave natural;
+// devuelve false si ya estaba en cuarentena (o fue resuelto antes)
+func (r *QuarantineRepository) Add(q *models.QuarantinedStock) (bool, error) {
+	res := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "natural_key"}},
+		DoNothing: true,
+	}).Create(q)
+	return res.RowsAffected > 0, res.Error
+}
+
+// List pagina los registros, opcionalmente filtrados por estado
+func (r *QuarantineRepository) List(status models.QuarantineStatus, page, pageSize int) ([]models.QuarantinedStock, int64, error) {
+	var items []models.QuarantinedStock
+	var total int64
+
+	query := r.db.Model(&models.QuarantinedStock{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("created_at DESC").Limit(pageSize).Offset(offset).Find(&items).Error
+	return items, total, err
+}
+
+// FindByID devuelve gorm.ErrRecordNotFound si no existe
+func (r *QuarantineRepository) FindByID(id uuid.UUID) (*models.QuarantinedStock, error) {
+	var q models.QuarantinedStock
+	if err := r.db.Where("id = ?", id).Take(&q).Error; err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (r *QuarantineRepository) Save(q *models.QuarantinedStock) error {
+	return r.db.Save(q).Error
+}
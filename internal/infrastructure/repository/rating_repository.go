@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"sort"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+// RatingResolver traduce un texto de rating a su nivel canónico; vacío si
+// no está en el vocabulario
+type RatingResolver interface {
+	ResolveRating(label string) string
+}
+
+// ratingStockRepository completa los ratings canónicos antes de cada upsert
+type ratingStockRepository struct {
+	StockRepository
+	resolver RatingResolver
+}
+
+// WithRatingResolution envuelve stocks para que cada upsert guarde los
+// ratings canónicos del evento
+func WithRatingResolution(stocks StockRepository, resolver RatingResolver) StockRepository {
+	return &ratingStockRepository{StockRepository: stocks, resolver: resolver}
+}
+
+func (r *ratingStockRepository) Upsert(stock models.Stock) (UpsertOutcome, error) {
+	stock.CanonicalRatingFrom = r.resolver.ResolveRating(stock.RatingFrom)
+	stock.CanonicalRatingTo = r.resolver.ResolveRating(stock.RatingTo)
+	return r.StockRepository.Upsert(stock)
+}
+
+// UnmappedRating es un texto de rating que no está en el vocabulario
+type UnmappedRating struct {
+	Label      string `json:"label"`
+	Normalized string `json:"normalized"`
+	// Events son los eventos guardados que lo usan como rating_from o rating_to
+	Events int64 `json:"events"`
+	// Quarantined son los registros pendientes en cuarentena que lo usan
+	Quarantined int64 `json:"quarantined"`
+}
+
+type RatingRepository struct {
+	db *gorm.DB
+}
+
+func NewRatingRepository(db *gorm.DB) *RatingRepository {
+	return &RatingRepository{db: db}
+}
+
+// List devuelve el vocabulario ordenado por texto normalizado
+func (r *RatingRepository) List() ([]models.RatingMapping, error) {
+	var mappings []models.RatingMapping
+	err := r.db.Order("normalized").Find(&mappings).Error
+	return mappings, err
+}
+
+// Save crea o reemplaza la traducción de m.Normalized
+func (r *RatingRepository) Save(m *models.RatingMapping) error {
+	return r.db.Save(m).Error
+}
+
+// Delete borra una traducción; devuelve false si no existía
+func (r *RatingRepository) Delete(normalized string) (bool, error) {
+	res := r.db.Where("normalized = ?", normalized).Delete(&models.RatingMapping{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// ResolveStocks recalcula los ratings canónicos de los eventos guardados con
+// resolver. Se resuelve una vez por texto distinto y solo se tocan las filas
+// que cambian; devuelve cuántas se actualizaron.
+func (r *RatingRepository) ResolveStocks(resolver RatingResolver) (int64, error) {
+	var updated int64
+	for _, column := range []string{"rating_from", "rating_to"} {
+		var labels []string
+		if err := r.db.Model(&models.Stock{}).Distinct(column).Pluck(column, &labels).Error; err != nil {
+			return updated, err
+		}
+		for _, label := range labels {
+			rating := resolver.ResolveRating(label)
+			res := r.db.Model(&models.Stock{}).
+				Where(column+" = ? AND canonical_"+column+" <> ?", label, rating).
+				Update("canonical_"+column, rating)
+			if res.Error != nil {
+				return updated, res.Error
+			}
+			updated += res.RowsAffected
+		}
+	}
+	return updated, nil
+}
+
+// Unmapped lista los textos de rating de los eventos guardados y de la
+// cuarentena pendiente que no están en el vocabulario, los más usados primero
+func (r *RatingRepository) Unmapped(resolver RatingResolver) ([]UnmappedRating, error) {
+	byKey := make(map[string]*UnmappedRating)
+	count := func(label string, n int64, quarantined bool) {
+		key := models.NormalizeRatingLabel(label)
+		if key == "" || resolver.ResolveRating(label) != "" {
+			return
+		}
+		u, ok := byKey[key]
+		if !ok {
+			u = &UnmappedRating{Label: label, Normalized: key}
+			byKey[key] = u
+		}
+		if quarantined {
+			u.Quarantined += n
+		} else {
+			u.Events += n
+		}
+	}
+
+	type labelCount struct {
+		Label string
+		N     int64
+	}
+	for _, column := range []string{"rating_from", "rating_to"} {
+		var stored []labelCount
+		err := r.db.Model(&models.Stock{}).
+			Select(column + " AS label, COUNT(*) AS n").
+			Where("canonical_" + column + " = ''").
+			Group(column).Scan(&stored).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range stored {
+			count(c.Label, c.N, false)
+		}
+
+		var quarantined []labelCount
+		err = r.db.Model(&models.QuarantinedStock{}).
+			Select(column+" AS label, COUNT(*) AS n").
+			Where("status = ?", models.QuarantinePending).
+			Group(column).Scan(&quarantined).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range quarantined {
+			count(c.Label, c.N, true)
+		}
+	}
+
+	return sortUnmapped(byKey), nil
+}
+
+// sortUnmapped ordena por uso total descendente y luego por texto
+func sortUnmapped(byKey map[string]*UnmappedRating) []UnmappedRating {
+	result := make([]UnmappedRating, 0, len(byKey))
+	for _, u := range byKey {
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ti, tj := result[i].Events+result[i].Quarantined, result[j].Events+result[j].Quarantined
+		if ti != tj {
+			return ti > tj
+		}
+		return result[i].Normalized < result[j].Normalized
+	})
+	return result
+}
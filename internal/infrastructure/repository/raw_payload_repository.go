@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+type RawPayloadRepository struct {
+	db *gorm.DB
+}
+
+func NewRawPayloadRepository(db *gorm.DB) *RawPayloadRepository {
+	return &RawPayloadRepository{db: db}
+}
+
+func (r *RawPayloadRepository) Create(p *models.RawPayload) error {
+	return r.db.Create(p).Error
+}
+
+// RawPayloadFilter selecciona páginas archivadas; los campos vacíos no filtran
+type RawPayloadFilter struct {
+	Provider   string
+	Since      time.Time
+	Until      time.Time
+	StatusCode int
+}
+
+// ListIDs devuelve los IDs en el orden en que se trajeron, sin cargar los bodies
+func (r *RawPayloadRepository) ListIDs(filter RawPayloadFilter) ([]uuid.UUID, error) {
+	query := r.db.Model(&models.RawPayload{})
+	if filter.Provider != "" {
+		query = query.Where("provider = ?", filter.Provider)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("fetched_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("fetched_at < ?", filter.Until)
+	}
+	if filter.StatusCode != 0 {
+		query = query.Where("status_code = ?", filter.StatusCode)
+	}
+
+	var ids []uuid.UUID
+	err := query.Order("fetched_at ASC").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// FindByID devuelve gorm.ErrRecordNotFound si no existe
+func (r *RawPayloadRepository) FindByID(id uuid.UUID) (*models.RawPayload, error) {
+	var p models.RawPayload
+	if err := r.db.Where("id = ?", id).Take(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
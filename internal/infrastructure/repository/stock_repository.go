@@ -1,18 +1,416 @@
 package repository
 
 import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type StockRepository struct {
+// UpsertOutcome describe qué pasó con un registro al hacer upsert
+type UpsertOutcome int
+
+const (
+	UpsertInserted UpsertOutcome = iota
+	UpsertUpdated
+	UpsertUnchanged
+)
+
+// Direcciones de cambio de rating para StockFilter.Direction
+const (
+	DirectionUpgrade   = "upgrade"
+	DirectionDowngrade = "downgrade"
+)
+
+// StockFilter son los filtros estructurados del listado; los campos vacíos
+// no filtran y todos se combinan con AND. Los textos se comparan sin
+// distinguir mayúsculas.
+type StockFilter struct {
+	Brokerages  []string
+	RatingsTo   []string
+	RatingsFrom []string
+	Actions     []string
+	// From y To limitan Time (ambos inclusive)
+	From *time.Time
+	To   *time.Time
+	// Direction deja solo upgrades o downgrades según la acción
+	Direction string
+	// MinTargetChangePct es la variación mínima del precio objetivo en %
+	MinTargetChangePct *float64
+}
+
+// SortField es un criterio de orden del listado
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// sortColumns son los campos por los que se puede ordenar y su columna.
+// Los nullable (precios no interpretables) van siempre al final.
+var sortColumns = map[string]struct {
+	column   string
+	nullable bool
+}{
+	"time":          {"time", false},
+	"ticker":        {"ticker", false},
+	"company":       {"company", false},
+	"brokerage":     {"brokerage", false},
+	"target_to":     {"target_to_value", true},
+	"target_change": {"target_change_pct", true},
+}
+
+// SortableFields lista los campos aceptados en StockQuery.Sort
+func SortableFields() []string {
+	fields := make([]string, 0, len(sortColumns))
+	for field := range sortColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// IsSortable indica si el campo está en la lista blanca de orden
+func IsSortable(field string) bool {
+	_, ok := sortColumns[field]
+	return ok
+}
+
+// defaultSort es el orden del listado si no se pide otro
+var defaultSort = []SortField{{Field: "time", Desc: true}}
+
+// StockQuery describe una búsqueda paginada de eventos
+type StockQuery struct {
+	Page     int
+	PageSize int
+	// Search filtra por ticker, compañía o brokerage (sin distinguir mayúsculas)
+	Search string
+	Filter StockFilter
+	// Sort ordena por varios campos en orden de prioridad; vacío es time DESC
+	Sort []SortField
+}
+
+// StockStats resume los eventos guardados
+type StockStats struct {
+	Total      int64            `json:"total"`
+	Tickers    int64            `json:"tickers"`
+	Brokerages int64            `json:"brokerages"`
+	Earliest   *time.Time       `json:"earliest"`
+	Latest     *time.Time       `json:"latest"`
+	ByProvider map[string]int64 `json:"by_provider"`
+}
+
+// StockCursor es la posición de un evento en el orden (time, id)
+type StockCursor struct {
+	Time time.Time
+	ID   uuid.UUID
+}
+
+// StockKeysetQuery pide una página por cursor. El orden es siempre
+// (time, id), descendente salvo que Asc sea true.
+type StockKeysetQuery struct {
+	Limit  int
+	Search string
+	Filter StockFilter
+	Asc    bool
+	// After trae los eventos que siguen a esa posición; Before los que la
+	// preceden. Sin ninguno de los dos se trae la primera página.
+	After  *StockCursor
+	Before *StockCursor
+	// WithTotal agrega el total filtrado, que cuesta un COUNT extra
+	WithTotal bool
+}
+
+// StockKeysetPage es una página por cursor, siempre en el orden pedido
+type StockKeysetPage struct {
+	Stocks []models.Stock
+	// HasMore indica que quedan eventos en la dirección recorrida
+	// (después de la página con After, antes de ella con Before)
+	HasMore bool
+	Total   *int64
+}
+
+// scanDesc indica el sentido en que se recorre el índice: ir hacia atrás
+// invierte el orden pedido
+func (q StockKeysetQuery) scanDesc() bool {
+	return !q.Asc != (q.Before != nil)
+}
+
+// keysetPage recorta la fila extra que indica si hay más y devuelve los
+// eventos en el orden pedido
+func keysetPage(stocks []models.Stock, q StockKeysetQuery) *StockKeysetPage {
+	page := &StockKeysetPage{Stocks: stocks}
+	if len(stocks) > q.Limit {
+		page.HasMore = true
+		page.Stocks = stocks[:q.Limit]
+	}
+	if q.Before != nil {
+		for i, j := 0, len(page.Stocks)-1; i < j; i, j = i+1, j-1 {
+			page.Stocks[i], page.Stocks[j] = page.Stocks[j], page.Stocks[i]
+		}
+	}
+	return page
+}
+
+// StockRepository es el acceso a los eventos de rating. Tiene una
+// implementación GORM y una en memoria para pruebas.
+type StockRepository interface {
+	// Upsert inserta el evento si su llave natural no existe, o actualiza
+	// los campos mutables si ya existe con otros valores
+	Upsert(stock models.Stock) (UpsertOutcome, error)
+	// FindByNaturalKey devuelve el evento con la misma llave natural, o nil
+	FindByNaturalKey(stock models.Stock) (*models.Stock, error)
+	// Search pagina con offset en el orden de query.Sort y devuelve el total filtrado
+	Search(query StockQuery) ([]models.Stock, int64, error)
+	// SearchKeyset pagina por cursor sobre (time, id), sin offset
+	SearchKeyset(query StockKeysetQuery) (*StockKeysetPage, error)
+	// ListForRecommendation devuelve los eventos que usa el recomendador
+	ListForRecommendation() ([]models.Stock, error)
+	// History devuelve los eventos de un ticker del más nuevo al más viejo
+	History(ticker string) ([]models.Stock, error)
+	Stats() (*StockStats, error)
+}
+
+type gormStockRepository struct {
 	db *gorm.DB
 }
 
-func NewStockRepository(db *gorm.DB) *StockRepository {
-	return &StockRepository{db: db}
+func NewStockRepository(db *gorm.DB) StockRepository {
+	return &gormStockRepository{db: db}
+}
+
+func (r *gormStockRepository) Upsert(stock models.Stock) (UpsertOutcome, error) {
+	stock.ParseTargets()
+	existing, err := r.FindByNaturalKey(stock)
+	if err != nil {
+		return 0, err
+	}
+
+	if existing == nil {
+		// ON CONFLICT DO NOTHING cubre la carrera con otra sincronización concurrente
+		res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&stock)
+		if res.Error != nil {
+			return 0, res.Error
+		}
+		if res.RowsAffected == 0 {
+			return UpsertUnchanged, nil
+		}
+		return UpsertInserted, nil
+	}
+
+	if existing.SameAttributes(stock) {
+		return UpsertUnchanged, nil
+	}
+
+	existing.CopyAttributes(stock)
+	if err := r.db.Model(existing).Select("company", "rating_from", "target_from", "target_from_value", "target_to_value", "target_currency", "target_unparsed", "provider", "brokerage_id", "canonical_rating_from", "canonical_rating_to").Updates(existing).Error; err != nil {
+		return 0, err
+	}
+	return UpsertUpdated, nil
+}
+
+func (r *gormStockRepository) FindByNaturalKey(stock models.Stock) (*models.Stock, error) {
+	var existing models.Stock
+	err := r.db.Model(&models.Stock{}).Where(
+		"ticker = ? AND brokerage = ? AND time = ? AND action = ? AND rating_to = ? AND target_to = ?",
+		stock.Ticker, stock.Brokerage, stock.Time, stock.Action, stock.RatingTo, stock.TargetTo,
+	).Take(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (r *gormStockRepository) Search(q StockQuery) ([]models.Stock, int64, error) {
+	var stocks []models.Stock
+	var total int64
+
+	query := r.filteredQuery(q.Search, q.Filter)
+
+	// contar el total de registros filtrados
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// aplicar paginación + ordenamiento
+	offset := (q.Page - 1) * q.PageSize
+	if err := applyStockSort(query, q.Sort).Limit(q.PageSize).Offset(offset).Find(&stocks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return stocks, total, nil
+}
+
+func (r *gormStockRepository) SearchKeyset(q StockKeysetQuery) (*StockKeysetPage, error) {
+	var total *int64
+	if q.WithTotal {
+		var count int64
+		if err := r.filteredQuery(q.Search, q.Filter).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		total = &count
+	}
+
+	query := r.filteredQuery(q.Search, q.Filter)
+
+	// Comparación de tuplas para que Cockroach use idx_stocks_time_id
+	dir, op := "ASC", ">"
+	if q.scanDesc() {
+		dir, op = "DESC", "<"
+	}
+	cursor := q.After
+	if q.Before != nil {
+		cursor = q.Before
+	}
+	if cursor != nil {
+		query = query.Where("(time, id) "+op+" (?, ?)", cursor.Time, cursor.ID)
+	}
+
+	// Se pide una fila de más para saber si quedan eventos
+	var stocks []models.Stock
+	if err := query.Order("time " + dir).Order("id " + dir).Limit(q.Limit + 1).Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+
+	page := keysetPage(stocks, q)
+	page.Total = total
+	return page, nil
+}
+
+// filteredQuery aplica el search libre y los filtros estructurados
+func (r *gormStockRepository) filteredQuery(search string, f StockFilter) *gorm.DB {
+	query := r.db.Model(&models.Stock{})
+
+	// Si el usuario pasó un search, filtramos
+	if search != "" {
+		like := "%" + strings.ToLower(search) + "%"
+		query = query.Where(
+			r.db.Where("LOWER(Ticker) LIKE ?", like).
+				Or("LOWER(Company) LIKE ?", like).
+				Or("LOWER(Brokerage) LIKE ?", like),
+		)
+	}
+
+	return applyStockFilter(query, f)
+}
+
+// applyStockFilter usa las mismas expresiones que los índices de la migración 0003
+func applyStockFilter(query *gorm.DB, f StockFilter) *gorm.DB {
+	if len(f.Brokerages) > 0 {
+		query = query.Where("LOWER(brokerage) IN ?", lowerAll(f.Brokerages))
+	}
+	if len(f.RatingsTo) > 0 {
+		query = query.Where("LOWER(rating_to) IN ?", lowerAll(f.RatingsTo))
+	}
+	if len(f.RatingsFrom) > 0 {
+		query = query.Where("LOWER(rating_from) IN ?", lowerAll(f.RatingsFrom))
+	}
+	if len(f.Actions) > 0 {
+		query = query.Where("LOWER(action) IN ?", lowerAll(f.Actions))
+	}
+	if f.From != nil {
+		query = query.Where("time >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("time <= ?", *f.To)
+	}
+	switch f.Direction {
+	case DirectionUpgrade:
+		query = query.Where("LOWER(action) LIKE ?", "upgraded%")
+	case DirectionDowngrade:
+		query = query.Where("LOWER(action) LIKE ?", "downgraded%")
+	}
+	if f.MinTargetChangePct != nil {
+		query = query.Where("target_change_pct >= ?", *f.MinTargetChangePct)
+	}
+	return query
+}
+
+// applyStockSort agrega el ORDER BY; el id desempata para que el orden sea estable
+func applyStockSort(query *gorm.DB, fields []SortField) *gorm.DB {
+	if len(fields) == 0 {
+		fields = defaultSort
+	}
+	for _, f := range fields {
+		col, ok := sortColumns[f.Field]
+		if !ok {
+			continue
+		}
+		order := col.column + " ASC"
+		if f.Desc {
+			order = col.column + " DESC"
+		}
+		if col.nullable {
+			order += " NULLS LAST"
+		}
+		query = query.Order(order)
+	}
+	return query.Order("id ASC")
 }
 
-func (r *StockRepository) SaveStocks(stocks []models.Stock) error {
-	return r.db.Create(&stocks).Error
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, v := range values {
+		lowered[i] = strings.ToLower(v)
+	}
+	return lowered
+}
+
+func (r *gormStockRepository) ListForRecommendation() ([]models.Stock, error) {
+	var stocks []models.Stock
+	if err := r.db.Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+func (r *gormStockRepository) History(ticker string) ([]models.Stock, error) {
+	var stocks []models.Stock
+	err := r.db.Where("ticker = ?", ticker).Order("time DESC").Find(&stocks).Error
+	return stocks, err
+}
+
+func (r *gormStockRepository) Stats() (*StockStats, error) {
+	var row struct {
+		Total      int64
+		Tickers    int64
+		Brokerages int64
+		Earliest   *time.Time
+		Latest     *time.Time
+	}
+	err := r.db.Model(&models.Stock{}).Select(
+		"COUNT(*) AS total, COUNT(DISTINCT ticker) AS tickers, COUNT(DISTINCT brokerage) AS brokerages, MIN(time) AS earliest, MAX(time) AS latest",
+	).Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []struct {
+		Provider string
+		Count    int64
+	}
+	err = r.db.Model(&models.Stock{}).Select("provider, COUNT(*) AS count").Group("provider").Scan(&providers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &StockStats{
+		Total:      row.Total,
+		Tickers:    row.Tickers,
+		Brokerages: row.Brokerages,
+		Earliest:   row.Earliest,
+		Latest:     row.Latest,
+		ByProvider: make(map[string]int64, len(providers)),
+	}
+	for _, p := range providers {
+		stats.ByProvider[p.Provider] = p.Count
+	}
+	return stats, nil
 }
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+// SyncJobRepository persiste los jobs de sincronización y su progreso. Tiene
+// una implementación GORM y una en memoria para pruebas.
+type SyncJobRepository interface {
+	Create(job *models.SyncJob) error
+	Save(job *models.SyncJob) error
+	// FindByID devuelve gorm.ErrRecordNotFound si el job no existe
+	FindByID(id uuid.UUID) (*models.SyncJob, error)
+	// List devuelve los jobs más recientes primero
+	List(limit int) ([]models.SyncJob, error)
+	// FailUnfinished marca como fallidos los jobs que quedaron abiertos,
+	// por ejemplo porque el proceso se reinició a mitad de la sincronización
+	FailUnfinished(reason string) (int64, error)
+}
+
+type gormSyncJobRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncJobRepository(db *gorm.DB) SyncJobRepository {
+	return &gormSyncJobRepository{db: db}
+}
+
+func (r *gormSyncJobRepository) Create(job *models.SyncJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *gormSyncJobRepository) Save(job *models.SyncJob) error {
+	return r.db.Save(job).Error
+}
+
+func (r *gormSyncJobRepository) FindByID(id uuid.UUID) (*models.SyncJob, error) {
+	var job models.SyncJob
+	if err := r.db.Where("id = ?", id).Take(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *gormSyncJobRepository) List(limit int) ([]models.SyncJob, error) {
+	var jobs []models.SyncJob
+	err := r.db.Order("created_at DESC").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *gormSyncJobRepository) FailUnfinished(reason string) (int64, error) {
+	res := r.db.Model(&models.SyncJob{}).
+		Where("status IN ?", []models.SyncJobStatus{models.SyncJobPending, models.SyncJobRunning}).
+		Updates(map[string]interface{}{
+			"status":      models.SyncJobFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"gorm.io/gorm"
+)
+
+// SyncStateRepository guarda el checkpoint de sincronización de cada
+// proveedor. Tiene una implementación GORM y una en memoria para pruebas.
+type SyncStateRepository interface {
+	// Get devuelve el checkpoint con ese nombre, o uno vacío si aún no existe
+	Get(name string) (*models.SyncState, error)
+	Save(state *models.SyncState) error
+}
+
+type gormSyncStateRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncStateRepository(db *gorm.DB) SyncStateRepository {
+	return &gormSyncStateRepository{db: db}
+}
+
+func (r *gormSyncStateRepository) Get(name string) (*models.SyncState, error) {
+	var state models.SyncState
+	err := r.db.Where("name = ?", name).Take(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.SyncState{Name: name}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *gormSyncStateRepository) Save(state *models.SyncState) error {
+	return r.db.Save(state).Error
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"abc * * * *",
+		"@every 10s",
+	}
+
+	for _, expr := range invalid {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseCron(expr); err == nil {
+				t.Errorf("Expected error for %q", expr)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Viernes 1 de agosto de 2025, 10:07:30
+	base := time.Date(2025, 8, 1, 10, 7, 30, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{"Every minute", "* * * * *", time.Date(2025, 8, 1, 10, 8, 0, 0, time.UTC)},
+		{"Every 15 minutes", "*/15 * * * *", time.Date(2025, 8, 1, 10, 15, 0, 0, time.UTC)},
+		{"Hourly macro", "@hourly", time.Date(2025, 8, 1, 11, 0, 0, 0, time.UTC)},
+		{"Daily at 3am", "0 3 * * *", time.Date(2025, 8, 2, 3, 0, 0, 0, time.UTC)},
+		{"List of hours", "30 6,18 * * *", time.Date(2025, 8, 1, 18, 30, 0, 0, time.UTC)},
+		{"Weekdays range", "0 9 * * mon-fri", time.Date(2025, 8, 4, 9, 0, 0, 0, time.UTC)},
+		{"Sunday as 7", "0 0 * * 7", time.Date(2025, 8, 3, 0, 0, 0, 0, time.UTC)},
+		{"Monthly", "@monthly", time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"Named month", "0 0 1 jan *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Day of month or weekday", "0 12 15 * sat", time.Date(2025, 8, 2, 12, 0, 0, 0, time.UTC)},
+		{"Every duration", "@every 30m", time.Date(2025, 8, 1, 10, 37, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := ParseCron(tc.expr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			next := schedule.Next(base)
+			if !next.Equal(tc.expected) {
+				t.Errorf("Next(%s) for %q = %s; expected %s", base, tc.expr, next, tc.expected)
+			}
+		})
+	}
+}
+
+func TestScheduleNext_Feb30NeverMatches(t *testing.T) {
+	schedule, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if next := schedule.Next(time.Now()); !next.IsZero() {
+		t.Errorf("Expected no next run for Feb 30, got %s", next)
+	}
+}
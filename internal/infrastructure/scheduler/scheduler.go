@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrSkipped lo puede devolver una tarea para indicar que no se ejecutó
+// (por ejemplo porque ya había una sincronización manual corriendo)
+var ErrSkipped = errors.New("ejecución omitida")
+
+// Task es el trabajo que se ejecuta en cada disparo del cron
+type Task func(ctx context.Context) error
+
+// EntryStatus es el estado público de una programación
+type EntryStatus struct {
+	Name       string     `json:"name"`
+	Expression string     `json:"expression"`
+	Running    bool       `json:"running"`
+	LastRunAt  *time.Time `json:"last_run_at"`
+	LastStatus string     `json:"last_status,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+	NextRunAt  *time.Time `json:"next_run_at"`
+	RunCount   int        `json:"run_count"`
+	SkipCount  int        `json:"skip_count"`
+}
+
+type entry struct {
+	status   EntryStatus
+	schedule *Schedule
+	task     Task
+}
+
+// Scheduler dispara tareas según expresiones cron dentro del proceso.
+// Nunca ejecuta dos tareas a la vez: si un disparo llega mientras otra
+// tarea sigue corriendo, se omite.
+type Scheduler struct {
+	mu      sync.Mutex
+	entries []*entry
+	jitter  time.Duration
+	busy    bool
+	now     func() time.Time
+}
+
+// New crea un scheduler; jitter agrega un retraso aleatorio en [0, jitter)
+// a cada disparo para no golpear al proveedor siempre en el mismo segundo
+func New(jitter time.Duration) *Scheduler {
+	return &Scheduler{jitter: jitter, now: time.Now}
+}
+
+// Add registra una tarea con su expresión cron
+func (s *Scheduler) Add(name, expression string, task Task) error {
+	schedule, err := ParseCron(expression)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, &entry{
+		status:   EntryStatus{Name: name, Expression: expression},
+		schedule: schedule,
+		task:     task,
+	})
+	return nil
+}
+
+// Start lanza una goroutine por programación hasta que se cancele ctx
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		go s.loop(ctx, e)
+		log.Printf("⏱️ Programación %q (%s) registrada", e.status.Name, e.status.Expression)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	for {
+		s.mu.Lock()
+		next := e.schedule.Next(s.now())
+		if next.IsZero() {
+			e.status.NextRunAt = nil
+			s.mu.Unlock()
+			log.Printf("⚠️ La programación %q no tiene próximas ejecuciones", e.status.Name)
+			return
+		}
+		e.status.NextRunAt = &next
+		s.mu.Unlock()
+
+		wait := next.Sub(s.now()) + s.randomJitter()
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.run(ctx, e)
+	}
+}
+
+// run ejecuta la tarea salvo que otra esté corriendo
+func (s *Scheduler) run(ctx context.Context, e *entry) {
+	s.mu.Lock()
+	startedAt := s.now()
+	e.status.LastRunAt = &startedAt
+	if s.busy {
+		e.status.SkipCount++
+		e.status.LastStatus = "skipped"
+		e.status.LastError = "otra tarea programada sigue en curso"
+		s.mu.Unlock()
+		log.Printf("⏭️ Programación %q omitida: otra tarea sigue en curso", e.status.Name)
+		return
+	}
+	s.busy = true
+	e.status.Running = true
+	s.mu.Unlock()
+
+	err := e.task(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.busy = false
+	e.status.Running = false
+	e.status.LastError = ""
+
+	switch {
+	case errors.Is(err, ErrSkipped):
+		e.status.SkipCount++
+		e.status.LastStatus = "skipped"
+		e.status.LastError = err.Error()
+	case err != nil:
+		e.status.RunCount++
+		e.status.LastStatus = "failed"
+		e.status.LastError = err.Error()
+		log.Printf("❌ Programación %q falló: %v", e.status.Name, err)
+	default:
+		e.status.RunCount++
+		e.status.LastStatus = "succeeded"
+	}
+}
+
+func (s *Scheduler) randomJitter() time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(s.jitter)))
+}
+
+// Status devuelve una copia del estado de todas las programaciones
+func (s *Scheduler) Status() []EntryStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]EntryStatus, 0, len(s.entries))
+	for _, e := range s.entries {
+		out = append(out, e.status)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
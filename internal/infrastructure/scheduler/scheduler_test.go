@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSchedulerRun_SkipsWhenBusy(t *testing.T) {
+	s := New(0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	if err := s.Add("slow", "* * * * *", func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.Add("other", "* * * * *", func(ctx context.Context) error {
+		t.Error("Task should not run while another one is in progress")
+		return nil
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.run(context.Background(), s.entries[0])
+		close(done)
+	}()
+	<-started
+
+	// El segundo disparo llega mientras el primero sigue corriendo
+	s.run(context.Background(), s.entries[1])
+	close(release)
+	<-done
+
+	status := s.Status()
+	if status[0].Name != "other" || status[0].LastStatus != "skipped" || status[0].SkipCount != 1 {
+		t.Errorf("Expected 'other' to be skipped, got %+v", status[0])
+	}
+	if status[1].Name != "slow" || status[1].LastStatus != "succeeded" || status[1].RunCount != 1 {
+		t.Errorf("Expected 'slow' to succeed, got %+v", status[1])
+	}
+}
+
+func TestSchedulerRun_RecordsOutcome(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"Success", nil, "succeeded"},
+		{"Failure", errors.New("API externa respondió con código 500"), "failed"},
+		{"Skipped by task", fmt.Errorf("%w: sincronización manual en curso", ErrSkipped), "skipped"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := New(0)
+			if err := s.Add("sync", "@daily", func(ctx context.Context) error { return tc.err }); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			s.run(context.Background(), s.entries[0])
+
+			status := s.Status()[0]
+			if status.LastStatus != tc.expected {
+				t.Errorf("Expected status %s, got %s", tc.expected, status.LastStatus)
+			}
+			if status.LastRunAt == nil {
+				t.Error("Expected LastRunAt to be set")
+			}
+			if status.Running {
+				t.Error("Task should not be running after completion")
+			}
+		})
+	}
+}
@@ -12,6 +12,12 @@ type Stock struct {
 	TargetFrom string    `json:"target_from"`
 	TargetTo   string    `json:"target_to"`
 	Time       time.Time `json:"time"`
+	// RawTime es el texto de la fecha cuando el proveedor la mandó ilegible;
+	// Time queda en cero y la validación pone el evento en cuarentena
+	RawTime string `json:"-"`
+	// ParseError explica por qué el item no se pudo leer (fila CSV rota,
+	// JSON inválido); los demás campos pueden venir vacíos
+	ParseError string `json:"-"`
 }
 
 type StockResponse struct {
@@ -0,0 +1,61 @@
+package external
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// RawPayload es una respuesta tal cual la envió un proveedor HTTP
+type RawPayload struct {
+	Provider   string
+	PageToken  string
+	URL        string
+	StatusCode int
+	Body       []byte
+	FetchedAt  time.Time
+}
+
+// PayloadArchiver guarda las respuestas crudas de los proveedores, para
+// poder revisar qué enviaron y re-procesarlas sin volver a llamarlos
+type PayloadArchiver interface {
+	ArchivePayload(ctx context.Context, payload RawPayload) error
+}
+
+// PayloadDecoder lo implementan los proveedores cuyas páginas archivadas
+// se pueden re-procesar con el mapeo actual
+type PayloadDecoder interface {
+	DecodePayload(body []byte) (*Page, error)
+}
+
+type skipArchiveKey struct{}
+
+// WithoutArchive marca el contexto para que las respuestas no se archiven,
+// por ejemplo en un dry-run que no debe escribir nada
+func WithoutArchive(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipArchiveKey{}, true)
+}
+
+func archiveSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipArchiveKey{}).(bool)
+	return skip
+}
+
+// archivable lo implementan los proveedores HTTP que archivan sus respuestas
+type archivable interface {
+	SetArchiver(a PayloadArchiver)
+}
+
+// redactURL quita credenciales de la URL antes de guardarla
+func redactURL(u *url.URL) string {
+	clean := *u
+	clean.User = nil
+	q := clean.Query()
+	for _, key := range []string{"token", "api_key", "apikey", "access_token"} {
+		if q.Has(key) {
+			q.Set(key, "REDACTED")
+		}
+	}
+	clean.RawQuery = q.Encode()
+	return clean.String()
+}
@@ -1,6 +1,7 @@
 package external
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,47 +10,89 @@ import (
 	"github.com/juanF18/EquiSignal-Backend/internal/interface/dto"
 )
 
+// PrimaryProviderName identifica a la API con bearer token original
+const PrimaryProviderName = "primary"
+
+// ExternalAPI es el proveedor principal: API paginada con bearer token
 type ExternalAPI struct {
-	cfg *config.Config
+	cfg  *config.Config
+	http *resilientClient
 }
 
 func NewExternalAPI(cfg *config.Config) *ExternalAPI {
-	return &ExternalAPI{cfg: cfg}
+	return &ExternalAPI{
+		cfg:  cfg,
+		http: newResilientClient(PrimaryProviderName, cfg),
+	}
+}
+
+func (e *ExternalAPI) Name() string {
+	return PrimaryProviderName
 }
 
-func (e *ExternalAPI) FetchStocks(nextPage string) (*dto.StockResponse, error) {
-	client := &http.Client{}
+func (e *ExternalAPI) Capabilities() Capabilities {
+	return Capabilities{Paginated: true, NewestFirst: true, Remote: true}
+}
 
-	req, err := http.NewRequest("GET", e.cfg.ExternalAPIURL, nil)
+// FetchPage implementa RatingsProvider
+func (e *ExternalAPI) FetchPage(ctx context.Context, pageToken string) (*Page, error) {
+	resp, err := e.FetchStocks(ctx, pageToken)
 	if err != nil {
 		return nil, err
 	}
+	return &Page{Items: resp.Items, NextPage: resp.NextPage}, nil
+}
 
-	// Auth header
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", e.cfg.ExternalAPIToken))
+// FetchStocks trae una página del proveedor. Reintenta con backoff ante
+// errores de red, 429 y 5xx, respetando Retry-After y el rate limit local.
+func (e *ExternalAPI) FetchStocks(ctx context.Context, nextPage string) (*dto.StockResponse, error) {
+	body, err := e.http.get(ctx, nextPage, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", e.cfg.ExternalAPIURL, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	// query param
-	if nextPage != "" {
-		q := req.URL.Query()
-		q.Add("next_page", nextPage)
-		req.URL.RawQuery = q.Encode()
-	}
+		// Auth header
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", e.cfg.ExternalAPIToken))
 
-	resp, err := client.Do(req)
+		// query param
+		if nextPage != "" {
+			q := req.URL.Query()
+			q.Add("next_page", nextPage)
+			req.URL.RawQuery = q.Encode()
+		}
+		return req, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// Validamos código HTTP
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API externa respondió con código %d", resp.StatusCode)
-	}
+	return decodeStockResponse(body)
+}
+
+// SetArchiver habilita el archivo de respuestas crudas
+func (e *ExternalAPI) SetArchiver(a PayloadArchiver) {
+	e.http.archiver = a
+}
 
-	var stockResp dto.StockResponse
-	if err := json.NewDecoder(resp.Body).Decode(&stockResp); err != nil {
+// DecodePayload implementa PayloadDecoder para re-procesar páginas archivadas
+func (e *ExternalAPI) DecodePayload(body []byte) (*Page, error) {
+	resp, err := decodeStockResponse(body)
+	if err != nil {
 		return nil, err
 	}
+	return &Page{Items: resp.Items, NextPage: resp.NextPage}, nil
+}
 
-	return &stockResp, nil
+// decodeStockResponse decodifica la página item por item, como los demás
+// proveedores, para que una fecha ilegible no invalide la página entera
+func decodeStockResponse(body []byte) (*dto.StockResponse, error) {
+	var raw struct {
+		Items    []json.RawMessage `json:"items"`
+		NextPage string            `json:"next_page"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	return &dto.StockResponse{Items: decodeStockItems(raw.Items), NextPage: raw.NextPage}, nil
 }
@@ -0,0 +1,286 @@
+package external
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/config"
+)
+
+// newTestAPI crea un cliente apuntando al proveedor de prueba con esperas cortas
+func newTestAPI(url string, maxRetries int) *ExternalAPI {
+	return NewExternalAPI(&config.Config{
+		ExternalAPIURL:         url,
+		ExternalAPIToken:       "secret",
+		ExternalAPITimeout:     time.Second,
+		ExternalAPIMaxRetries:  maxRetries,
+		ExternalAPIBackoffBase: time.Millisecond,
+		ExternalAPIBackoffMax:  5 * time.Millisecond,
+	})
+}
+
+func TestFetchStocks_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("next_page") != "abc" {
+			t.Errorf("Expected next_page=abc, got %q", r.URL.Query().Get("next_page"))
+		}
+		w.Write([]byte(`{"items":[{"ticker":"AAPL","company":"Apple Inc."}],"next_page":"def"}`))
+	}))
+	defer server.Close()
+
+	resp, err := newTestAPI(server.URL, 0).FetchStocks(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(resp.Items) != 1 || resp.Items[0].Ticker != "AAPL" {
+		t.Errorf("Unexpected items: %+v", resp.Items)
+	}
+	if resp.NextPage != "def" {
+		t.Errorf("Expected next page def, got %q", resp.NextPage)
+	}
+}
+
+func TestFetchStocks_RetriesTransientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{"items":[],"next_page":""}`))
+		}
+	}))
+	defer server.Close()
+
+	_, err := newTestAPI(server.URL, 3).FetchStocks(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestFetchStocks_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := newTestAPI(server.URL, 2).FetchStocks(context.Background(), "")
+	if err == nil {
+		t.Fatal("Expected error after exhausting retries")
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 calls (1 + 2 retries), got %d", calls)
+	}
+}
+
+func TestFetchStocks_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := newTestAPI(server.URL, 5).FetchStocks(context.Background(), "")
+	if err == nil {
+		t.Fatal("Expected error for 401")
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected a single call, got %d", calls)
+	}
+}
+
+func TestFetchStocks_TimeoutIsRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Write([]byte(`{"items":[],"next_page":""}`))
+	}))
+	defer server.Close()
+
+	api := newTestAPI(server.URL, 1)
+	api.http.client.Timeout = 20 * time.Millisecond
+
+	if _, err := api.FetchStocks(context.Background(), ""); err != nil {
+		t.Fatalf("Expected success after timeout retry, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestFetchStocks_ContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := newTestAPI(server.URL, 3).FetchStocks(ctx, "")
+	if err == nil {
+		t.Fatal("Expected error when context is cancelled")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Retry-After wait should stop when the context is cancelled")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"Seconds", "120", 2 * time.Minute, true},
+		{"HTTP date", now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{"Past date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"Empty", "", 0, false},
+		{"Invalid", "soon", 0, false},
+		{"Negative", "-5", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, ok := parseRetryAfter(tc.value, now)
+			if ok != tc.ok || d != tc.expected {
+				t.Errorf("Expected (%s, %v), got (%s, %v)", tc.expected, tc.ok, d, ok)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := retryPolicy{backoffBase: 100 * time.Millisecond, backoffMax: time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		expectedMax := 100 * time.Millisecond << attempt
+		if expectedMax > time.Second {
+			expectedMax = time.Second
+		}
+
+		d := policy.backoff(attempt)
+		if d < expectedMax/2 || d > expectedMax {
+			t.Errorf("Attempt %d: backoff %s outside [%s, %s]", attempt, d, expectedMax/2, expectedMax)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff_NoCap(t *testing.T) {
+	policy := retryPolicy{backoffBase: 100 * time.Millisecond}
+
+	// Sin máximo la espera sigue duplicándose
+	d := policy.backoff(5)
+	if expected := 3200 * time.Millisecond; d < expected/2 || d > expected {
+		t.Errorf("Expected backoff in [%s, %s], got %s", expected/2, expected, d)
+	}
+	if d := policy.capWait(time.Hour); d != time.Hour {
+		t.Errorf("Expected no cap, got %s", d)
+	}
+}
+
+func TestFetchStocks_CapsRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+
+	// El máximo de backoff de newTestAPI es 5ms: la hora pedida no se respeta
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := newTestAPI(server.URL, 1).FetchStocks(ctx, ""); err != nil {
+		t.Fatalf("Expected Retry-After to be capped, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	current := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	bucket := newTokenBucket(2, 2) // 2 req/s, ráfaga de 2
+	bucket.now = func() time.Time { return current }
+
+	// La ráfaga inicial no espera
+	if wait := bucket.reserve(); wait != 0 {
+		t.Errorf("Expected first token immediately, waited %s", wait)
+	}
+	if wait := bucket.reserve(); wait != 0 {
+		t.Errorf("Expected second token immediately, waited %s", wait)
+	}
+
+	// Sin tokens hay que esperar medio segundo (2 tokens por segundo)
+	if wait := bucket.reserve(); wait != 500*time.Millisecond {
+		t.Errorf("Expected 500ms wait, got %s", wait)
+	}
+
+	// Tras recargar hay token disponible
+	current = current.Add(500 * time.Millisecond)
+	if wait := bucket.reserve(); wait != 0 {
+		t.Errorf("Expected token after refill, waited %s", wait)
+	}
+
+	if newTokenBucket(0, 1) != nil {
+		t.Error("Rate 0 should disable the limiter")
+	}
+}
+
+func TestFetchStocks_KeepsItemsWithBadTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"items":[
+			{"ticker":"AAPL","company":"Apple Inc.","time":"2025-08-01T00:00:00Z"},
+			{"ticker":"MSFT","company":"Microsoft","time":"mañana"},
+			{"ticker":"NVDA","company":"NVIDIA","time":"2025-08-02T00:00:00.123Z"}
+		],"next_page":"def"}`))
+	}))
+	defer server.Close()
+
+	page, err := newTestAPI(server.URL, 0).FetchPage(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.Items) != 3 || page.NextPage != "def" {
+		t.Fatalf("Expected the whole page, got %+v", page)
+	}
+	if bad := page.Items[1]; bad.Ticker != "MSFT" || bad.RawTime != "mañana" || !bad.Time.IsZero() {
+		t.Errorf("Unexpected bad item: %+v", bad)
+	}
+	for _, i := range []int{0, 2} {
+		if item := page.Items[i]; item.RawTime != "" || item.Time.IsZero() {
+			t.Errorf("Unexpected valid item: %+v", item)
+		}
+	}
+}
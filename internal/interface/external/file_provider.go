@@ -0,0 +1,152 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/dto"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/importer"
+)
+
+// FileProvider lee eventos de archivos dejados en un directorio local.
+// Cada archivo (.json, .jsonl o .csv) es una página, en orden alfabético.
+//
+// Los .json pueden ser un objeto {"items": [...]} como el de la API o un
+// arreglo de items. Los .csv y .jsonl usan los mismos nombres de campo que
+// la API (ticker, company, brokerage, action, rating_from, rating_to,
+// target_from, target_to, time).
+type FileProvider struct {
+	name string
+	dir  string
+}
+
+func NewFileProvider(name, dir string) *FileProvider {
+	return &FileProvider{name: name, dir: dir}
+}
+
+func (f *FileProvider) Name() string {
+	return f.name
+}
+
+func (f *FileProvider) Capabilities() Capabilities {
+	return Capabilities{Paginated: true, NewestFirst: false, Remote: false}
+}
+
+// FetchPage lee el archivo pageToken (o el primero si está vacío)
+func (f *FileProvider) FetchPage(ctx context.Context, pageToken string) (*Page, error) {
+	files, err := f.files()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return &Page{}, nil
+	}
+
+	idx := 0
+	if pageToken != "" {
+		idx = sort.SearchStrings(files, pageToken)
+		if idx >= len(files) || files[idx] != pageToken {
+			return nil, fmt.Errorf("archivo %q no encontrado en %s", pageToken, f.dir)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(f.dir, files[idx]))
+	if err != nil {
+		return nil, err
+	}
+
+	var items []dto.Stock
+	switch strings.ToLower(filepath.Ext(files[idx])) {
+	case ".csv":
+		items, err = parseStocksFile(data, importer.FormatCSV)
+	case ".jsonl":
+		items, err = parseStocksFile(data, importer.FormatJSONL)
+	default:
+		items, err = parseStocksJSON(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", files[idx], err)
+	}
+
+	for i := range items {
+		if items[i].ParseError != "" {
+			items[i].ParseError = files[idx] + " " + items[i].ParseError
+		}
+	}
+
+	page := &Page{Items: items}
+	if idx+1 < len(files) {
+		page.NextPage = files[idx+1]
+	}
+	return page, nil
+}
+
+// files lista los archivos soportados del directorio, ordenados
+func (f *FileProvider) files() ([]string, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".json", ".jsonl", ".csv":
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// parseStocksJSON acepta un objeto {"items": [...]} o un arreglo de items
+func parseStocksJSON(data []byte) ([]dto.Stock, error) {
+	var rawItems []json.RawMessage
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &rawItems); err != nil {
+			return nil, err
+		}
+	} else {
+		var resp struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(trimmed, &resp); err != nil {
+			return nil, err
+		}
+		rawItems = resp.Items
+	}
+
+	return decodeStockItems(rawItems), nil
+}
+
+// parseStocksFile lee un .csv o .jsonl. Una fecha ilegible deja el item con
+// RawTime y una línea que no se puede leer, con ParseError; en los dos casos
+// la validación lo pone en cuarentena.
+func parseStocksFile(data []byte, format importer.Format) ([]dto.Stock, error) {
+	var items []dto.Stock
+	err := importer.Read(bytes.NewReader(data), format, nil, func(row importer.Row) error {
+		item := row.Stock
+		var unreadable []string
+		for _, e := range row.Errors {
+			if e.Field != "time" {
+				unreadable = append(unreadable, e.Message)
+			}
+		}
+		if len(unreadable) > 0 {
+			item.ParseError = fmt.Sprintf("línea %d: %s", row.Line, strings.Join(unreadable, "; "))
+		}
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
@@ -0,0 +1,139 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/config"
+)
+
+// resilientClient hace GETs con timeout, rate limit local y reintentos
+// con backoff ante errores de red, 429 y 5xx. Lo comparten los proveedores HTTP.
+type resilientClient struct {
+	client  *http.Client
+	limiter *tokenBucket
+	retry   retryPolicy
+
+	// provider y archiver permiten guardar cada respuesta cruda
+	provider string
+	archiver PayloadArchiver
+}
+
+func newResilientClient(provider string, cfg *config.Config) *resilientClient {
+	return &resilientClient{
+		provider: provider,
+		client:   &http.Client{Timeout: cfg.ExternalAPITimeout},
+		limiter:  newTokenBucket(cfg.ExternalAPIRateLimit, cfg.ExternalAPIRateBurst),
+		retry: retryPolicy{
+			maxRetries:  cfg.ExternalAPIMaxRetries,
+			backoffBase: cfg.ExternalAPIBackoffBase,
+			backoffMax:  cfg.ExternalAPIBackoffMax,
+		},
+	}
+}
+
+// get ejecuta la request construida por newRequest y devuelve el body de la
+// primera respuesta 200, reintentando las fallas transitorias. pageToken solo
+// se usa para archivar la respuesta.
+func (c *resilientClient) get(ctx context.Context, pageToken string, newRequest func(ctx context.Context) (*http.Request, error)) ([]byte, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= c.retry.maxRetries; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		body, wait, err := c.getOnce(ctx, pageToken, newRequest)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || ctx.Err() != nil {
+			return nil, err
+		}
+		if attempt == c.retry.maxRetries {
+			break
+		}
+
+		if wait < 0 {
+			wait = c.retry.backoff(attempt)
+		} else {
+			// Un Retry-After de horas no puede frenar la sincronización
+			wait = c.retry.capWait(wait)
+		}
+		log.Printf("🔁 Reintentando API externa en %s (intento %d/%d): %v", wait, attempt+1, c.retry.maxRetries, err)
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("API externa falló tras %d intentos: %w", c.retry.maxRetries+1, lastErr)
+}
+
+// permanentError marca fallas que no vale la pena reintentar
+type permanentError struct {
+	err error
+}
+
+func (p *permanentError) Error() string { return p.err.Error() }
+func (p *permanentError) Unwrap() error { return p.err }
+
+// getOnce hace un solo intento. Si el servidor pidió esperar (Retry-After)
+// devuelve esa espera; -1 significa usar el backoff normal.
+func (c *resilientClient) getOnce(ctx context.Context, pageToken string, newRequest func(ctx context.Context) (*http.Request, error)) ([]byte, time.Duration, error) {
+	req, err := newRequest(ctx)
+	if err != nil {
+		return nil, 0, &permanentError{err}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, -1, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, -1, err
+	}
+	c.archive(ctx, req, pageToken, resp.StatusCode, body)
+
+	// Validamos código HTTP
+	if resp.StatusCode != http.StatusOK {
+		statusErr := fmt.Errorf("API externa respondió con código %d", resp.StatusCode)
+		if !isRetryableStatus(resp.StatusCode) {
+			return nil, 0, &permanentError{statusErr}
+		}
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return nil, wait, statusErr
+		}
+		return nil, -1, statusErr
+	}
+
+	return body, 0, nil
+}
+
+// archive guarda la respuesta cruda; un error al archivar no detiene la sincronización
+func (c *resilientClient) archive(ctx context.Context, req *http.Request, pageToken string, status int, body []byte) {
+	if c.archiver == nil || archiveSkipped(ctx) {
+		return
+	}
+	payload := RawPayload{
+		Provider:   c.provider,
+		PageToken:  pageToken,
+		URL:        redactURL(req.URL),
+		StatusCode: status,
+		Body:       body,
+		FetchedAt:  time.Now(),
+	}
+	if err := c.archiver.ArchivePayload(ctx, payload); err != nil {
+		log.Printf("⚠️ Error archivando respuesta de %s: %v", c.provider, err)
+	}
+}
@@ -0,0 +1,248 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/config"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/dto"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/importer"
+)
+
+// mappableFields son los campos de dto.Stock que se pueden mapear
+var mappableFields = map[string]bool{
+	"ticker": true, "company": true, "brokerage": true, "action": true,
+	"rating_from": true, "rating_to": true, "target_from": true, "target_to": true,
+	"time": true,
+}
+
+// FieldMapping describe cómo traducir el JSON de otro proveedor a dto.Stock.
+// Las rutas usan puntos para entrar en objetos anidados ("meta.next_cursor").
+type FieldMapping struct {
+	// ItemsPath es la ruta al arreglo de items; vacío si la raíz es el arreglo
+	ItemsPath string `json:"items_path"`
+	// NextPagePath es la ruta al token de la siguiente página
+	NextPagePath string `json:"next_page_path"`
+	// PageParam es el query param con el que se pide la siguiente página
+	PageParam string `json:"page_param"`
+	// AuthHeader es el header de autenticación; "Authorization" usa "Bearer <token>"
+	AuthHeader string `json:"auth_header"`
+	// Fields mapea cada campo de dto.Stock a su ruta dentro del item
+	Fields map[string]string `json:"fields"`
+	// TimeLayout es el formato de fecha si no es ISO-8601 ni epoch en segundos
+	TimeLayout string `json:"time_layout"`
+	// NewestFirst indica que el proveedor entrega primero los eventos más nuevos
+	NewestFirst bool `json:"newest_first"`
+}
+
+// LoadFieldMapping lee el mapeo desde un archivo JSON
+func LoadFieldMapping(path string) (*FieldMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapping FieldMapping
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("mapeo %s: %w", path, err)
+	}
+	if err := mapping.Validate(); err != nil {
+		return nil, fmt.Errorf("mapeo %s: %w", path, err)
+	}
+	return &mapping, nil
+}
+
+// Validate exige ticker y time y rechaza campos desconocidos
+func (m *FieldMapping) Validate() error {
+	for field := range m.Fields {
+		if !mappableFields[field] {
+			return fmt.Errorf("campo desconocido %q en el mapeo", field)
+		}
+	}
+	if m.Fields["ticker"] == "" || m.Fields["time"] == "" {
+		return fmt.Errorf("el mapeo debe incluir ticker y time")
+	}
+	return nil
+}
+
+// MappedHTTPProvider consume una API HTTP con un esquema distinto al de la
+// API principal, traduciendo cada item con un FieldMapping
+type MappedHTTPProvider struct {
+	name    string
+	url     string
+	token   string
+	mapping FieldMapping
+	http    *resilientClient
+}
+
+func NewMappedHTTPProvider(name, url, token string, mapping FieldMapping, cfg *config.Config) *MappedHTTPProvider {
+	if mapping.PageParam == "" {
+		mapping.PageParam = "page"
+	}
+	if mapping.AuthHeader == "" {
+		mapping.AuthHeader = "Authorization"
+	}
+	return &MappedHTTPProvider{
+		name:    name,
+		url:     url,
+		token:   token,
+		mapping: mapping,
+		http:    newResilientClient(name, cfg),
+	}
+}
+
+func (m *MappedHTTPProvider) Name() string {
+	return m.name
+}
+
+func (m *MappedHTTPProvider) Capabilities() Capabilities {
+	return Capabilities{Paginated: m.mapping.NextPagePath != "", NewestFirst: m.mapping.NewestFirst, Remote: true}
+}
+
+func (m *MappedHTTPProvider) FetchPage(ctx context.Context, pageToken string) (*Page, error) {
+	body, err := m.http.get(ctx, pageToken, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", m.url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if m.token != "" {
+			if m.mapping.AuthHeader == "Authorization" {
+				req.Header.Set("Authorization", "Bearer "+m.token)
+			} else {
+				req.Header.Set(m.mapping.AuthHeader, m.token)
+			}
+		}
+		if pageToken != "" {
+			q := req.URL.Query()
+			q.Set(m.mapping.PageParam, pageToken)
+			req.URL.RawQuery = q.Encode()
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m.mapping.Decode(body)
+}
+
+// SetArchiver habilita el archivo de respuestas crudas
+func (m *MappedHTTPProvider) SetArchiver(a PayloadArchiver) {
+	m.http.archiver = a
+}
+
+// DecodePayload implementa PayloadDecoder con el mapeo actual
+func (m *MappedHTTPProvider) DecodePayload(body []byte) (*Page, error) {
+	return m.mapping.Decode(body)
+}
+
+// Decode traduce el body de una página al formato normalizado
+func (m *FieldMapping) Decode(body []byte) (*Page, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var root interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+
+	rawItems, ok := lookupPath(root, m.ItemsPath).([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("no se encontró el arreglo de items en %q", m.ItemsPath)
+	}
+
+	page := &Page{Items: make([]dto.Stock, 0, len(rawItems))}
+	for _, raw := range rawItems {
+		page.Items = append(page.Items, m.decodeItem(raw))
+	}
+
+	if m.NextPagePath != "" {
+		page.NextPage = stringValue(lookupPath(root, m.NextPagePath))
+	}
+	return page, nil
+}
+
+// decodeItem nunca falla: una fecha ilegible queda en RawTime para que la
+// validación ponga el item en cuarentena sin perder el resto de la página
+func (m *FieldMapping) decodeItem(raw interface{}) dto.Stock {
+	field := func(name string) string {
+		path, ok := m.Fields[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(stringValue(lookupPath(raw, path)))
+	}
+
+	rawTime := lookupPath(raw, m.Fields["time"])
+	item := dto.Stock{
+		Ticker:     field("ticker"),
+		Company:    field("company"),
+		Brokerage:  field("brokerage"),
+		Action:     field("action"),
+		RatingFrom: field("rating_from"),
+		RatingTo:   field("rating_to"),
+		TargetFrom: field("target_from"),
+		TargetTo:   field("target_to"),
+	}
+	t, err := m.parseTime(rawTime)
+	if err != nil {
+		item.RawTime = stringValue(rawTime)
+	}
+	item.Time = t
+	return item
+}
+
+// parseTime acepta epoch en segundos, el layout configurado o ISO-8601
+func (m *FieldMapping) parseTime(v interface{}) (time.Time, error) {
+	if n, ok := v.(json.Number); ok {
+		secs, err := n.Int64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("epoch inválido %q", n)
+		}
+		return time.Unix(secs, 0).UTC(), nil
+	}
+
+	s := stringValue(v)
+	if m.TimeLayout != "" {
+		t, err := time.Parse(m.TimeLayout, s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("fecha inválida %q", s)
+		}
+		return t, nil
+	}
+	return importer.ParseTime(s)
+}
+
+// lookupPath recorre objetos anidados siguiendo una ruta con puntos
+func lookupPath(v interface{}, path string) interface{} {
+	if path == "" {
+		return v
+	}
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = obj[key]
+	}
+	return v
+}
+
+func stringValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	default:
+		return fmt.Sprint(val)
+	}
+}
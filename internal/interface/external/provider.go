@@ -0,0 +1,95 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/dto"
+)
+
+// RatingsProvider es una fuente de eventos de rating de analistas.
+// Cada adaptador traduce su formato propio a dto.Stock.
+type RatingsProvider interface {
+	// Name identifica al proveedor; se guarda en cada models.Stock
+	Name() string
+	Capabilities() Capabilities
+	// FetchPage trae la página indicada por pageToken ("" es la primera)
+	FetchPage(ctx context.Context, pageToken string) (*Page, error)
+}
+
+// Capabilities describe qué soporta un proveedor
+type Capabilities struct {
+	// Paginated indica que el proveedor entrega los datos en varias páginas
+	Paginated bool `json:"paginated"`
+	// NewestFirst indica que los eventos llegan del más nuevo al más viejo,
+	// requisito para la sincronización incremental
+	NewestFirst bool `json:"newest_first"`
+	// Remote indica que los datos vienen de un servicio externo
+	Remote bool `json:"remote"`
+}
+
+// Page es una página de eventos ya normalizados
+type Page struct {
+	Items    []dto.Stock
+	NextPage string
+}
+
+// ProviderInfo es la descripción pública de un proveedor registrado
+type ProviderInfo struct {
+	Name         string       `json:"name"`
+	Default      bool         `json:"default"`
+	Capabilities Capabilities `json:"capabilities"`
+}
+
+// ErrUnknownProvider se devuelve al pedir un proveedor no registrado
+var ErrUnknownProvider = errors.New("proveedor desconocido")
+
+// Registry guarda los proveedores disponibles; el primero registrado es el default
+type Registry struct {
+	providers   map[string]RatingsProvider
+	defaultName string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]RatingsProvider)}
+}
+
+// Register agrega un proveedor; los nombres no se pueden repetir
+func (r *Registry) Register(p RatingsProvider) error {
+	if _, exists := r.providers[p.Name()]; exists {
+		return fmt.Errorf("el proveedor %q ya está registrado", p.Name())
+	}
+	r.providers[p.Name()] = p
+	if r.defaultName == "" {
+		r.defaultName = p.Name()
+	}
+	return nil
+}
+
+// Get devuelve el proveedor con ese nombre; vacío devuelve el default
+func (r *Registry) Get(name string) (RatingsProvider, error) {
+	if name == "" {
+		name = r.defaultName
+	}
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownProvider, name)
+	}
+	return p, nil
+}
+
+// List devuelve los proveedores registrados ordenados por nombre
+func (r *Registry) List() []ProviderInfo {
+	infos := make([]ProviderInfo, 0, len(r.providers))
+	for name, p := range r.providers {
+		infos = append(infos, ProviderInfo{
+			Name:         name,
+			Default:      name == r.defaultName,
+			Capabilities: p.Capabilities(),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
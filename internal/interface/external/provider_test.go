@@ -0,0 +1,285 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/config"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/dto"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	primary := NewExternalAPI(&config.Config{})
+	files := NewFileProvider("files", t.TempDir())
+
+	if err := registry.Register(primary); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := registry.Register(files); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := registry.Register(NewFileProvider("files", t.TempDir())); err == nil {
+		t.Error("Expected error registering duplicate provider name")
+	}
+
+	// Vacío devuelve el primero registrado
+	p, err := registry.Get("")
+	if err != nil || p.Name() != PrimaryProviderName {
+		t.Errorf("Expected default provider %s, got %v (%v)", PrimaryProviderName, p, err)
+	}
+
+	if _, err := registry.Get("missing"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("Expected ErrUnknownProvider, got %v", err)
+	}
+
+	infos := registry.List()
+	if len(infos) != 2 || infos[0].Name != "files" || !infos[1].Default {
+		t.Errorf("Unexpected provider list: %+v", infos)
+	}
+}
+
+func TestFileProvider_WalksFilesAsPages(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "001.csv", "ticker,company,brokerage,action,rating_from,rating_to,target_from,target_to,time\n"+
+		"AAPL,Apple Inc.,Goldman Sachs,upgraded by,Hold,Buy,$150.00,$180.00,2025-08-01T12:00:00Z\n"+
+		"MSFT,Microsoft,UBS,reiterated by,Buy,Buy,$300.00,$320.00,2025-08-02\n")
+	writeFile(t, dir, "002.json", `{"items":[{"ticker":"TSLA","company":"Tesla","time":"2025-08-03T00:00:00Z"}]}`)
+	writeFile(t, dir, "003.json", `[{"ticker":"NVDA","company":"NVIDIA","time":"2025-08-04T00:00:00Z"}]`)
+	writeFile(t, dir, "notes.txt", "ignorado")
+
+	provider := NewFileProvider("files", dir)
+	var tickers []string
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("Too many pages")
+		}
+		page, err := provider.FetchPage(context.Background(), token)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, item := range page.Items {
+			tickers = append(tickers, item.Ticker)
+		}
+		if page.NextPage == "" {
+			break
+		}
+		token = page.NextPage
+	}
+
+	expected := []string{"AAPL", "MSFT", "TSLA", "NVDA"}
+	if len(tickers) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, tickers)
+	}
+	for i := range expected {
+		if tickers[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, tickers)
+		}
+	}
+}
+
+func TestFileProvider_CSVFields(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "ratings.csv", "Ticker,Company,Brokerage,Action,Rating_From,Rating_To,Target_From,Target_To,Time\n"+
+		"AAPL,Apple Inc.,Goldman Sachs,upgraded by,Hold,Buy,$150.00,$180.00,2025-08-01 12:30:00\n")
+
+	page, err := NewFileProvider("files", dir).FetchPage(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	item := page.Items[0]
+	if item.Brokerage != "Goldman Sachs" || item.RatingTo != "Buy" || item.TargetTo != "$180.00" {
+		t.Errorf("Unexpected item: %+v", item)
+	}
+	if !item.Time.Equal(time.Date(2025, 8, 1, 12, 30, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected time: %v", item.Time)
+	}
+}
+
+func TestFileProvider_KeepsItemsWithBadTime(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "001.csv", "ticker,company,time\n"+
+		"AAPL,Apple Inc.,2025-08-01\n"+
+		"MSFT,Microsoft,ayer\n"+
+		"NVDA,NVIDIA,2025-08-02\n")
+	writeFile(t, dir, "002.json", `[{"ticker":"TSLA","company":"Tesla","time":"ayer"},{"ticker":"AMZN","company":"Amazon","time":"2025-08-03T00:00:00Z"}]`)
+
+	provider := NewFileProvider("files", dir)
+	csvPage, err := provider.FetchPage(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	jsonPage, err := provider.FetchPage(context.Background(), csvPage.NextPage)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, page := range []*Page{csvPage, jsonPage} {
+		bad := 0
+		for _, item := range page.Items {
+			if item.RawTime != "" {
+				bad++
+				if item.RawTime != "ayer" || !item.Time.IsZero() || item.Company == "" {
+					t.Errorf("Unexpected bad item: %+v", item)
+				}
+			}
+		}
+		if bad != 1 {
+			t.Errorf("Expected 1 item with raw time, got %d in %+v", bad, page.Items)
+		}
+	}
+	if len(csvPage.Items) != 3 || len(jsonPage.Items) != 2 {
+		t.Errorf("Expected every item to be kept, got %d and %d", len(csvPage.Items), len(jsonPage.Items))
+	}
+}
+
+func TestFileProvider_KeepsUnreadableRows(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "001.jsonl", `{"ticker":"AAPL","company":"Apple Inc.","time":"2025-08-01"}`+"\n"+
+		`{"ticker": "MSFT",`+"\n"+
+		`{"ticker":"NVDA","company":"NVIDIA","time":"2025-08-02"}`+"\n")
+	writeFile(t, dir, "002.json", `[{"ticker":"TSLA","company":"Tesla","time":"2025-08-03T00:00:00Z"}, 42]`)
+
+	provider := NewFileProvider("files", dir)
+	jsonlPage, err := provider.FetchPage(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	jsonPage, err := provider.FetchPage(context.Background(), jsonlPage.NextPage)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(jsonlPage.Items) != 3 || len(jsonPage.Items) != 2 {
+		t.Fatalf("Expected every row to be kept, got %+v and %+v", jsonlPage.Items, jsonPage.Items)
+	}
+	if bad := jsonlPage.Items[1]; !strings.HasPrefix(bad.ParseError, "001.jsonl línea 2: JSON inválido") {
+		t.Errorf("Unexpected unreadable line: %+v", bad)
+	}
+	if bad := jsonPage.Items[1]; !strings.HasPrefix(bad.ParseError, "002.json item 1:") {
+		t.Errorf("Unexpected unreadable item: %+v", bad)
+	}
+	for _, item := range []dto.Stock{jsonlPage.Items[0], jsonlPage.Items[2], jsonPage.Items[0]} {
+		if item.ParseError != "" {
+			t.Errorf("Unexpected parse error: %+v", item)
+		}
+	}
+}
+
+func TestFieldMapping_DecodeKeepsItemsWithBadTime(t *testing.T) {
+	mapping := FieldMapping{
+		ItemsPath: "items",
+		Fields:    map[string]string{"ticker": "symbol", "time": "published"},
+	}
+	page, err := mapping.Decode([]byte(`{"items": [
+		{"symbol": "AAPL", "published": "2025-08-01T00:00:00Z"},
+		{"symbol": "MSFT", "published": "not a date"}
+	]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %+v", page.Items)
+	}
+	if page.Items[0].RawTime != "" || page.Items[0].Time.IsZero() {
+		t.Errorf("Unexpected valid item: %+v", page.Items[0])
+	}
+	if bad := page.Items[1]; bad.Ticker != "MSFT" || bad.RawTime != "not a date" || !bad.Time.IsZero() {
+		t.Errorf("Unexpected bad item: %+v", bad)
+	}
+}
+
+func TestMappedHTTPProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			t.Errorf("Expected api key header, got %q", r.Header.Get("X-Api-Key"))
+		}
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{
+				"data": {"ratings": [
+					{"symbol": "AAPL", "issuer": {"name": "Apple Inc."}, "firm": "UBS",
+					 "rating": {"old": "Neutral", "new": "Buy"}, "pt": {"old": 150, "new": 185.5},
+					 "published": 1754049600}
+				]},
+				"meta": {"next": "c2"}
+			}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"ratings": []}, "meta": {"next": null}}`))
+	}))
+	defer server.Close()
+
+	mapping := FieldMapping{
+		ItemsPath:    "data.ratings",
+		NextPagePath: "meta.next",
+		PageParam:    "cursor",
+		AuthHeader:   "X-Api-Key",
+		Fields: map[string]string{
+			"ticker":      "symbol",
+			"company":     "issuer.name",
+			"brokerage":   "firm",
+			"rating_from": "rating.old",
+			"rating_to":   "rating.new",
+			"target_from": "pt.old",
+			"target_to":   "pt.new",
+			"time":        "published",
+		},
+	}
+	if err := mapping.Validate(); err != nil {
+		t.Fatalf("Unexpected mapping error: %v", err)
+	}
+
+	provider := NewMappedHTTPProvider("secondary", server.URL, "key", mapping, &config.Config{ExternalAPITimeout: time.Second})
+
+	page, err := provider.FetchPage(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(page.Items) != 1 || page.NextPage != "c2" {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+
+	item := page.Items[0]
+	if item.Ticker != "AAPL" || item.Company != "Apple Inc." || item.RatingFrom != "Neutral" || item.TargetTo != "185.5" {
+		t.Errorf("Unexpected mapped item: %+v", item)
+	}
+	if !item.Time.Equal(time.Unix(1754049600, 0)) {
+		t.Errorf("Unexpected time: %v", item.Time)
+	}
+
+	last, err := provider.FetchPage(context.Background(), "c2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(last.Items) != 0 || last.NextPage != "" {
+		t.Errorf("Expected empty last page, got %+v", last)
+	}
+}
+
+func TestFieldMappingValidate(t *testing.T) {
+	missingTime := FieldMapping{Fields: map[string]string{"ticker": "symbol"}}
+	if err := missingTime.Validate(); err == nil {
+		t.Error("Expected error when time is not mapped")
+	}
+
+	unknown := FieldMapping{Fields: map[string]string{"ticker": "symbol", "time": "ts", "price": "p"}}
+	if err := unknown.Validate(); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,48 @@
+package external
+
+import (
+	"fmt"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/config"
+)
+
+// BuildRegistry registra la API principal y los proveedores opcionales
+// habilitados en la configuración. Si archiver no es nil, los proveedores
+// HTTP guardan cada respuesta cruda.
+func BuildRegistry(cfg *config.Config, archiver PayloadArchiver) (*Registry, error) {
+	registry := NewRegistry()
+
+	if err := registry.Register(NewExternalAPI(cfg)); err != nil {
+		return nil, err
+	}
+
+	if cfg.FileProviderDir != "" {
+		if err := registry.Register(NewFileProvider(cfg.FileProviderName, cfg.FileProviderDir)); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.SecondaryAPIURL != "" {
+		if cfg.SecondaryAPIMapping == "" {
+			return nil, fmt.Errorf("SECONDARY_API_MAPPING es obligatorio cuando SECONDARY_API_URL está definido")
+		}
+		mapping, err := LoadFieldMapping(cfg.SecondaryAPIMapping)
+		if err != nil {
+			return nil, err
+		}
+		provider := NewMappedHTTPProvider(cfg.SecondaryAPIName, cfg.SecondaryAPIURL, cfg.SecondaryAPIToken, *mapping, cfg)
+		if err := registry.Register(provider); err != nil {
+			return nil, err
+		}
+	}
+
+	if archiver != nil {
+		for _, p := range registry.providers {
+			if a, ok := p.(archivable); ok {
+				a.SetArchiver(archiver)
+			}
+		}
+	}
+
+	return registry, nil
+}
@@ -0,0 +1,79 @@
+package external
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryPolicy define cuántas veces y cuánto esperar entre reintentos
+type retryPolicy struct {
+	maxRetries  int
+	backoffBase time.Duration
+	// backoffMax limita el backoff y el Retry-After del servidor; 0 es sin límite
+	backoffMax time.Duration
+}
+
+// backoff calcula la espera del intento n (0-based) con backoff exponencial
+// y jitter: un valor aleatorio entre la mitad y el total de base*2^n.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	d := p.backoffBase
+	for i := 0; i < attempt && d < math.MaxInt64/2; i++ {
+		if p.backoffMax > 0 && d >= p.backoffMax {
+			break
+		}
+		d *= 2
+	}
+	d = p.capWait(d)
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// capWait limita una espera a backoffMax, salvo que sea 0 (sin límite)
+func (p retryPolicy) capWait(d time.Duration) time.Duration {
+	if p.backoffMax > 0 && d > p.backoffMax {
+		return p.backoffMax
+	}
+	return d
+}
+
+// isRetryableStatus indica si el código HTTP es transitorio
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusRequestTimeout,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter interpreta el header Retry-After, que puede venir en
+// segundos o como fecha HTTP.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
@@ -0,0 +1,46 @@
+package external
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/dto"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/importer"
+)
+
+// decodeStockItems decodifica cada item por separado para que un item malo
+// no invalide la página: una fecha ilegible queda en RawTime y un item que no
+// se puede leer, en ParseError
+func decodeStockItems(rawItems []json.RawMessage) []dto.Stock {
+	items := make([]dto.Stock, 0, len(rawItems))
+	for i, raw := range rawItems {
+		item, err := parseStockJSON(raw)
+		if err != nil {
+			item = dto.Stock{ParseError: fmt.Sprintf("item %d: %v", i, err)}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func parseStockJSON(raw json.RawMessage) (dto.Stock, error) {
+	// Time como texto para aceptar cualquier valor y validarlo después
+	var item struct {
+		dto.Stock
+		Time interface{} `json:"time"`
+	}
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return dto.Stock{}, err
+	}
+
+	stock := item.Stock
+	if item.Time != nil {
+		rawTime := fmt.Sprint(item.Time)
+		t, err := importer.ParseTime(rawTime)
+		if err != nil {
+			stock.RawTime = rawTime
+		}
+		stock.Time = t
+	}
+	return stock, nil
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/application"
+)
+
+type BrokerageHandler struct {
+	service *application.BrokerageService
+}
+
+func NewBrokerageHandler(service *application.BrokerageService) *BrokerageHandler {
+	return &BrokerageHandler{service: service}
+}
+
+// ListBrokerages lista los brokerages con su tier, peso y alias
+func (h *BrokerageHandler) ListBrokerages(c *gin.Context) {
+	brokerages, err := h.service.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": brokerages, "total": len(brokerages)})
+}
+
+func (h *BrokerageHandler) GetBrokerage(c *gin.Context) {
+	id, ok := brokerageID(c)
+	if !ok {
+		return
+	}
+
+	b, err := h.service.Get(id)
+	if err != nil {
+		brokerageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": b})
+}
+
+// CreateBrokerage crea un brokerage; name, tier y weight son obligatorios
+func (h *BrokerageHandler) CreateBrokerage(c *gin.Context) {
+	var input application.BrokerageInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	b, err := h.service.Create(input)
+	if err != nil {
+		brokerageError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": b})
+}
+
+// UpdateBrokerage modifica los campos enviados; aliases reemplaza la lista
+func (h *BrokerageHandler) UpdateBrokerage(c *gin.Context) {
+	id, ok := brokerageID(c)
+	if !ok {
+		return
+	}
+
+	var input application.BrokerageInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	b, err := h.service.Update(id, input)
+	if err != nil {
+		brokerageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": b})
+}
+
+func (h *BrokerageHandler) DeleteBrokerage(c *gin.Context) {
+	id, ok := brokerageID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		brokerageError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Brokerage eliminado"})
+}
+
+func brokerageID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id de brokerage inválido"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func brokerageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, application.ErrBrokerageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrInvalidBrokerage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrBrokerageConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juanF18/EquiSignal-Backend/internal/application"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/importer"
+)
+
+type ImportHandler struct {
+	service *application.ImportService
+}
+
+func NewImportHandler(service *application.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// CreateImport recibe un archivo multipart ("file") y devuelve el reporte por fila.
+// Campos opcionales del formulario: format (csv|jsonl), mapping
+// ("ticker=Symbol,company=Name") y provider.
+func (h *ImportHandler) CreateImport(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "se requiere el archivo en el campo 'file'"})
+		return
+	}
+
+	format, err := importer.ParseFormat(c.PostForm("format"), fileHeader.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mapping, err := importer.ParseColumnMapping(c.PostForm("mapping"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	report, err := h.service.Import(c.Request.Context(), file, application.ImportOptions{
+		Format:   format,
+		Mapping:  mapping,
+		Provider: c.PostForm("provider"),
+		Source:   fileHeader.Filename,
+	})
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "data": report})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
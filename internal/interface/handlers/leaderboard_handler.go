@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juanF18/EquiSignal-Backend/internal/application"
+)
+
+type LeaderboardHandler struct {
+	// service es nil si no hay cotizaciones configuradas
+	service *application.LeaderboardService
+}
+
+func NewLeaderboardHandler(service *application.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{service: service}
+}
+
+// GetLeaderboard devuelve el último ranking de brokerages por su historial real
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	if h.service == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": application.ErrLeaderboardUnavailable.Error() + ": falta LEADERBOARD_PRICES_DIR"})
+		return
+	}
+
+	board, err := h.service.Current()
+	if errors.Is(err, application.ErrLeaderboardUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": board, "total": len(board.Brokerages)})
+}
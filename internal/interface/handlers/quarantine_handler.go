@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/application"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+type QuarantineHandler struct {
+	service *application.QuarantineService
+}
+
+func NewQuarantineHandler(service *application.QuarantineService) *QuarantineHandler {
+	return &QuarantineHandler{service: service}
+}
+
+// ListQuarantine lista los registros en cuarentena; ?status=pending|readmitted|discarded
+func (h *QuarantineHandler) ListQuarantine(c *gin.Context) {
+	status := models.QuarantineStatus(c.DefaultQuery("status", string(models.QuarantinePending)))
+	switch status {
+	case models.QuarantinePending, models.QuarantineReadmitted, models.QuarantineDiscarded:
+	case "all":
+		status = ""
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status inválido (pending, readmitted, discarded, all)"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if err != nil || pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	items, total, err := h.service.List(status, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":     items,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+func (h *QuarantineHandler) GetQuarantined(c *gin.Context) {
+	id, ok := quarantineID(c)
+	if !ok {
+		return
+	}
+
+	q, err := h.service.Get(id)
+	if err != nil {
+		quarantineError(c, err, nil)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": q})
+}
+
+// FixQuarantined corrige campos del registro y devuelve los motivos que quedan
+func (h *QuarantineHandler) FixQuarantined(c *gin.Context) {
+	id, ok := quarantineID(c)
+	if !ok {
+		return
+	}
+
+	var fix application.QuarantineFix
+	if err := c.ShouldBindJSON(&fix); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	q, issues, err := h.service.Fix(id, fix)
+	if err != nil {
+		quarantineError(c, err, q)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": q, "issues": issues, "valid": len(issues) == 0})
+}
+
+// ReadmitQuarantined guarda el registro en stocks si ya pasa la validación
+func (h *QuarantineHandler) ReadmitQuarantined(c *gin.Context) {
+	id, ok := quarantineID(c)
+	if !ok {
+		return
+	}
+
+	q, _, err := h.service.Readmit(id)
+	var invalid *application.StillInvalidError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "issues": invalid.Issues, "data": q})
+		return
+	}
+	if err != nil {
+		quarantineError(c, err, q)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Registro re-admitido", "data": q})
+}
+
+func (h *QuarantineHandler) DiscardQuarantined(c *gin.Context) {
+	id, ok := quarantineID(c)
+	if !ok {
+		return
+	}
+
+	q, err := h.service.Discard(id)
+	if err != nil {
+		quarantineError(c, err, q)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Registro descartado", "data": q})
+}
+
+func quarantineID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id de cuarentena inválido"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func quarantineError(c *gin.Context, err error, q *models.QuarantinedStock) {
+	switch {
+	case errors.Is(err, application.ErrQuarantineNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrQuarantineResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "data": q})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juanF18/EquiSignal-Backend/internal/application"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+)
+
+type RatingHandler struct {
+	service *application.RatingService
+}
+
+func NewRatingHandler(service *application.RatingService) *RatingHandler {
+	return &RatingHandler{service: service}
+}
+
+// ListRatings lista el vocabulario y los niveles de la escala canónica
+func (h *RatingHandler) ListRatings(c *gin.Context) {
+	mappings, err := h.service.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": mappings, "total": len(mappings), "scale": models.CanonicalRatings})
+}
+
+// PutRating agrega o reemplaza la traducción de un texto de rating
+func (h *RatingHandler) PutRating(c *gin.Context) {
+	var input application.RatingMappingInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	m, err := h.service.Put(input)
+	if err != nil {
+		ratingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": m})
+}
+
+func (h *RatingHandler) DeleteRating(c *gin.Context) {
+	if err := h.service.Delete(c.Param("label")); err != nil {
+		ratingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Rating eliminado del vocabulario"})
+}
+
+// ListUnmapped lista los textos de rating sin traducción, los más usados primero
+func (h *RatingHandler) ListUnmapped(c *gin.Context) {
+	unmapped, err := h.service.Unmapped()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": unmapped, "total": len(unmapped)})
+}
+
+func ratingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, application.ErrRatingMappingNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrInvalidRatingMapping):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/scheduler"
+)
+
+type ScheduleHandler struct {
+	schedulers []*scheduler.Scheduler
+}
+
+func NewScheduleHandler(schedulers ...*scheduler.Scheduler) *ScheduleHandler {
+	return &ScheduleHandler{schedulers: schedulers}
+}
+
+// ListSchedules muestra las programaciones de todos los schedulers con su
+// última y próxima ejecución
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	statuses := []scheduler.EntryStatus{}
+	for _, s := range h.schedulers {
+		statuses = append(statuses, s.Status()...)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	c.JSON(http.StatusOK, gin.H{"data": statuses})
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/juanF18/EquiSignal-Backend/internal/algorithms/stock"
 	"github.com/juanF18/EquiSignal-Backend/internal/application"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/external"
 )
 
 type StockHandler struct {
@@ -17,12 +21,52 @@ func NewStockHandler(service *application.StockService) *StockHandler {
 }
 
 func (h *StockHandler) UpdateStocks(c *gin.Context) {
-	err := h.service.UpdateStocks()
+	mode, err := application.ParseSyncMode(c.DefaultQuery("mode", string(application.SyncModeFull)))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run inválido"})
+		return
+	}
+
+	result, err := h.service.Sync(c.Request.Context(), application.SyncOptions{
+		Provider: c.Query("provider"),
+		Mode:     mode,
+		DryRun:   dryRun,
+	})
+	if errors.Is(err, application.ErrSyncInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Stocks updated successfully"})
+	if errors.Is(err, external.ErrUnknownProvider) || errors.Is(err, application.ErrIncrementalUnsupported) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Dry-run completed, nothing was written",
+			"result":  result,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Stocks updated successfully",
+		"result":     result,
+		"checkpoint": result.Checkpoint,
+	})
+}
+
+// ListProviders muestra los proveedores registrados y sus capacidades
+func (h *StockHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": h.service.Providers()})
 }
 
 func (h *StockHandler) GetStocks(c *gin.Context) {
@@ -42,7 +86,34 @@ func (h *StockHandler) GetStocks(c *gin.Context) {
 		pageSize = 10
 	}
 
-	stocks, total, err := h.service.GetStocks(page, pageSize, search)
+	filter, err := application.ParseStockFilter(c.Request.URL.Query(), h.service.RatingTaxonomy())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sort, err := application.ParseStockSort(c.Query("sort"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   err.Error(),
+			"allowed": repository.SortableFields(),
+		})
+		return
+	}
+
+	// ?cursor= (vacío para la primera página) activa la paginación por cursor
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		h.getStocksByCursor(c, cursor, pageSize, search, filter, sort)
+		return
+	}
+
+	stocks, total, err := h.service.GetStocks(repository.StockQuery{
+		Page:     page,
+		PageSize: pageSize,
+		Search:   search,
+		Filter:   filter,
+		Sort:     sort,
+	})
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -62,8 +133,94 @@ func (h *StockHandler) GetStocks(c *gin.Context) {
 
 }
 
+// getStocksByCursor responde el listado paginado por (time, id). Solo admite
+// ordenar por time, y el total se calcula únicamente con ?count=true.
+func (h *StockHandler) getStocksByCursor(c *gin.Context, cursor string, pageSize int, search string, filter repository.StockFilter, sort []repository.SortField) {
+	if len(sort) > 1 || (len(sort) == 1 && sort[0].Field != "time") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "la paginación por cursor solo ordena por time",
+			"allowed": []string{"time", "-time"},
+		})
+		return
+	}
+
+	withTotal, err := strconv.ParseBool(c.DefaultQuery("count", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "count inválido"})
+		return
+	}
+
+	page, err := h.service.GetStocksPage(application.StockPageRequest{
+		Cursor:    cursor,
+		Limit:     pageSize,
+		Search:    search,
+		Filter:    filter,
+		Asc:       len(sort) == 1 && !sort[0].Desc,
+		WithTotal: withTotal,
+	})
+	if errors.Is(err, application.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message":     "Error fetching stocks",
+			"description": err.Error(),
+		})
+		return
+	}
+
+	response := gin.H{
+		"data":        page.Stocks,
+		"pageSize":    pageSize,
+		"next_cursor": page.NextCursor,
+		"prev_cursor": page.PrevCursor,
+	}
+	if page.Total != nil {
+		response["total"] = *page.Total
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRecommend devuelve el top 10; ?mode=ticker combina todos los eventos
+// de cada ticker en vez de quedarse con el mejor evento (mode=event) y
+// ?profile= elige el perfil de scoring. ?as_of= recomienda con los eventos
+// publicados hasta esa fecha y mide la frescura contra ella. ?weights=measured
+// usa la credibilidad medida en el leaderboard de brokerages.
 func (h *StockHandler) GetRecommend(c *gin.Context) {
-	recs, err := h.service.GetRecommend(10)
+	mode, err := stock.ParseMode(c.Query("mode"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	asOf, err := application.ParseAsOf(c.Query("as_of"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	weights, err := application.ParseWeightSource(c.Query("weights"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recs, err := h.service.GetRecommend(application.RecommendRequest{
+		Limit:   10,
+		Mode:    mode,
+		Profile: c.Query("profile"),
+		AsOf:    asOf,
+		Weights: weights,
+	})
+	if errors.Is(err, application.ErrUnknownProfile) || errors.Is(err, application.ErrInvalidAsOf) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, application.ErrLeaderboardUnavailable) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -77,3 +234,31 @@ func (h *StockHandler) GetRecommend(c *gin.Context) {
 		"data": recs,
 	})
 }
+
+// GetTickerDetail devuelve el historial, el consenso y el último score de un ticker
+func (h *StockHandler) GetTickerDetail(c *gin.Context) {
+	detail, err := h.service.GetTickerDetail(c.Param("ticker"))
+	if errors.Is(err, application.ErrInvalidTicker) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, application.ErrTickerNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": detail})
+}
+
+// GetStats resume los eventos guardados
+func (h *StockHandler) GetStats(c *gin.Context) {
+	stats, err := h.service.GetStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": stats})
+}
@@ -1,8 +1,18 @@
 package handlers
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juanF18/EquiSignal-Backend/internal/application"
+	"github.com/juanF18/EquiSignal-Backend/internal/domain/models"
+	"github.com/juanF18/EquiSignal-Backend/internal/infrastructure/repository"
 )
 
 // Tests para funciones de utilidad y validación
@@ -182,6 +192,301 @@ func TestHTTPStatusCodeLogic(t *testing.T) {
 	}
 }
 
+// newTestStockRouter monta el handler sobre un repositorio en memoria
+func newTestStockRouter(stocks ...models.Stock) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	service := application.NewStockService(nil, repository.NewMemoryStockRepository(stocks...), nil, nil)
+	h := NewStockHandler(service)
+
+	r := gin.New()
+	r.GET("/api/stocks", h.GetStocks)
+	r.GET("/api/stocks/recommend", h.GetRecommend)
+	r.GET("/api/stocks/stats", h.GetStats)
+	r.GET("/api/stocks/:ticker", h.GetTickerDetail)
+	return r
+}
+
+func TestGetStocks_Handler(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var stocks []models.Stock
+	for i, ticker := range []string{"AAPL", "MSFT", "TSLA"} {
+		stocks = append(stocks, models.Stock{Ticker: ticker, Company: ticker + " Inc.", Time: base.Add(time.Duration(i) * time.Hour)})
+	}
+	r := newTestStockRouter(stocks...)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks?page=2&pageSize=2", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var body struct {
+		Data       []models.Stock `json:"data"`
+		Total      int64          `json:"total"`
+		Page       int            `json:"page"`
+		TotalPages int64          `json:"total_pages"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if body.Total != 3 || body.Page != 2 || body.TotalPages != 2 {
+		t.Errorf("Unexpected pagination: %+v", body)
+	}
+	if len(body.Data) != 1 || body.Data[0].Ticker != "AAPL" {
+		t.Errorf("Expected oldest stock on the last page, got %+v", body.Data)
+	}
+}
+
+func TestGetStocks_HandlerFilters(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := newTestStockRouter(
+		models.Stock{Ticker: "AAPL", Brokerage: "UBS", Action: "upgraded by", RatingTo: "Buy", Time: base},
+		models.Stock{Ticker: "MSFT", Brokerage: "Citi", Action: "downgraded by", RatingTo: "Sell", Time: base},
+	)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks?direction=downgrade&brokerage=citi", nil))
+
+	var body struct {
+		Data  []models.Stock `json:"data"`
+		Total int64          `json:"total"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusOK || body.Total != 1 || body.Data[0].Ticker != "MSFT" {
+		t.Errorf("Expected only MSFT, got %d %+v", w.Code, body)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks?min_target_change=abc", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid filter, got %d", w.Code)
+	}
+}
+
+func TestGetStocks_HandlerSort(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := newTestStockRouter(
+		models.Stock{Ticker: "MSFT", Brokerage: "UBS", Time: base},
+		models.Stock{Ticker: "AAPL", Brokerage: "UBS", Time: base.Add(time.Hour)},
+		models.Stock{Ticker: "TSLA", Brokerage: "Citi", Time: base},
+	)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks?sort=-brokerage,ticker", nil))
+
+	var body struct {
+		Data []models.Stock `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if w.Code != http.StatusOK || len(body.Data) != 3 {
+		t.Fatalf("Unexpected response: %d %s", w.Code, w.Body.String())
+	}
+	for i, ticker := range []string{"AAPL", "MSFT", "TSLA"} {
+		if body.Data[i].Ticker != ticker {
+			t.Errorf("Expected %s at %d, got %s", ticker, i, body.Data[i].Ticker)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks?sort=price", nil))
+
+	var errBody struct {
+		Allowed []string `json:"allowed"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &errBody)
+	if w.Code != http.StatusBadRequest || len(errBody.Allowed) == 0 {
+		t.Errorf("Expected 400 with allowed fields, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetStocks_HandlerCursor(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := newTestStockRouter(
+		models.Stock{Ticker: "AAPL", Time: base},
+		models.Stock{Ticker: "MSFT", Time: base.Add(time.Hour)},
+		models.Stock{Ticker: "TSLA", Time: base.Add(2 * time.Hour)},
+	)
+
+	type cursorBody struct {
+		Data       []models.Stock `json:"data"`
+		NextCursor string         `json:"next_cursor"`
+		PrevCursor string         `json:"prev_cursor"`
+		Total      *int64         `json:"total"`
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks?cursor=&pageSize=2&count=true", nil))
+	var first cursorBody
+	json.Unmarshal(w.Body.Bytes(), &first)
+	if w.Code != http.StatusOK || len(first.Data) != 2 || first.Data[0].Ticker != "TSLA" || first.NextCursor == "" {
+		t.Fatalf("Unexpected first page: %d %s", w.Code, w.Body.String())
+	}
+	if first.Total == nil || *first.Total != 3 {
+		t.Errorf("Expected total 3 with count=true, got %v", first.Total)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks?pageSize=2&cursor="+first.NextCursor, nil))
+	var second cursorBody
+	json.Unmarshal(w.Body.Bytes(), &second)
+	if len(second.Data) != 1 || second.Data[0].Ticker != "AAPL" || second.NextCursor != "" || second.PrevCursor == "" {
+		t.Errorf("Unexpected second page: %s", w.Body.String())
+	}
+	if second.Total != nil {
+		t.Error("Expected no total without count=true")
+	}
+
+	for _, query := range []string{"cursor=garbage", "cursor=&sort=ticker"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %q, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetTickerDetail_Handler(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := newTestStockRouter(
+		models.Stock{Ticker: "AAPL", Company: "Apple", Brokerage: "UBS", RatingFrom: "Hold", RatingTo: "Buy", TargetFrom: "$100.00", TargetTo: "$120.00", Time: base},
+		models.Stock{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Citi", RatingFrom: "Buy", RatingTo: "Sell", Time: base.Add(time.Hour)},
+		models.Stock{Ticker: "MSFT", Company: "Microsoft", Brokerage: "UBS", RatingTo: "Buy", Time: base},
+	)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks/aapl", nil))
+
+	var body struct {
+		Data application.TickerDetail `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Unexpected response: %d %s", w.Code, w.Body.String())
+	}
+	detail := body.Data
+	if detail.Ticker != "AAPL" || detail.Company != "Apple Inc." || detail.Events != 2 {
+		t.Errorf("Unexpected detail: %+v", detail)
+	}
+	// La línea de tiempo va en orden cronológico
+	if len(detail.Timeline) != 2 || detail.Timeline[0].Brokerage != "UBS" || detail.Timeline[0].TargetChangePct == nil {
+		t.Errorf("Unexpected timeline: %+v", detail.Timeline)
+	}
+	if first := detail.Timeline[0]; first.TargetToValue == nil || first.TargetToValue.String() != "120.00" || first.TargetCurrency != "USD" {
+		t.Errorf("Expected numeric target in USD, got %+v", first)
+	}
+	if !strings.Contains(w.Body.String(), `"target_to_value":120.00`) {
+		t.Errorf("Expected exact target in JSON: %s", w.Body.String())
+	}
+	if detail.Consensus.Positive != 1 || detail.Consensus.Negative != 1 {
+		t.Errorf("Unexpected consensus: %+v", detail.Consensus)
+	}
+	if len(detail.Score.Factors) == 0 {
+		t.Error("Expected score breakdown")
+	}
+
+	// Las rutas fijas no se confunden con un ticker
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks/stats", nil))
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "timeline") {
+		t.Errorf("Expected stats response, got %s", w.Body.String())
+	}
+
+	testCases := []struct {
+		path     string
+		expected int
+	}{
+		{"/api/stocks/NVDA", http.StatusNotFound},
+		{"/api/stocks/not-a-ticker!", http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.expected {
+			t.Errorf("Expected %d for %s, got %d", tc.expected, tc.path, w.Code)
+		}
+	}
+}
+
+func TestGetRecommend_HandlerMode(t *testing.T) {
+	r := newTestStockRouter(
+		models.Stock{Ticker: "AAPL", Brokerage: "UBS", RatingTo: "Buy", Time: time.Now()},
+		models.Stock{Ticker: "AAPL", Brokerage: "Citi", RatingTo: "Buy", Time: time.Now()},
+	)
+
+	for _, mode := range []string{"", "event", "ticker"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks/recommend?mode="+mode, nil))
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "AAPL") {
+			t.Errorf("Unexpected response for mode %q: %d %s", mode, w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), `"Breakdown":[{"name":"rating"`) || !strings.Contains(w.Body.String(), `"codes":[`) {
+			t.Errorf("Expected structured breakdown for mode %q, got %s", mode, w.Body.String())
+		}
+	}
+
+	for _, query := range []string{"mode=best", "profile=unknown", "as_of=yesterday", "as_of=2999-01-01", "weights=opinion"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks/recommend?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %q, got %d", query, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks/recommend?profile=default", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the built-in default profile, got %d", w.Code)
+	}
+
+	// Sin leaderboard configurado no hay pesos medidos
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks/recommend?weights=measured", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without leaderboard, got %d", w.Code)
+	}
+}
+
+func TestGetLeaderboard_Handler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/brokerages/leaderboard", NewLeaderboardHandler(nil).GetLeaderboard)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/brokerages/leaderboard", nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "LEADERBOARD_PRICES_DIR") {
+		t.Errorf("Expected 503 when disabled, got %d %s", w.Code, w.Body.String())
+	}
+
+	r = gin.New()
+	service := application.NewLeaderboardService(repository.NewMemoryStockRepository(), nil, t.TempDir())
+	r.GET("/api/brokerages/leaderboard", NewLeaderboardHandler(service).GetLeaderboard)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/brokerages/leaderboard", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 before the first refresh, got %d", w.Code)
+	}
+}
+
+func TestGetStats_Handler(t *testing.T) {
+	r := newTestStockRouter(
+		models.Stock{Ticker: "AAPL", Brokerage: "UBS", Time: time.Now(), Provider: "primary"},
+		models.Stock{Ticker: "MSFT", Brokerage: "UBS", Time: time.Now(), Provider: "primary"},
+	)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stocks/stats", nil))
+
+	var body struct {
+		Data repository.StockStats `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if body.Data.Total != 2 || body.Data.Tickers != 2 || body.Data.Brokerages != 1 {
+		t.Errorf("Unexpected stats: %+v", body.Data)
+	}
+}
+
 // Helper functions
 func defaultQuery(value, defaultValue string) string {
 	if value == "" {
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/juanF18/EquiSignal-Backend/internal/application"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/external"
+)
+
+type SyncJobHandler struct {
+	service *application.SyncJobService
+}
+
+func NewSyncJobHandler(service *application.SyncJobService) *SyncJobHandler {
+	return &SyncJobHandler{service: service}
+}
+
+type createSyncJobRequest struct {
+	Provider string `json:"provider"`
+	Mode     string `json:"mode"`
+}
+
+// CreateSyncJob inicia una sincronización en segundo plano y devuelve el job
+func (h *SyncJobHandler) CreateSyncJob(c *gin.Context) {
+	var req createSyncJobRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Mode == "" {
+		req.Mode = c.Query("mode")
+	}
+	if req.Provider == "" {
+		req.Provider = c.Query("provider")
+	}
+
+	mode, err := application.ParseSyncMode(req.Mode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.service.Start(req.Provider, mode)
+	if errors.Is(err, application.ErrSyncInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, external.ErrUnknownProvider) || errors.Is(err, application.ErrIncrementalUnsupported) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": job})
+}
+
+func (h *SyncJobHandler) GetSyncJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id de job inválido"})
+		return
+	}
+
+	job, err := h.service.Get(id)
+	if errors.Is(err, application.ErrSyncJobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": job})
+}
+
+func (h *SyncJobHandler) ListSyncJobs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	jobs, err := h.service.List(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": jobs})
+}
+
+// CancelSyncJob pide detener un job en curso
+func (h *SyncJobHandler) CancelSyncJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id de job inválido"})
+		return
+	}
+
+	job, err := h.service.Cancel(id)
+	switch {
+	case errors.Is(err, application.ErrSyncJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrSyncJobNotRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "data": job})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusAccepted, gin.H{"message": "Cancelación solicitada", "data": job})
+	}
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/handlers"
+)
+
+func RegisterScheduleRoutes(r *gin.RouterGroup, h *handlers.ScheduleHandler) {
+	r.GET("/schedules", h.ListSchedules)
+}
+
+func RegisterQuarantineRoutes(r *gin.RouterGroup, h *handlers.QuarantineHandler) {
+	quarantine := r.Group("/quarantine")
+	{
+		quarantine.GET("", h.ListQuarantine)
+		quarantine.GET("/:id", h.GetQuarantined)
+		quarantine.PATCH("/:id", h.FixQuarantined)
+		quarantine.POST("/:id/readmit", h.ReadmitQuarantined)
+		quarantine.POST("/:id/discard", h.DiscardQuarantined)
+	}
+}
+
+func RegisterBrokerageRoutes(r *gin.RouterGroup, h *handlers.BrokerageHandler) {
+	brokerages := r.Group("/brokerages")
+	{
+		brokerages.GET("", h.ListBrokerages)
+		brokerages.POST("", h.CreateBrokerage)
+		brokerages.GET("/:id", h.GetBrokerage)
+		brokerages.PATCH("/:id", h.UpdateBrokerage)
+		brokerages.DELETE("/:id", h.DeleteBrokerage)
+	}
+}
+
+func RegisterRatingRoutes(r *gin.RouterGroup, h *handlers.RatingHandler) {
+	ratings := r.Group("/ratings")
+	{
+		ratings.GET("", h.ListRatings)
+		ratings.PUT("", h.PutRating)
+		ratings.GET("/unmapped", h.ListUnmapped)
+		ratings.DELETE("/:label", h.DeleteRating)
+	}
+}
@@ -9,5 +9,6 @@ func RegisterExternalAPIRoutes(r *gin.RouterGroup, h *handlers.StockHandler) {
 	externalGroup := r.Group("/external")
 	{
 		externalGroup.GET("/update-stocks", h.UpdateStocks)
+		externalGroup.GET("/providers", h.ListProviders)
 	}
 }
@@ -0,0 +1,10 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/handlers"
+)
+
+func RegisterImportRoutes(r *gin.RouterGroup, h *handlers.ImportHandler) {
+	r.POST("/imports", h.CreateImport)
+}
@@ -5,10 +5,31 @@ import (
 	"github.com/juanF18/EquiSignal-Backend/internal/interface/handlers"
 )
 
-func SetupRoutes(r *gin.Engine, stockHandler *handlers.StockHandler) {
+// Handlers agrupa los controladores que se montan bajo /api
+type Handlers struct {
+	Stock       *handlers.StockHandler
+	SyncJob     *handlers.SyncJobHandler
+	Schedule    *handlers.ScheduleHandler
+	Import      *handlers.ImportHandler
+	Quarantine  *handlers.QuarantineHandler
+	Brokerage   *handlers.BrokerageHandler
+	Rating      *handlers.RatingHandler
+	Leaderboard *handlers.LeaderboardHandler
+}
+
+func SetupRoutes(r *gin.Engine, h Handlers) {
 	api := r.Group("/api")
 	{
-		RegisterExternalAPIRoutes(api, stockHandler)
-		RegisterStockRoutes(api, stockHandler)
+		RegisterExternalAPIRoutes(api, h.Stock)
+		RegisterStockRoutes(api, h.Stock)
+		RegisterSyncJobRoutes(api, h.SyncJob)
+		RegisterImportRoutes(api, h.Import)
+		RegisterLeaderboardRoutes(api, h.Leaderboard)
+
+		admin := api.Group("/admin")
+		RegisterScheduleRoutes(admin, h.Schedule)
+		RegisterQuarantineRoutes(admin, h.Quarantine)
+		RegisterBrokerageRoutes(admin, h.Brokerage)
+		RegisterRatingRoutes(admin, h.Rating)
 	}
 }
@@ -9,5 +9,8 @@ func RegisterStockRoutes(r *gin.RouterGroup, h *handlers.StockHandler) {
 	{
 		r.GET("/stocks", h.GetStocks)
 		r.GET("/stocks/recommend", h.GetRecommend)
+		r.GET("/stocks/stats", h.GetStats)
+		// gin prioriza las rutas fijas (recommend, stats) sobre el parámetro
+		r.GET("/stocks/:ticker", h.GetTickerDetail)
 	}
 }
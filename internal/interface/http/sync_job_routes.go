@@ -0,0 +1,16 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/handlers"
+)
+
+func RegisterSyncJobRoutes(r *gin.RouterGroup, h *handlers.SyncJobHandler) {
+	jobs := r.Group("/sync-jobs")
+	{
+		jobs.POST("", h.CreateSyncJob)
+		jobs.GET("", h.ListSyncJobs)
+		jobs.GET("/:id", h.GetSyncJob)
+		jobs.POST("/:id/cancel", h.CancelSyncJob)
+	}
+}
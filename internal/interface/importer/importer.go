@@ -0,0 +1,243 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juanF18/EquiSignal-Backend/internal/interface/dto"
+)
+
+// Format es el formato del archivo a importar
+type Format string
+
+const (
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// Fields son los campos de dto.Stock que se pueden importar
+var Fields = []string{
+	"ticker", "company", "brokerage", "action",
+	"rating_from", "rating_to", "target_from", "target_to", "time",
+}
+
+// ParseFormat valida el formato; si viene vacío se deduce de la extensión del archivo
+func ParseFormat(format, filename string) (Format, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".csv":
+			return FormatCSV, nil
+		case ".jsonl", ".ndjson":
+			return FormatJSONL, nil
+		}
+		return "", fmt.Errorf("no se pudo deducir el formato de %q (use csv o jsonl)", filename)
+	}
+
+	switch Format(strings.ToLower(format)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSONL, "ndjson":
+		return FormatJSONL, nil
+	}
+	return "", fmt.Errorf("formato inválido %q (valores permitidos: csv, jsonl)", format)
+}
+
+// ColumnMapping indica de qué columna (CSV) o llave (JSON) sale cada campo.
+// Los campos no mapeados se buscan con su propio nombre.
+type ColumnMapping map[string]string
+
+// ParseColumnMapping interpreta "ticker=Symbol,company=Company Name"
+func ParseColumnMapping(s string) (ColumnMapping, error) {
+	mapping := ColumnMapping{}
+	if strings.TrimSpace(s) == "" {
+		return mapping, nil
+	}
+
+	known := make(map[string]bool, len(Fields))
+	for _, f := range Fields {
+		known[f] = true
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !ok || field == "" || column == "" {
+			return nil, fmt.Errorf("mapeo inválido %q (use campo=columna)", pair)
+		}
+		if !known[field] {
+			return nil, fmt.Errorf("campo desconocido %q (permitidos: %s)", field, strings.Join(Fields, ", "))
+		}
+		mapping[field] = column
+	}
+	return mapping, nil
+}
+
+func (m ColumnMapping) source(field string) string {
+	if col, ok := m[field]; ok {
+		return col
+	}
+	return field
+}
+
+// FieldError es un error de validación de un campo de una fila
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Row es una fila leída del archivo con sus errores de formato
+type Row struct {
+	// Line es la línea del archivo (1-based, incluyendo el encabezado CSV)
+	Line   int
+	Stock  dto.Stock
+	Errors []FieldError
+}
+
+// Read recorre el archivo y llama fn por cada fila. Los errores de una fila
+// no detienen la lectura; solo un archivo ilegible o un error de fn lo hacen.
+func Read(r io.Reader, format Format, mapping ColumnMapping, fn func(Row) error) error {
+	switch format {
+	case FormatCSV:
+		return readCSV(r, mapping, fn)
+	case FormatJSONL:
+		return readJSONL(r, mapping, fn)
+	}
+	return fmt.Errorf("formato inválido %q", format)
+}
+
+func readCSV(r io.Reader, mapping ColumnMapping, fn func(Row) error) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("encabezado CSV: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	for _, field := range []string{"ticker", "time"} {
+		if _, ok := columns[strings.ToLower(mapping.source(field))]; !ok {
+			return fmt.Errorf("el CSV no tiene la columna %q para el campo %s", mapping.source(field), field)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if fnErr := fn(Row{Line: line, Errors: []FieldError{{Message: err.Error()}}}); fnErr != nil {
+				return fnErr
+			}
+			continue
+		}
+
+		values := make(map[string]string, len(Fields))
+		for _, field := range Fields {
+			if i, ok := columns[strings.ToLower(mapping.source(field))]; ok && i < len(record) {
+				values[field] = strings.TrimSpace(record[i])
+			}
+		}
+		if err := fn(buildRow(line, values)); err != nil {
+			return err
+		}
+	}
+}
+
+func readJSONL(r io.Reader, mapping ColumnMapping, fn func(Row) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &obj); err != nil {
+			if fnErr := fn(Row{Line: line, Errors: []FieldError{{Message: "JSON inválido: " + err.Error()}}}); fnErr != nil {
+				return fnErr
+			}
+			continue
+		}
+
+		values := make(map[string]string, len(Fields))
+		for _, field := range Fields {
+			if v, ok := obj[mapping.source(field)]; ok && v != nil {
+				values[field] = strings.TrimSpace(fmt.Sprint(v))
+			}
+		}
+		if err := fn(buildRow(line, values)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// buildRow arma el dto y registra los errores de formato de la fila;
+// las reglas de negocio (campos obligatorios, vocabulario) las valida
+// application.ValidateStock
+func buildRow(line int, values map[string]string) Row {
+	row := Row{Line: line}
+
+	t, err := ParseTime(values["time"])
+	if err != nil {
+		row.Errors = append(row.Errors, FieldError{Field: "time", Message: err.Error()})
+	}
+
+	row.Stock = dto.Stock{
+		Ticker:     values["ticker"],
+		Company:    values["company"],
+		Brokerage:  values["brokerage"],
+		Action:     values["action"],
+		RatingFrom: values["rating_from"],
+		RatingTo:   values["rating_to"],
+		TargetFrom: values["target_from"],
+		TargetTo:   values["target_to"],
+		Time:       t,
+	}
+	if err != nil {
+		row.Stock.RawTime = values["time"]
+	}
+
+	return row
+}
+
+// timeLayouts son los formatos de fecha aceptados
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"01/02/2006",
+}
+
+// ParseTime acepta ISO-8601 con o sin hora; vacío devuelve el tiempo cero
+func ParseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("fecha inválida %q", s)
+}
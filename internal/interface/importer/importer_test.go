@@ -0,0 +1,135 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFormat(t *testing.T) {
+	testCases := []struct {
+		format    string
+		filename  string
+		expected  Format
+		expectErr bool
+	}{
+		{"", "history.csv", FormatCSV, false},
+		{"", "history.jsonl", FormatJSONL, false},
+		{"", "history.ndjson", FormatJSONL, false},
+		{"CSV", "data.txt", FormatCSV, false},
+		{"", "history.xlsx", "", true},
+		{"xml", "history.csv", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format+"_"+tc.filename, func(t *testing.T) {
+			format, err := ParseFormat(tc.format, tc.filename)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tc.expectErr, err)
+			}
+			if format != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, format)
+			}
+		})
+	}
+}
+
+func TestParseColumnMapping(t *testing.T) {
+	mapping, err := ParseColumnMapping("ticker=Symbol, company = Company Name,time=Date")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mapping["ticker"] != "Symbol" || mapping["company"] != "Company Name" || mapping["time"] != "Date" {
+		t.Errorf("Unexpected mapping: %v", mapping)
+	}
+	if mapping.source("brokerage") != "brokerage" {
+		t.Error("Unmapped fields should use their own name")
+	}
+
+	if _, err := ParseColumnMapping("price=Close"); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if _, err := ParseColumnMapping("ticker"); err == nil {
+		t.Error("Expected error for malformed pair")
+	}
+}
+
+func TestRead_CSVWithMapping(t *testing.T) {
+	input := "Symbol,Company Name,Firm,Date,New Rating\n" +
+		"AAPL,Apple Inc.,Goldman Sachs,2020-03-15,Buy\n" +
+		"MSFT,Microsoft,UBS,not-a-date,Hold\n" +
+		"TSLA,Tesla\n"
+
+	mapping := ColumnMapping{"ticker": "Symbol", "company": "Company Name", "brokerage": "Firm", "time": "Date", "rating_to": "New Rating"}
+
+	var rows []Row
+	err := Read(strings.NewReader(input), FormatCSV, mapping, func(row Row) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(rows))
+	}
+
+	first := rows[0]
+	if first.Line != 2 || len(first.Errors) != 0 {
+		t.Errorf("Unexpected first row: %+v", first)
+	}
+	if first.Stock.Brokerage != "Goldman Sachs" || first.Stock.RatingTo != "Buy" {
+		t.Errorf("Unexpected mapped stock: %+v", first.Stock)
+	}
+	if !first.Stock.Time.Equal(time.Date(2020, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected time: %v", first.Stock.Time)
+	}
+
+	if rows[1].Line != 3 || len(rows[1].Errors) != 1 || rows[1].Errors[0].Field != "time" {
+		t.Errorf("Expected time error on line 3, got %+v", rows[1])
+	}
+
+	// La fila corta no trae time: no es un error de formato, lo rechaza
+	// después la validación de negocio
+	if len(rows[2].Errors) != 0 || !rows[2].Stock.Time.IsZero() {
+		t.Errorf("Expected short row without format errors, got %+v", rows[2])
+	}
+}
+
+func TestRead_CSVMissingRequiredColumn(t *testing.T) {
+	err := Read(strings.NewReader("ticker,company\nAAPL,Apple\n"), FormatCSV, nil, func(row Row) error {
+		return nil
+	})
+	if err == nil {
+		t.Error("Expected error when the time column is missing")
+	}
+}
+
+func TestRead_JSONL(t *testing.T) {
+	input := `{"sym":"AAPL","company":"Apple Inc.","time":"2021-06-01T10:00:00Z","target_to":180}` + "\n" +
+		"\n" +
+		`{not json}` + "\n" +
+		`{"sym":"MSFT","company":"Microsoft","time":"2021-06-02"}` + "\n"
+
+	var rows []Row
+	err := Read(strings.NewReader(input), FormatJSONL, ColumnMapping{"ticker": "sym"}, func(row Row) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows (blank line skipped), got %d", len(rows))
+	}
+
+	if rows[0].Stock.Ticker != "AAPL" || rows[0].Stock.TargetTo != "180" {
+		t.Errorf("Unexpected first row: %+v", rows[0].Stock)
+	}
+	if rows[1].Line != 3 || len(rows[1].Errors) == 0 {
+		t.Errorf("Expected JSON error on line 3, got %+v", rows[1])
+	}
+	if rows[2].Line != 4 || rows[2].Stock.Ticker != "MSFT" {
+		t.Errorf("Unexpected last row: %+v", rows[2])
+	}
+}